@@ -1,14 +1,21 @@
 package ginx
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/textproto"
 	"reflect"
+	"regexp"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/pkg/errors"
 	"github.com/shrewx/ginx/internal/binding"
+	e2 "github.com/shrewx/ginx/internal/errors"
 	"github.com/shrewx/ginx/internal/utils"
+	"github.com/shrewx/ginx/pkg/statuserror"
 )
 
 // ParameterBinding 快速参数绑定（更细粒度的控制）
@@ -28,14 +35,19 @@ func ParameterBinding(ctx *gin.Context, router interface{}, typeInfo *OperatorTy
 			continue
 		}
 
-		fieldValue := v.Field(field.Index)
-		if !fieldValue.CanSet() {
+		fieldValue, ok := fieldByIndex(v, field.indexPath())
+		if !ok {
 			continue
 		}
 
-		var err error
+		// 多个来源时使用第一个包含该参数的来源
+		if len(field.Sources) > 0 {
+			field.In = field.source(ctx)
+		}
+
 		// 根据参数来源选择对应的绑定函数
 		// 这种switch模式避免了动态分发的开销
+		var err error
 		switch field.In {
 		case "path":
 			err = bindPathParam(ctx, fieldValue, field)
@@ -46,9 +58,13 @@ func ParameterBinding(ctx *gin.Context, router interface{}, typeInfo *OperatorTy
 		case "form":
 			err = bindFormParam(ctx, fieldValue, field)
 		case "multipart":
-			err = bindMultipartParam(ctx, fieldValue, field)
+			if fieldValue.Type() == multipartStreamType {
+				err = bindMultipartStream(ctx, fieldValue, field)
+			} else {
+				err = bindMultipartParam(ctx, fieldValue, field)
+			}
 			if err != nil {
-				return err
+				return bindingError(field, err)
 			}
 			// multipart 字段不保存到 map 中
 			continue
@@ -61,7 +77,7 @@ func ParameterBinding(ctx *gin.Context, router interface{}, typeInfo *OperatorTy
 		}
 
 		if err != nil {
-			return err
+			return bindingError(field, err)
 		}
 
 		if injectParams {
@@ -101,18 +117,64 @@ func ParameterBinding(ctx *gin.Context, router interface{}, typeInfo *OperatorTy
 		ctx.Set(ParsedParamsKey, params)
 	}
 
-	return binding.Validator.ValidateStruct(router)
+	if err := binding.Validator.ValidateStruct(router); err != nil {
+		return validationError(GetLang(ctx), typeInfo, err)
+	}
+	return nil
+}
+
+// fieldByIndex 按索引路径获取字段，路径上为 nil 的结构体指针（如嵌入的参数组）会被初始化
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, v.CanSet()
+}
+
+// source 按优先级返回第一个包含该参数的来源，都不包含时返回默认来源
+func (f FieldInfo) source(ctx *gin.Context) string {
+	for _, in := range f.Sources {
+		if hasParam(ctx, in, f.ParamName) {
+			return in
+		}
+	}
+	return f.In
+}
+
+// hasParam 判断请求的指定来源中是否包含参数
+func hasParam(ctx *gin.Context, in, name string) bool {
+	switch in {
+	case "path":
+		return ctx.Param(name) != ""
+	case "query":
+		return len(ctx.Request.URL.Query()[name]) > 0
+	case "header":
+		return len(ctx.Request.Header[textproto.CanonicalMIMEHeaderKey(name)]) > 0
+	case "cookies":
+		_, err := ctx.Cookie(name)
+		return err == nil
+	}
+	return false
 }
 
 // bindPathParam 绑定路径参数
 func bindPathParam(ctx *gin.Context, fieldValue reflect.Value, field FieldInfo) error {
+	opt := binding.ParseSetOptions(field.StructField)
 	value := ctx.Param(field.ParamName)
-	if value == "" {
+	if value == "" && !opt.HasDefault() {
 		return nil
 	}
 
 	form := map[string][]string{field.ParamName: {value}}
-	opt := binding.ParseSetOptions(field.StructField)
 	_, err := binding.SetFieldByForm(fieldValue, field.StructField, form, field.ParamName, opt)
 	return err
 }
@@ -120,11 +182,11 @@ func bindPathParam(ctx *gin.Context, fieldValue reflect.Value, field FieldInfo)
 // bindQueryParam 绑定查询参数
 func bindQueryParam(ctx *gin.Context, fieldValue reflect.Value, field FieldInfo) error {
 	query := ctx.Request.URL.Query()
-	if len(query[field.ParamName]) == 0 {
+	opt := binding.ParseSetOptions(field.StructField)
+	if len(query[field.ParamName]) == 0 && !opt.HasDefault() {
 		return nil
 	}
 
-	opt := binding.ParseSetOptions(field.StructField)
 	_, err := binding.SetFieldByForm(fieldValue, field.StructField, query, field.ParamName, opt)
 	return err
 }
@@ -134,11 +196,11 @@ func bindHeaderParam(ctx *gin.Context, fieldValue reflect.Value, field FieldInfo
 	// Header 名称需要规范化
 	canonicalKey := textproto.CanonicalMIMEHeaderKey(field.ParamName)
 	header := ctx.Request.Header
-	if len(header[canonicalKey]) == 0 {
+	opt := binding.ParseSetOptions(field.StructField)
+	if len(header[canonicalKey]) == 0 && !opt.HasDefault() {
 		return nil
 	}
 
-	opt := binding.ParseSetOptions(field.StructField)
 	_, err := binding.SetFieldByForm(fieldValue, field.StructField, header, canonicalKey, opt)
 	return err
 }
@@ -157,20 +219,19 @@ func bindFormParam(ctx *gin.Context, fieldValue reflect.Value, field FieldInfo)
 	}
 
 	postForm := ctx.Request.PostForm
-	if len(postForm[field.ParamName]) == 0 {
+	opt := binding.ParseSetOptions(field.StructField)
+	if len(postForm[field.ParamName]) == 0 && !opt.HasDefault() {
 		return nil
 	}
 
-	opt := binding.ParseSetOptions(field.StructField)
 	_, err := binding.SetFieldByForm(fieldValue, field.StructField, postForm, field.ParamName, opt)
 	return err
 }
 
 // bindMultipartParam 绑定多部分表单参数
 func bindMultipartParam(ctx *gin.Context, fieldValue reflect.Value, field FieldInfo) error {
-	// 确保 ParseMultipartForm 已调用
-	const defaultMemory = 32 << 20
-	if err := ctx.Request.ParseMultipartForm(defaultMemory); err != nil {
+	// 确保表单已解析，超出内存大小的部分写入临时文件
+	if err := parseMultipartForm(ctx.Request); err != nil {
 		return err
 	}
 
@@ -194,44 +255,40 @@ func bindURLEncodedParam(ctx *gin.Context, fieldValue reflect.Value, field Field
 	}
 
 	postForm := ctx.Request.PostForm
-	if len(postForm[field.ParamName]) == 0 {
+	opt := binding.ParseSetOptions(field.StructField)
+	if len(postForm[field.ParamName]) == 0 && !opt.HasDefault() {
 		return nil
 	}
 
-	opt := binding.ParseSetOptions(field.StructField)
 	_, err := binding.SetFieldByForm(fieldValue, field.StructField, postForm, field.ParamName, opt)
 	return err
 }
 
-// bindBodyParam 绑定请求体参数
+// bindBodyParam 绑定请求体参数，请求体只读取一次，按 Content-Type 选择解析方式
+// 未设置 Content-Type 时按 JSON 解析，application/merge-patch+json 等 +json 类型同样按 JSON 解析
 func bindBodyParam(ctx *gin.Context, fieldValue reflect.Value, field FieldInfo) error {
-	// 根据Content-Type选择绑定方式
-	contentType := ctx.GetHeader("Content-Type")
-
-	if strings.Contains(contentType, "application/json") {
-		return ctx.ShouldBindJSON(fieldValue.Addr().Interface())
-	} else if strings.Contains(contentType, "application/xml") {
-		return ctx.ShouldBindXML(fieldValue.Addr().Interface())
-	} else if strings.Contains(contentType, "application/x-yaml") {
-		return ctx.ShouldBindYAML(fieldValue.Addr().Interface())
-	} else if strings.Contains(contentType, "application/toml") {
-		return ctx.ShouldBindTOML(fieldValue.Addr().Interface())
+	if err := bindRequestBody(ctx, fieldValue.Addr().Interface()); err != nil {
+		return err
 	}
 
-	// 默认使用JSON绑定
-	return ctx.ShouldBindJSON(fieldValue.Addr().Interface())
+	// Merge Patch 只校验请求中出现的字段
+	if patch, ok := fieldValue.Addr().Interface().(patchValidator); ok {
+		return bodyValidationError(GetLang(ctx), patch.validatePresent(validatorEngine()))
+	}
+	return nil
 }
 
 // bindCookieParam 绑定Cookie参数
 func bindCookieParam(ctx *gin.Context, fieldValue reflect.Value, field FieldInfo) error {
-	cookie, err := ctx.Cookie(field.ParamName)
-	if err != nil {
+	opt := binding.ParseSetOptions(field.StructField)
+	form := map[string][]string{}
+	if cookie, err := ctx.Cookie(field.ParamName); err == nil {
+		form[field.ParamName] = []string{cookie}
+	} else if !opt.HasDefault() {
 		return nil // Cookie不存在不算错误
 	}
 
-	form := map[string][]string{field.ParamName: {cookie}}
-	opt := binding.ParseSetOptions(field.StructField)
-	_, err = binding.SetFieldByForm(fieldValue, field.StructField, form, field.ParamName, opt)
+	_, err := binding.SetFieldByForm(fieldValue, field.StructField, form, field.ParamName, opt)
 	return err
 }
 
@@ -249,3 +306,135 @@ func GetParsedParams(ctx *gin.Context) map[string]interface{} {
 func ResetParsedParams(ctx *gin.Context, params map[string]interface{}) {
 	ctx.Set(ParsedParamsKey, params)
 }
+
+// bindingError 将参数绑定失败转换为字段级别的校验错误，保留原始错误信息用于日志
+func bindingError(field FieldInfo, err error) error {
+	// 已转换的校验错误与不支持的请求体类型
+	var fieldErr *statuserror.ValidationError
+	if errors.As(err, &fieldErr) || errors.Is(err, e2.UnsupportedMediaType) {
+		return err
+	}
+
+	// 读取请求体时超过大小限制
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return fmt.Errorf("%w: %s", e2.RequestEntityTooLarge, err)
+	}
+
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		// body 反序列化后由 gin 执行的校验
+		fieldErrs := make([]statuserror.FieldError, 0, len(validationErrs))
+		for _, e := range validationErrs {
+			fieldErrs = append(fieldErrs, statuserror.FieldError{
+				Field: strings.Join(strings.Split(e.Namespace(), ".")[1:], "."),
+				In:    field.In,
+				Rule:  e.Tag(),
+				Param: e.Param(),
+			})
+		}
+		return newValidationError(fieldErrs...)
+	}
+
+	name := field.ParamName
+	var typeErr *json.UnmarshalTypeError
+	if field.In == "body" && errors.As(err, &typeErr) && typeErr.Field != "" {
+		name = typeErr.Field
+	}
+	return fmt.Errorf("%w: %s", newValidationError(statuserror.FieldError{
+		Field: name,
+		In:    field.In,
+		Rule:  statuserror.RuleType,
+	}), err)
+}
+
+// validationError 将 validator 的校验错误转换为字段级别的校验错误，错误信息按请求语言翻译
+func validationError(lang string, typeInfo *OperatorTypeInfo, err error) error {
+	return translateValidationError(lang, err, typeInfo.fieldError)
+}
+
+// bodyValidationError 请求体中字段的校验错误，字段为 JSON 路径
+func bodyValidationError(lang string, err error) error {
+	return translateValidationError(lang, err, func(e validator.FieldError) statuserror.FieldError {
+		return statuserror.FieldError{
+			Field: strings.Join(strings.Split(e.Namespace(), ".")[1:], "."),
+			In:    "body",
+			Rule:  e.Tag(),
+			Param: e.Param(),
+		}
+	})
+}
+
+func translateValidationError(lang string, err error, fieldError func(e validator.FieldError) statuserror.FieldError) error {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return err
+	}
+
+	fieldErrs := make([]statuserror.FieldError, 0, len(validationErrs))
+	for _, e := range validationErrs {
+		fieldErr := fieldError(e)
+		// 翻译中的字段名为最后一级字段，替换为完整的参数路径
+		if msg := binding.Translate(lang, e); strings.HasPrefix(msg, e.Field()) {
+			fieldErr.Message = fieldErr.Field + strings.TrimPrefix(msg, e.Field())
+		} else {
+			fieldErr.Message = msg
+		}
+		fieldErrs = append(fieldErrs, fieldErr)
+	}
+	return newValidationError(fieldErrs...)
+}
+
+func newValidationError(errs ...statuserror.FieldError) *statuserror.ValidationError {
+	return statuserror.NewValidationError(e2.BadRequest.Key(), e2.BadRequest.Code(), errs...)
+}
+
+var reFieldIndex = regexp.MustCompile(`\[[^\]]*\]`)
+
+// fieldError 根据校验失败字段的路径找到对应的参数，参数名使用 name 或 json 标签，body 中的字段返回 JSON 路径
+func (info *OperatorTypeInfo) fieldError(e validator.FieldError) statuserror.FieldError {
+	// 去掉命名空间中的结构体名，两者层级一一对应
+	structPath := strings.Split(e.StructNamespace(), ".")[1:]
+	namePath := strings.Split(e.Namespace(), ".")[1:]
+
+	fieldErr := statuserror.FieldError{
+		Field: strings.Join(namePath, "."),
+		Rule:  e.Tag(),
+		Param: e.Param(),
+	}
+
+	for i := len(structPath); i > 0; i-- {
+		field, ok := info.paramField(reFieldIndex.ReplaceAllString(strings.Join(structPath[:i], "."), ""))
+		if !ok {
+			continue
+		}
+
+		fieldErr.In = field.In
+		if len(field.Sources) > 0 {
+			// 多个来源的参数返回所有来源
+			fieldErr.In = strings.Join(field.Sources, ",")
+		}
+		rest := namePath[i:]
+		if field.In == "body" && len(rest) > 0 {
+			fieldErr.Field = strings.Join(rest, ".")
+			break
+		}
+
+		name := field.ParamName + reFieldIndex.FindString(namePath[i-1])
+		fieldErr.Field = strings.Join(append([]string{name}, rest...), ".")
+		break
+	}
+	return fieldErr
+}
+
+// paramField 按字段路径查找参数字段
+func (info *OperatorTypeInfo) paramField(path string) (FieldInfo, bool) {
+	for _, fields := range [][]FieldInfo{info.Fields, info.NoLogFields} {
+		for _, field := range fields {
+			if field.In != "" && field.Path == path {
+				return field, true
+			}
+		}
+	}
+	return FieldInfo{}, false
+}
@@ -2,6 +2,7 @@ package ginx
 
 import (
 	"bytes"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
@@ -9,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/shrewx/ginx/pkg/statuserror"
 	"github.com/stretchr/testify/require"
 )
 
@@ -67,6 +69,179 @@ func TestParameterBindingWithInjection(t *testing.T) {
 	require.Equal(t, "cookie-value", cookieParams["session"])
 }
 
+type testPagination struct {
+	Page int `in:"query" name:"page,default=1" validate:"gte=1"`
+	Size int `in:"query" name:"size,default=20" validate:"lte=100"`
+}
+
+type testSorting struct {
+	Sort string `in:"query" name:"sort"`
+}
+
+func TestParameterBindingEmbeddedStruct(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	type listRouter struct {
+		MethodGet
+		testPagination
+		testSorting
+		Name string `in:"query" name:"name"`
+	}
+
+	bind := func(rawQuery string) (*listRouter, error) {
+		router := &listRouter{}
+		ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+		ctx.Request = httptest.NewRequest(http.MethodGet, "/users?"+rawQuery, nil)
+		return router, ParameterBinding(ctx, router, parseOperatorType(reflect.TypeOf(router)))
+	}
+
+	router, err := bind("size=50&sort=-created_at,name&name=neo")
+	require.NoError(t, err)
+	require.Equal(t, 1, router.Page)
+	require.Equal(t, 50, router.Size)
+	require.Equal(t, "-created_at,name", router.Sort)
+	require.Equal(t, "neo", router.Name)
+
+	_, err = bind("size=500")
+	require.Error(t, err)
+}
+
+type TestTenantHeader struct {
+	TenantID string `in:"header" name:"X-Tenant-Id" validate:"required"`
+}
+
+type testTrace struct {
+	TraceID string `in:"header" name:"X-Trace-Id"`
+}
+
+func TestParameterBindingNestedStruct(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	type listRouter struct {
+		*TestTenantHeader
+		Trace *testTrace
+		Page  testPagination
+		Name  string `in:"query" name:"name"`
+	}
+
+	bind := func(rawQuery string, header http.Header) (*listRouter, error) {
+		router := &listRouter{}
+		ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+		ctx.Request = httptest.NewRequest(http.MethodGet, "/orders?"+rawQuery, nil)
+		for key, values := range header {
+			ctx.Request.Header[key] = values
+		}
+		return router, ParameterBinding(ctx, router, parseOperatorType(reflect.TypeOf(router)))
+	}
+
+	router, err := bind("page=2&name=neo", http.Header{"X-Tenant-Id": {"t1"}, "X-Trace-Id": {"abc"}})
+	require.NoError(t, err)
+	require.Equal(t, listRouter{
+		TestTenantHeader: &TestTenantHeader{TenantID: "t1"},
+		Trace:            &testTrace{TraceID: "abc"},
+		Page:             testPagination{Page: 2, Size: 20},
+		Name:             "neo",
+	}, *router)
+
+	// 嵌套参数组中的字段同样进行校验
+	_, err = bind("size=500", nil)
+	var validationErr *statuserror.ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	require.ElementsMatch(t, []statuserror.FieldError{
+		{Field: "X-Tenant-Id", In: "header", Rule: "required", Message: "X-Tenant-Id为必填字段"},
+		{Field: "size", In: "query", Rule: "lte", Param: "100", Message: "size必须小于或等于100"},
+	}, validationErr.Errors)
+}
+
+func TestParameterBindingValidationError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	type item struct {
+		Name string `json:"name" validate:"required"`
+	}
+	type payload struct {
+		Title string `json:"title" validate:"required,min=2"`
+		Items []item `json:"items" validate:"dive"`
+	}
+	type createRouter struct {
+		testPagination
+		Org  string   `in:"path" name:"org" validate:"required"`
+		Tags []string `in:"query" name:"tags" validate:"dive,oneof=a b"`
+		Body payload  `in:"body"`
+	}
+
+	bind := func(rawQuery, body string) error {
+		router := &createRouter{}
+		ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+		ctx.Request = httptest.NewRequest(http.MethodPost, "/users?"+rawQuery, bytes.NewBufferString(body))
+		ctx.Request.Header.Set("Content-Type", "application/json")
+		return ParameterBinding(ctx, router, parseOperatorType(reflect.TypeOf(router)))
+	}
+
+	err := bind("size=500&tags=a&tags=c", `{"title":"x","items":[{"name":"a"},{}]}`)
+	var validationErr *statuserror.ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	require.Equal(t, int64(40000000001), validationErr.Code())
+	require.ElementsMatch(t, []statuserror.FieldError{
+		{Field: "size", In: "query", Rule: "lte", Param: "100", Message: "size必须小于或等于100"},
+		{Field: "org", In: "path", Rule: "required", Message: "org为必填字段"},
+		{Field: "tags[1]", In: "query", Rule: "oneof", Param: "a b", Message: "tags[1]必须是[a b]中的一个"},
+		{Field: "title", In: "body", Rule: "min", Param: "2", Message: "title长度必须至少为2个字符"},
+		{Field: "items[1].name", In: "body", Rule: "required", Message: "items[1].name为必填字段"},
+	}, validationErr.Errors)
+
+	err = bind("size=abc", `{}`)
+	require.True(t, errors.As(err, &validationErr))
+	require.Equal(t, []statuserror.FieldError{{Field: "size", In: "query", Rule: statuserror.RuleType}}, validationErr.Errors)
+
+	err = bind("", `{"title":1}`)
+	require.True(t, errors.As(err, &validationErr))
+	require.Equal(t, []statuserror.FieldError{{Field: "title", In: "body", Rule: statuserror.RuleType}}, validationErr.Errors)
+}
+
+func TestParameterBindingMultipleSources(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	type tenantRouter struct {
+		Tenant string `in:"header,query" name:"tenant"`
+		Token  string `in:"cookies,header" name:"token" validate:"required"`
+		Size   int    `in:"query,header" name:"size,default=10"`
+	}
+
+	bind := func(rawQuery string, header http.Header) (*tenantRouter, error) {
+		router := &tenantRouter{}
+		ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+		ctx.Request = httptest.NewRequest(http.MethodGet, "/tenants?"+rawQuery, nil)
+		for key, values := range header {
+			ctx.Request.Header[key] = values
+		}
+		return router, ParameterBinding(ctx, router, parseOperatorType(reflect.TypeOf(router)))
+	}
+
+	router, err := bind("tenant=q", http.Header{"Token": {"h"}})
+	require.NoError(t, err)
+	require.Equal(t, tenantRouter{Tenant: "q", Token: "h", Size: 10}, *router)
+
+	// 多个来源都包含参数时按声明的顺序优先
+	router, err = bind("tenant=q&size=20", http.Header{"Tenant": {"h"}, "Size": {"30"}, "Cookie": {"token=c"}, "Token": {"h"}})
+	require.NoError(t, err)
+	require.Equal(t, tenantRouter{Tenant: "h", Token: "c", Size: 20}, *router)
+
+	_, err = bind("", nil)
+	var validationErr *statuserror.ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	require.Equal(t, []statuserror.FieldError{
+		{Field: "token", In: "cookies,header", Rule: "required", Message: "token为必填字段"},
+	}, validationErr.Errors)
+
+	require.Panics(t, func() {
+		type invalidRouter struct {
+			Name string `in:"query,body" name:"name"`
+		}
+		parseOperatorType(reflect.TypeOf(&invalidRouter{}))
+	})
+}
+
 func TestParameterBindingBodyInjection(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -0,0 +1,67 @@
+package ginx
+
+import (
+	"net/http"
+	"os"
+	"runtime"
+
+	"github.com/gin-gonic/gin"
+	e2 "github.com/shrewx/ginx/internal/errors"
+	"github.com/shrewx/ginx/pkg/conf"
+	"github.com/shrewx/ginx/pkg/logx"
+)
+
+// defaultMultipartMemory multipart 表单默认保存在内存中的大小
+const defaultMultipartMemory = 32 << 20
+
+var (
+	// maxBodySize 服务的请求体大小上限，0 表示不限制
+	maxBodySize int64
+	// multipartMemory multipart 表单保存在内存中的大小
+	multipartMemory int64 = defaultMultipartMemory
+	// multipartTempDir multipart 临时文件目录，为空时使用系统临时目录
+	multipartTempDir string
+)
+
+func loadBodyLimit(config *conf.Server) {
+	maxBodySize = config.MaxBodySize
+
+	multipartMemory = defaultMultipartMemory
+	if config.MultipartMemory > 0 {
+		multipartMemory = config.MultipartMemory
+	}
+
+	multipartTempDir = config.MultipartTempDir
+	if multipartTempDir != "" && !fileHeaderSettable {
+		logx.Warnf("multipart_temp_dir is not supported by %s, temp files are written to %s", runtime.Version(), os.TempDir())
+	}
+}
+
+// bodyLimit 获取操作符的请求体大小上限，未声明时使用服务配置
+func bodyLimit(operator interface{}) int64 {
+	if describer, ok := operator.(BodyLimitDescriber); ok && describer.BodyLimit() != 0 {
+		return describer.BodyLimit()
+	}
+	return maxBodySize
+}
+
+// limitRequestBody 限制请求体大小，Content-Length 已超出时直接返回错误，否则在读取超出时返回 http.MaxBytesError
+func limitRequestBody(ctx *gin.Context, operator interface{}) error {
+	limit := bodyLimit(operator)
+	if limit <= 0 || ctx.Request.Body == nil || ctx.Request.Body == http.NoBody {
+		return nil
+	}
+
+	if ctx.Request.ContentLength > limit {
+		return e2.RequestEntityTooLarge
+	}
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, limit)
+	return nil
+}
+
+// cleanupMultipartForm 删除解析 multipart 表单时写入的临时文件
+func cleanupMultipartForm(ctx *gin.Context) {
+	if ctx.Request.MultipartForm != nil {
+		_ = ctx.Request.MultipartForm.RemoveAll()
+	}
+}
@@ -0,0 +1,189 @@
+package ginx
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shrewx/ginx/pkg/conf"
+	"github.com/shrewx/ginx/pkg/i18nx"
+	"github.com/shrewx/ginx/pkg/statuserror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBodyLimitOperator 限制请求体大小的操作符
+type TestBodyLimitOperator struct {
+	MethodPost
+	Data TestOperatorBody `in:"body"`
+}
+
+func (t *TestBodyLimitOperator) Path() string     { return "/api/limit" }
+func (t *TestBodyLimitOperator) BodyLimit() int64 { return 32 }
+
+func (t *TestBodyLimitOperator) Output(ctx *gin.Context) (interface{}, error) {
+	return t.Data, nil
+}
+
+func TestGinHandleFuncWrapper_BodyLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	i18nx.Load(&conf.I18N{Langs: []string{"zh", "en"}})
+
+	engine := gin.New()
+	engine.POST("/api/limit", ginHandleFuncWrapper(&TestBodyLimitOperator{}))
+	engine.POST("/api/posts", ginHandleFuncWrapper(&TestPostOperator{}))
+
+	serve := func(path, lang string, body io.Reader) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, path, body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(CurrentLangHeader(), lang)
+		engine.ServeHTTP(w, req)
+		return w
+	}
+	largeBody := `{"title":"` + strings.Repeat("a", 64) + `"}`
+
+	t.Run("within limit", func(t *testing.T) {
+		w := serve("/api/limit", "zh", strings.NewReader(`{"title":"ok"}`))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("content length exceeds limit", func(t *testing.T) {
+		for lang, message := range map[string]string{"zh": "请求体过大", "en": "request entity too large"} {
+			w := serve("/api/limit", lang, strings.NewReader(largeBody))
+			require.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+			var resp statuserror.StatusErr
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, int64(41300000001), resp.Code())
+			assert.Equal(t, message, resp.Message)
+		}
+	})
+
+	t.Run("chunked body exceeds limit", func(t *testing.T) {
+		// 未知长度的请求体在读取超出时返回 413
+		w := serve("/api/limit", "en", io.MultiReader(strings.NewReader(largeBody)))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+
+	t.Run("server limit", func(t *testing.T) {
+		maxBodySize = 32
+		defer func() { maxBodySize = 0 }()
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, serve("/api/posts", "zh", strings.NewReader(largeBody)).Code)
+		assert.Equal(t, http.StatusOK, serve("/api/posts", "zh", strings.NewReader(`{"title":"ok"}`)).Code)
+	})
+}
+
+// TestUploadTempDirOperator 上传文件并返回处理时临时目录中的文件数量
+type TestUploadTempDirOperator struct {
+	MethodPost
+	Name string                `in:"multipart" name:"name"`
+	File *multipart.FileHeader `in:"multipart" name:"file"`
+}
+
+func (t *TestUploadTempDirOperator) Path() string { return "/api/upload" }
+
+func (t *TestUploadTempDirOperator) Output(ctx *gin.Context) (interface{}, error) {
+	file, err := t.File.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(multipartTempDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return map[string]interface{}{"name": t.Name, "content": string(content), "tempFiles": len(entries)}, nil
+}
+
+func TestGinHandleFuncWrapper_MultipartCleanup(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	defer func() { multipartMemory, multipartTempDir = defaultMultipartMemory, "" }()
+
+	engine := gin.New()
+	engine.POST("/api/upload", ginHandleFuncWrapper(&TestUploadTempDirOperator{}))
+
+	content := strings.Repeat("a", 1024)
+	tests := []struct {
+		name          string
+		memory        int64
+		wantTempFiles int
+	}{
+		{name: "in memory", memory: defaultMultipartMemory, wantTempFiles: 0},
+		{name: "exceeds memory", memory: 1, wantTempFiles: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 临时目录不存在时自动创建
+			multipartMemory, multipartTempDir = tt.memory, filepath.Join(t.TempDir(), "multipart")
+
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			require.NoError(t, writer.WriteField("name", "test"))
+			part, err := writer.CreateFormFile("file", "test.txt")
+			require.NoError(t, err)
+			_, err = part.Write([]byte(content))
+			require.NoError(t, err)
+			require.NoError(t, writer.Close())
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/upload", body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+			engine.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			var resp struct {
+				Name      string `json:"name"`
+				Content   string `json:"content"`
+				TempFiles int    `json:"tempFiles"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, "test", resp.Name)
+			assert.Equal(t, content, resp.Content)
+			assert.Equal(t, tt.wantTempFiles, resp.TempFiles)
+
+			// 超出内存大小的文件写入配置的临时目录，处理结束后删除
+			entries, err := os.ReadDir(multipartTempDir)
+			if !os.IsNotExist(err) {
+				require.NoError(t, err)
+			}
+			assert.Empty(t, entries)
+		})
+	}
+}
+
+func TestReadMultipartForm_Error(t *testing.T) {
+	dir := t.TempDir()
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", "test.txt")
+	require.NoError(t, err)
+	_, err = part.Write(bytes.Repeat([]byte("a"), 1024))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	// 请求体不完整时删除已写入的临时文件
+	truncated := body.Bytes()[:body.Len()-10]
+	_, err = readMultipartForm(multipart.NewReader(bytes.NewReader(truncated), writer.Boundary()), 1, dir)
+	assert.Error(t, err)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
@@ -4,16 +4,21 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/sirupsen/logrus"
 	"io"
 	"mime/multipart"
+	"net"
 	"net/http"
 	"net/textproto"
 	"net/url"
 	"reflect"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.opentelemetry.io/otel"
@@ -21,7 +26,6 @@ import (
 
 	"github.com/go-courier/reflectx"
 	"github.com/shrewx/ginx/pkg/statuserror"
-	"github.com/spf13/cast"
 )
 
 const (
@@ -49,6 +53,14 @@ type MultipartFile struct {
 
 // Invoke 执行请求（核心方法）
 func (c *Client) Invoke(ctx context.Context, req interface{}, config RequestConfig) (ResponseBind, error) {
+	// 1. 通过服务发现选择实例
+	if config.ServiceName != "" {
+		done, err := resolveServiceInstance(ctx, &config)
+		if err != nil {
+			return nil, err
+		}
+		defer done()
+	}
 
 	// 2. 构建 HTTP 请求
 	// 如果 req 已经是 *http.Request，直接使用；否则通过 NewRequest 构建
@@ -56,6 +68,11 @@ func (c *Client) Invoke(ctx context.Context, req interface{}, config RequestConf
 	var err error
 	if httpRequest, ok := req.(*http.Request); ok {
 		httpReq = httpRequest
+		if config.ServiceName != "" {
+			httpReq.URL.Scheme = config.Schema
+			httpReq.URL.Host = requestHost(config)
+			httpReq.Host = ""
+		}
 	} else {
 		httpReq, err = NewRequest(ctx, req, config)
 		if err != nil {
@@ -63,26 +80,97 @@ func (c *Client) Invoke(ctx context.Context, req interface{}, config RequestConf
 		}
 	}
 
-	return InvokeRequest(ctx, httpReq, config.Timeout, config.Transport)
+	return invokeRequest(ctx, httpReq, config)
+}
+
+func InvokeRequest(ctx context.Context, httpReq *http.Request, timeout *time.Duration, transport http.RoundTripper) (ResponseBind, error) {
+	return invokeRequest(ctx, httpReq, RequestConfig{Timeout: timeout, Transport: transport})
 }
 
-func InvokeRequest(ctx context.Context, httpReq *http.Request, timeout *time.Duration, transport *http.Transport) (ResponseBind, error) {
-	// 1. 获取或创建 HTTP Client
-	httpClient := getHTTPClient(timeout, transport)
+// invokeRequest 执行请求
+// Timeout 只限制到收到响应头为止，响应体的读取由调用方的 ctx 控制，避免截断流式响应
+func invokeRequest(ctx context.Context, httpReq *http.Request, config RequestConfig) (ResponseBind, error) {
+	// 1. 获取复用的 HTTP Client
+	httpClient := getHTTPClient(config.Transport, config.MaxConnsPerHost)
 
 	// 2. 注入 OpenTelemetry 追踪信息
 	if ctxReq, ok := ctx.Value(RequestContextKey).(*http.Request); ok {
 		otel.GetTextMapPropagator().Inject(ctxReq.Context(), propagation.HeaderCarrier(httpReq.Header))
 	}
 
-	// 3. 执行请求
-	resp, err := httpClient.Do(httpReq)
+	// 3. 通过 context 控制超时
+	timeout := DefaultTimeout
+	if config.Timeout != nil {
+		timeout = *config.Timeout
+	}
+	cancelCtx, cancelCause := context.WithCancelCause(ctx)
+	cancel := func() { cancelCause(nil) }
+	ctx = cancelCtx
+	var timedOut atomic.Bool
+	var timer *time.Timer
+	if timeout > 0 {
+		ctx = &timeoutContext{Context: cancelCtx, deadline: time.Now().Add(timeout)}
+		timer = time.AfterFunc(timeout, func() {
+			timedOut.Store(true)
+			cancelCause(context.DeadlineExceeded)
+		})
+	}
+	httpReq = httpReq.WithContext(ctx)
+
+	// 4. 经过拦截器执行请求（按配置重试与熔断）
+	handler := chainInterceptors(func(ctx context.Context, req *http.Request) (*http.Response, error) {
+		return doWithRetry(ctx, httpClient, req, config)
+	}, globalClientInterceptors(), config.Interceptors)
+	start := time.Now()
+	resp, err := handler(ctx, httpReq)
+	if timer != nil {
+		timer.Stop()
+	}
 	if err != nil {
+		cancel()
+		if timedOut.Load() {
+			err = fmt.Errorf("request timeout after %s: %w", timeout, context.DeadlineExceeded)
+		}
 		logrus.Errorf("http client error: %v", err)
 		return nil, err
 	}
 
-	return &Result{Response: resp}, nil
+	// 响应体关闭时释放 context
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return &Result{Response: resp, Latency: time.Since(start)}, nil
+}
+
+// timeoutContext 请求超时的 context，Deadline 返回超时时间，超时取消后 Err 返回 context.DeadlineExceeded
+// 与 context.WithTimeout 不同，收到响应头后停止计时，响应体的读取不受超时限制
+type timeoutContext struct {
+	context.Context
+	deadline time.Time
+}
+
+func (c *timeoutContext) Deadline() (time.Time, bool) {
+	if deadline, ok := c.Context.Deadline(); ok && deadline.Before(c.deadline) {
+		return deadline, true
+	}
+	return c.deadline, true
+}
+
+func (c *timeoutContext) Err() error {
+	err := c.Context.Err()
+	if err != nil && errors.Is(context.Cause(c.Context), context.DeadlineExceeded) {
+		return context.DeadlineExceeded
+	}
+	return err
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
 }
 
 func NewRequest(ctx context.Context, req interface{}, config RequestConfig) (*http.Request, error) {
@@ -100,14 +188,9 @@ func NewRequest(ctx context.Context, req interface{}, config RequestConfig) (*ht
 		path = pathDescriber.Path()
 	}
 
-	host := config.Host
-	if config.Port != 0 {
-		host = fmt.Sprintf("%s:%d", config.Host, config.Port)
-	}
-
 	u := url.URL{
 		Scheme: config.Schema,
-		Host:   host,
+		Host:   requestHost(config),
 		Path:   path,
 	}
 
@@ -121,6 +204,13 @@ func NewRequest(ctx context.Context, req interface{}, config RequestConfig) (*ht
 	return request, nil
 }
 
+func requestHost(config RequestConfig) string {
+	if config.Port != 0 {
+		return net.JoinHostPort(config.Host, strconv.Itoa(int(config.Port)))
+	}
+	return config.Host
+}
+
 // newRequestWithContext 根据结构体字段标签构建HTTP请求
 // 这是客户端的核心函数，负责解析结构体字段的in标签，
 // 并将字段值绑定到HTTP请求的不同部分（header、query、body等）
@@ -146,10 +236,12 @@ func newRequestWithContext(ctx context.Context, method string, rawUrl string, v
 	}
 
 	// 初始化各种参数容器
-	query := url.Values{}               // 查询参数
-	cookies := url.Values{}             // Cookie参数
-	body := new(bytes.Buffer)           // 请求体
-	writer := multipart.NewWriter(body) // multipart表单写入器
+	query := url.Values{}         // 查询参数
+	form := url.Values{}          // URL编码表单参数
+	cookies := url.Values{}       // Cookie参数
+	params := map[string]string{} // 路径参数
+	body := new(bytes.Buffer)     // 请求体
+	var parts []formPart          // multipart表单的各部分
 
 	// 获取反射值和类型信息
 	rv, ok := v.(reflect.Value)
@@ -157,97 +249,121 @@ func newRequestWithContext(ctx context.Context, method string, rawUrl string, v
 		rv = reflect.ValueOf(v)
 	}
 	rv = reflectx.Indirect(rv)
-	rt := reflectx.Deref(reflect.TypeOf(v))
-
-	var closeWriter bool // 标记是否需要关闭multipart写入器
-
-	// 遍历结构体字段，根据in标签进行参数绑定
-	for i := 0; i < rt.NumField(); i++ {
-		field := rt.Field(i)
-		if in, ok := field.Tag.Lookup("in"); ok {
-			// 确定参数名称：优先使用name标签，其次json标签，最后使用小写字段名
-			name := field.Tag.Get("name")
-			if name == "" {
-				name = field.Tag.Get("json")
+
+	var (
+		isMultipart bool // 标记是否为multipart表单
+		streaming   bool // 包含文件时流式写入请求体
+	)
+
+	// 遍历结构体字段（包括嵌入或嵌套的参数组），根据in标签进行参数绑定
+	for _, f := range requestFields(rv) {
+		field, fieldValue := f.StructField, f.Value
+		// 多个来源时使用优先级最高的来源，与服务端绑定的顺序一致
+		in, _, _ := strings.Cut(field.Tag.Get("in"), ",")
+		// 确定参数名称：优先使用name标签，其次json标签，最后使用小写字段名
+		name := parseParamName(field)
+
+		// 根据in标签值进行不同的参数绑定
+		switch in {
+		case Body: // JSON请求体
+			if (fieldValue.Kind() == reflect.Ptr || fieldValue.Kind() == reflect.Interface) && fieldValue.IsNil() {
+				continue
 			}
-			if name == "" {
-				name = strings.ToLower(field.Name[:1]) + field.Name[1:]
+			data, err := json.Marshal(fieldValue.Interface())
+			if err != nil {
+				return nil, err
 			}
-
-			// 根据in标签值进行不同的参数绑定
-			switch in {
-			case Head: // HTTP头部
-				header[textproto.CanonicalMIMEHeaderKey(name)] = append(header[textproto.CanonicalMIMEHeaderKey(name)], cast.ToString(rv.Field(i).Interface()))
-			case Cookies: // Cookie
-				cookies[name] = append(cookies[name], cast.ToString(rv.Field(i).Interface()))
-			case Query: // URL查询参数
-				query.Add(name, cast.ToString(rv.Field(i).Interface()))
-			case Path: // 路径参数（替换URL中的占位符）
-				rawUrl = strings.Replace(rawUrl, fmt.Sprintf(":%s", name), cast.ToString(rv.Field(i).Interface()), -1)
-			case Body: // JSON请求体
-				data, _ := json.Marshal(rv.Field(i).Interface())
-				body.Write(data)
-				header.Set("Content-Type", MineApplicationJson)
-			case UrlEncode: // URL编码表单数据
-				query.Add(name, rv.Field(i).String())
-				header.Set("Content-Type", MineApplicationUrlencoded)
-			case Form, Multipart: // 表单或multipart数据
-				switch typ := rv.Field(i).Interface().(type) {
-				case MultipartFile: // 单个文件上传
-					part, err := writer.CreateFormFile(name, typ.Filename)
-					if err != nil {
-						return nil, err
-					}
-
-					if _, err := io.Copy(part, typ.Data); err != nil {
-						return nil, err
-					}
-				case []MultipartFile: // 多文件上传
-					for _, f := range typ {
-						part, err := writer.CreateFormFile(name, f.Filename)
-						if err != nil {
-							return nil, err
-						}
-						if _, err := io.Copy(part, f.Data); err != nil {
-							return nil, err
-						}
+			body.Write(data)
+			// mime 标签指定请求体类型，如 application/merge-patch+json
+			contentType := field.Tag.Get("mime")
+			if contentType == "" {
+				contentType = MineApplicationJson
+			}
+			header.Set("Content-Type", contentType)
+			continue
+		case Form, Multipart: // 表单或multipart数据
+			isMultipart = true
+
+			switch typ := fieldValue.Interface().(type) {
+			case MultipartFile: // 单个文件上传，未设置 Data 时不发送
+				if typ.Data != nil {
+					parts = append(parts, formFilePart(name, typ))
+					streaming = true
+				}
+				continue
+			case []MultipartFile: // 多文件上传
+				for _, f := range typ {
+					if f.Data != nil {
+						parts = append(parts, formFilePart(name, f))
+						streaming = true
 					}
-				default: // 普通表单字段
-					writer.WriteField(name, cast.ToString(rv.Field(i).Interface()))
 				}
-				header.Set("Content-Type", writer.FormDataContentType())
-				closeWriter = true // 标记需要关闭写入器
+				continue
 			}
+		case UrlEncode: // URL编码表单数据
+			header.Set("Content-Type", MineApplicationUrlencoded)
 		}
-	}
 
-	// 关闭multipart写入器（关键！否则内容长度会不匹配导致panic）
-	if closeWriter {
-		//  necessary !!!!  otherwise the length of the content is shorter than the length of the body !!!! panic
-		if writer != nil {
-			err := writer.Close()
-			if err != nil {
-				return nil, err
+		values, ok, err := encodeParamValues(fieldValue, field)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		switch in {
+		case Head: // HTTP头部
+			key := textproto.CanonicalMIMEHeaderKey(name)
+			header[key] = append(header[key], values...)
+		case Cookies: // Cookie
+			cookies[name] = append(cookies[name], values...)
+		case Query: // URL查询参数
+			query[name] = append(query[name], values...)
+		case Path: // 路径参数（替换URL中的占位符）
+			params[name] = strings.Join(values, ",")
+		case UrlEncode: // URL编码表单数据
+			form[name] = append(form[name], values...)
+		case Form, Multipart: // 普通表单字段
+			for _, value := range values {
+				parts = append(parts, formFieldPart(name, value))
 			}
 		}
 	}
 
-	// 处理查询参数：如果是URL编码格式，放入body；否则放入URL
-	var rawQuery string
-	if len(query) > 0 {
-		if header.Get("Content-Type") == MineApplicationUrlencoded {
-			body = bytes.NewBufferString(query.Encode())
+	var reqBody io.Reader = body
+	if isMultipart {
+		var contentType string
+		if streaming {
+			reqBody, contentType = streamMultipart(parts)
 		} else {
-			rawQuery = query.Encode()
+			var err error
+			if body, contentType, err = bufferMultipart(parts); err != nil {
+				return nil, err
+			}
+			reqBody = body
 		}
+		header.Set("Content-Type", contentType)
+	}
+
+	// URL编码表单放入body
+	if len(form) > 0 {
+		reqBody = bytes.NewBufferString(form.Encode())
 	}
 
 	// 构建最终的HTTP请求
-	req, err := http.NewRequestWithContext(ctx, method, rawUrl, body)
+	req, err := http.NewRequestWithContext(ctx, method, rawUrl, reqBody)
 	if err != nil {
+		// 结束流式写入
+		if closer, ok := reqBody.(io.Closer); ok {
+			_ = closer.Close()
+		}
 		return nil, err
 	}
-	req.URL.RawQuery = rawQuery
+	if len(params) > 0 {
+		setPathParams(req.URL, params)
+	}
+	req.URL.RawQuery = query.Encode()
 	req.Header = header
 
 	// 添加Cookie（按名称排序以确保一致性）
@@ -274,8 +390,140 @@ func newRequestWithContext(ctx context.Context, method string, rawUrl string, v
 	return req, nil
 }
 
+// requestField 请求结构体中声明了 in 标签的字段
+type requestField struct {
+	reflect.StructField
+	Value reflect.Value
+}
+
+// requestFields 收集声明了 in 标签的导出字段，没有 in 标签的结构体字段（嵌入或嵌套的参数组）递归展开，nil 指针跳过
+func requestFields(rv reflect.Value) []requestField {
+	var fields []requestField
+	for i := 0; i < rv.NumField(); i++ {
+		field := rv.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		fieldValue := rv.Field(i)
+		if _, ok := field.Tag.Lookup("in"); ok {
+			fields = append(fields, requestField{StructField: field, Value: fieldValue})
+			continue
+		}
+
+		for fieldValue.Kind() == reflect.Ptr && !fieldValue.IsNil() {
+			fieldValue = fieldValue.Elem()
+		}
+		if fieldValue.Kind() == reflect.Struct {
+			fields = append(fields, requestFields(fieldValue)...)
+		}
+	}
+	return fields
+}
+
+// formPart multipart 表单的一个部分
+type formPart func(writer *multipart.Writer) error
+
+func formFieldPart(name, value string) formPart {
+	return func(writer *multipart.Writer) error {
+		return writer.WriteField(name, value)
+	}
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// formFilePart 写入一个 multipart 文件，Header 中声明的 Content-Type 会保留
+func formFilePart(name string, file MultipartFile) formPart {
+	return func(writer *multipart.Writer) error {
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+			quoteEscaper.Replace(name), quoteEscaper.Replace(file.Filename)))
+		h.Set("Content-Type", "application/octet-stream")
+		if contentType := file.Header.Get("Content-Type"); contentType != "" {
+			h.Set("Content-Type", contentType)
+		}
+		part, err := writer.CreatePart(h)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(part, file.Data)
+		return err
+	}
+}
+
+// writeMultipart 依次写入各部分并关闭写入器，关闭时写入结束边界
+func writeMultipart(writer *multipart.Writer, parts []formPart) error {
+	for _, part := range parts {
+		if err := part(writer); err != nil {
+			return err
+		}
+	}
+	return writer.Close()
+}
+
+// bufferMultipart 将不含文件的 multipart 表单写入内存，请求可重试
+func bufferMultipart(parts []formPart) (*bytes.Buffer, string, error) {
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	if err := writeMultipart(writer, parts); err != nil {
+		return nil, "", err
+	}
+	return body, writer.FormDataContentType(), nil
+}
+
+// streamMultipart 通过 io.Pipe 在发送请求时写入 multipart 表单，文件内容不会缓存在内存中
+// 写入失败时请求随之失败，流式请求体无法重放，不会进行重试
+func streamMultipart(parts []formPart) (io.ReadCloser, string) {
+	pr, pw := io.Pipe()
+	body := &multipartBody{pr: pr, pw: pw, writer: multipart.NewWriter(pw), parts: parts}
+	return body, body.writer.FormDataContentType()
+}
+
+// multipartBody 首次读取时才启动写入的协程，熔断或拦截器未发出请求时不会遗留协程
+type multipartBody struct {
+	pr     *io.PipeReader
+	pw     *io.PipeWriter
+	writer *multipart.Writer
+	parts  []formPart
+	once   sync.Once
+}
+
+func (b *multipartBody) Read(p []byte) (int, error) {
+	b.once.Do(func() {
+		go func() {
+			b.pw.CloseWithError(writeMultipart(b.writer, b.parts))
+		}()
+	})
+	return b.pr.Read(p)
+}
+
+func (b *multipartBody) Close() error {
+	// 未读取时不再启动写入
+	b.once.Do(func() {})
+	return b.pr.Close()
+}
+
+// setPathParams 替换路径中 :name 形式的占位符，参数值按路径段进行转义
+func setPathParams(u *url.URL, params map[string]string) {
+	segments := strings.Split(u.Path, "/")
+	rawSegments := make([]string, len(segments))
+	for i, segment := range segments {
+		if value, ok := params[strings.TrimPrefix(segment, ":")]; ok && strings.HasPrefix(segment, ":") {
+			segments[i] = value
+			rawSegments[i] = url.PathEscape(value)
+			continue
+		}
+		rawSegments[i] = url.PathEscape(segment)
+	}
+	u.Path = strings.Join(segments, "/")
+	u.RawPath = strings.Join(rawSegments, "/")
+}
+
 type Result struct {
 	Response *http.Response
+	Latency  time.Duration // 发起调用到收到最终响应头的总耗时，包括拦截器、重试与退避等待
+
+	body []byte
 }
 
 func (r *Result) StatusCode() int {
@@ -292,12 +540,19 @@ func (r *Result) Bind(body interface{}) error {
 		}
 	}()
 
+	// 成功响应直接写入 io.Writer，避免将附件整体读入内存
+	if w, ok := body.(io.Writer); ok && isOk(r.Response.StatusCode) {
+		_, err := io.Copy(w, r.Response.Body)
+		return err
+	}
+
 	data, err := io.ReadAll(r.Response.Body)
 	if err != nil {
 		return err
 	}
+	r.body = data
 	if isOk(r.Response.StatusCode) {
-		return json.Unmarshal(data, body)
+		return bindSuccessBody(r.Response.Header, data, body)
 	}
 	statusErr := &statuserror.StatusErr{}
 	err = json.Unmarshal(data, statusErr)
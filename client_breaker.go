@@ -0,0 +1,241 @@
+package ginx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CircuitState 熔断器状态
+type CircuitState int
+
+const (
+	// CircuitClosed 正常放行请求
+	CircuitClosed CircuitState = iota
+	// CircuitOpen 熔断中，请求直接失败
+	CircuitOpen
+	// CircuitHalfOpen 熔断超时后放行少量探测请求
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// ErrCircuitOpen 熔断器处于打开状态，可通过 errors.Is 判断
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitOpenError 熔断时返回的错误
+type CircuitOpenError struct {
+	Host       string
+	RetryAfter time.Duration // 距离进入半开状态的剩余时间
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit breaker is open for %s, retry after %s", e.Host, e.RetryAfter)
+}
+
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// CircuitBreaker 按目标主机维护状态的熔断器
+// 连续失败达到阈值后打开，经过 OpenTimeout 进入半开状态，半开状态下的探测请求全部成功后关闭
+type CircuitBreaker struct {
+	failureThreshold int
+	openTimeout      time.Duration
+	halfOpenRequests int
+	isFailure        RetryOnFunc
+
+	mu    sync.Mutex
+	hosts map[string]*hostCircuit
+}
+
+type hostCircuit struct {
+	state     CircuitState
+	failures  int
+	openedAt  time.Time
+	probes    int
+	successes int
+}
+
+// CircuitBreakerOption 用于配置熔断器
+type CircuitBreakerOption func(cb *CircuitBreaker)
+
+// WithFailureThreshold 设置打开熔断器的连续失败次数，默认 5
+func WithFailureThreshold(threshold int) CircuitBreakerOption {
+	return func(cb *CircuitBreaker) {
+		cb.failureThreshold = threshold
+	}
+}
+
+// WithOpenTimeout 设置熔断打开后进入半开状态的时间，默认 30s
+func WithOpenTimeout(timeout time.Duration) CircuitBreakerOption {
+	return func(cb *CircuitBreaker) {
+		cb.openTimeout = timeout
+	}
+}
+
+// WithHalfOpenRequests 设置半开状态下允许的探测请求数，默认 1
+func WithHalfOpenRequests(n int) CircuitBreakerOption {
+	return func(cb *CircuitBreaker) {
+		cb.halfOpenRequests = n
+	}
+}
+
+// WithFailureCondition 设置失败判定条件，默认网络错误与 5xx 响应视为失败
+func WithFailureCondition(isFailure RetryOnFunc) CircuitBreakerOption {
+	return func(cb *CircuitBreaker) {
+		cb.isFailure = isFailure
+	}
+}
+
+// NewCircuitBreaker 创建熔断器，同一个熔断器可被多个客户端共享
+func NewCircuitBreaker(opts ...CircuitBreakerOption) *CircuitBreaker {
+	cb := &CircuitBreaker{
+		failureThreshold: 5,
+		openTimeout:      30 * time.Second,
+		halfOpenRequests: 1,
+		isFailure:        defaultIsFailure,
+		hosts:            make(map[string]*hostCircuit),
+	}
+	for _, opt := range opts {
+		opt(cb)
+	}
+	return cb
+}
+
+// WithCircuitBreaker 为请求设置熔断器
+func WithCircuitBreaker(cb *CircuitBreaker) RequestOption {
+	return func(rc *RequestConfig) {
+		rc.CircuitBreaker = cb
+	}
+}
+
+// State 返回指定主机当前的熔断状态
+func (cb *CircuitBreaker) State(host string) CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	circuit, ok := cb.hosts[host]
+	if !ok {
+		return CircuitClosed
+	}
+	cb.refresh(circuit)
+	return circuit.state
+}
+
+// allow 判断请求是否可以放行
+func (cb *CircuitBreaker) allow(host string) error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	circuit, ok := cb.hosts[host]
+	if !ok {
+		circuit = &hostCircuit{}
+		cb.hosts[host] = circuit
+	}
+	cb.refresh(circuit)
+
+	switch circuit.state {
+	case CircuitOpen:
+		return &CircuitOpenError{Host: host, RetryAfter: cb.openTimeout - time.Since(circuit.openedAt)}
+	case CircuitHalfOpen:
+		if circuit.probes >= cb.halfOpenRequests {
+			return &CircuitOpenError{Host: host}
+		}
+		circuit.probes++
+	}
+	return nil
+}
+
+// record 记录请求结果并更新状态
+func (cb *CircuitBreaker) record(host string, resp *http.Response, err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	circuit, ok := cb.hosts[host]
+	if !ok {
+		return
+	}
+
+	// 调用方主动取消的请求不计入结果，超时返回 context.DeadlineExceeded，计为失败
+	if errors.Is(err, context.Canceled) {
+		if circuit.state == CircuitHalfOpen && circuit.probes > 0 {
+			circuit.probes--
+		}
+		return
+	}
+
+	failed := cb.isFailure(resp, err)
+	switch circuit.state {
+	case CircuitClosed:
+		if !failed {
+			circuit.failures = 0
+			return
+		}
+		circuit.failures++
+		if circuit.failures >= cb.failureThreshold {
+			cb.open(circuit)
+		}
+	case CircuitHalfOpen:
+		if failed {
+			cb.open(circuit)
+			return
+		}
+		circuit.successes++
+		if circuit.successes >= cb.halfOpenRequests {
+			*circuit = hostCircuit{}
+		}
+	}
+}
+
+func (cb *CircuitBreaker) open(circuit *hostCircuit) {
+	circuit.state = CircuitOpen
+	circuit.openedAt = time.Now()
+	circuit.failures = 0
+	circuit.probes = 0
+	circuit.successes = 0
+}
+
+// refresh 打开状态超时后转为半开
+func (cb *CircuitBreaker) refresh(circuit *hostCircuit) {
+	if circuit.state == CircuitOpen && time.Since(circuit.openedAt) >= cb.openTimeout {
+		circuit.state = CircuitHalfOpen
+		circuit.probes = 0
+		circuit.successes = 0
+	}
+}
+
+func defaultIsFailure(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp != nil && resp.StatusCode >= http.StatusInternalServerError
+}
+
+// doWithBreaker 经过熔断器执行一次请求
+func doWithBreaker(httpClient *http.Client, req *http.Request, cb *CircuitBreaker) (*http.Response, error) {
+	if cb == nil {
+		return httpClient.Do(req)
+	}
+
+	host := req.URL.Host
+	if err := cb.allow(host); err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Do(req)
+	cb.record(host, resp, err)
+	return resp, err
+}
@@ -0,0 +1,191 @@
+package ginx
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// paramTagOptions 参数名称标签中的选项，如 name:"ids,omitempty"
+type paramTagOptions struct {
+	omitempty bool
+}
+
+// parseParamTagOptions 解析 name 与 json 标签中的 omitempty 选项
+// default 选项只用于服务端，显式设置的零值同样发送，不会被服务端默认值覆盖
+func parseParamTagOptions(field reflect.StructField) paramTagOptions {
+	var opts paramTagOptions
+	for _, key := range []string{"name", "json"} {
+		_, flags, _ := strings.Cut(field.Tag.Get(key), ",")
+		for flags != "" {
+			var flag string
+			flag, flags, _ = strings.Cut(flags, ",")
+			if flag == "omitempty" {
+				opts.omitempty = true
+			}
+		}
+	}
+	return opts
+}
+
+// collectionSeparator 返回 collection_format 对应的分隔符，multi 或未设置时返回空字符串
+func collectionSeparator(field reflect.StructField) (string, error) {
+	switch format := field.Tag.Get("collection_format"); format {
+	case "", "multi":
+		return "", nil
+	case "csv":
+		return ",", nil
+	case "ssv":
+		return " ", nil
+	case "tsv":
+		return "\t", nil
+	case "pipes":
+		return "|", nil
+	default:
+		return "", fmt.Errorf("%s is not supported in the collection_format of field %s", format, field.Name)
+	}
+}
+
+// encodeParamValues 将字段值编码为参数值列表，与 internal/binding 的解析规则保持对称
+// 返回 false 表示该字段不需要发送（nil 指针、omitempty 的零值）
+func encodeParamValues(value reflect.Value, field reflect.StructField) ([]string, bool, error) {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil, false, nil
+		}
+		value = value.Elem()
+	}
+
+	opts := parseParamTagOptions(field)
+	if opts.omitempty && value.IsZero() {
+		return nil, false, nil
+	}
+
+	if _, ok := textMarshaler(value); ok || (value.Kind() != reflect.Slice && value.Kind() != reflect.Array) {
+		s, err := encodeScalar(value, field)
+		if err != nil {
+			return nil, false, err
+		}
+		return []string{s}, true, nil
+	}
+
+	values := make([]string, 0, value.Len())
+	for i := 0; i < value.Len(); i++ {
+		s, err := encodeScalar(value.Index(i), field)
+		if err != nil {
+			return nil, false, err
+		}
+		values = append(values, s)
+	}
+	if len(values) == 0 {
+		return nil, false, nil
+	}
+
+	sep, err := collectionSeparator(field)
+	if err != nil {
+		return nil, false, err
+	}
+	if sep != "" {
+		return []string{strings.Join(values, sep)}, true, nil
+	}
+	return values, true, nil
+}
+
+// encodeScalar 将单个值编码为字符串
+func encodeScalar(value reflect.Value, field reflect.StructField) (string, error) {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return "", nil
+		}
+		value = value.Elem()
+	}
+
+	switch v := value.Interface().(type) {
+	case time.Time:
+		return encodeTime(v, field)
+	case time.Duration:
+		return v.String(), nil
+	}
+
+	if marshaler, ok := textMarshaler(value); ok {
+		data, err := marshaler.MarshalText()
+		return string(data), err
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		return value.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(value.Uint(), 10), nil
+	case reflect.Float32:
+		return strconv.FormatFloat(value.Float(), 'f', -1, 32), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'f', -1, 64), nil
+	}
+
+	// 结构体、map 等复杂类型与服务端一致使用 JSON
+	data, err := json.Marshal(value.Interface())
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// textMarshaler 判断值是否实现了 encoding.TextMarshaler，time.Time 使用 time_format 单独处理
+func textMarshaler(value reflect.Value) (encoding.TextMarshaler, bool) {
+	if value.Type() == reflect.TypeOf(time.Time{}) {
+		return nil, false
+	}
+	if marshaler, ok := value.Interface().(encoding.TextMarshaler); ok {
+		return marshaler, true
+	}
+	if value.CanAddr() {
+		if marshaler, ok := value.Addr().Interface().(encoding.TextMarshaler); ok {
+			return marshaler, true
+		}
+	}
+	return nil, false
+}
+
+// encodeTime 按照 time_format、time_utc、time_location 标签编码时间
+func encodeTime(t time.Time, field reflect.StructField) (string, error) {
+	timeFormat := field.Tag.Get("time_format")
+	if timeFormat == "" {
+		timeFormat = time.RFC3339
+	}
+
+	switch strings.ToLower(timeFormat) {
+	case "unix":
+		return strconv.FormatInt(t.Unix(), 10), nil
+	case "unixmilli":
+		return strconv.FormatInt(t.UnixMilli(), 10), nil
+	case "unixmicro":
+		return strconv.FormatInt(t.UnixMicro(), 10), nil
+	case "unixnano":
+		return strconv.FormatInt(t.UnixNano(), 10), nil
+	}
+
+	if t.IsZero() {
+		return "", nil
+	}
+
+	if isUTC, _ := strconv.ParseBool(field.Tag.Get("time_utc")); isUTC {
+		t = t.UTC()
+	}
+	if locTag := field.Tag.Get("time_location"); locTag != "" {
+		loc, err := time.LoadLocation(locTag)
+		if err != nil {
+			return "", err
+		}
+		t = t.In(loc)
+	}
+	return t.Format(timeFormat), nil
+}
@@ -0,0 +1,338 @@
+package ginx
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type encoderStatus int
+
+const (
+	encoderStatusUnknown encoderStatus = iota
+	encoderStatusActive
+	encoderStatusDisabled
+)
+
+func (s encoderStatus) MarshalText() ([]byte, error) {
+	switch s {
+	case encoderStatusActive:
+		return []byte("ACTIVE"), nil
+	case encoderStatusDisabled:
+		return []byte("DISABLED"), nil
+	}
+	return []byte("UNKNOWN"), nil
+}
+
+func (s *encoderStatus) UnmarshalText(data []byte) error {
+	switch string(data) {
+	case "ACTIVE":
+		*s = encoderStatusActive
+	case "DISABLED":
+		*s = encoderStatusDisabled
+	case "UNKNOWN":
+		*s = encoderStatusUnknown
+	default:
+		return fmt.Errorf("unknown status %q", data)
+	}
+	return nil
+}
+
+type encodeParamsRequest struct {
+	ID       string          `in:"path" name:"id"`
+	IDs      []int           `in:"query" name:"ids"`
+	Tags     []string        `in:"query" name:"tags" collection_format:"csv"`
+	Names    [2]string       `in:"query" name:"names" collection_format:"pipes"`
+	Keyword  *string         `in:"query" name:"keyword"`
+	Limit    *int            `in:"query" name:"limit"`
+	Size     int             `in:"query" name:"size,default=20"`
+	Offset   int             `in:"query" name:"offset,omitempty"`
+	Since    time.Time       `in:"query" name:"since" time_format:"unix"`
+	Day      time.Time       `in:"query" name:"day" time_format:"2006-01-02" time_utc:"1"`
+	Status   encoderStatus   `in:"query" name:"status"`
+	Statuses []encoderStatus `in:"query" name:"statuses"`
+	Timeout  time.Duration   `in:"query" name:"timeout"`
+	Ratio    float64         `in:"query" name:"ratio"`
+	Enabled  bool            `in:"query" name:"enabled"`
+	Notify   bool            `in:"query" name:"notify,default=true"`
+	Trace    []string        `in:"header" name:"X-Trace"`
+	Session  string          `in:"cookies" name:"session"`
+}
+
+type encodeSourcesRequest struct {
+	Tenant string `in:"header,query" name:"tenant"`
+	Token  string `in:"cookies,header" name:"token"`
+}
+
+type EncodeTenantHeader struct {
+	TenantID string `in:"header" name:"X-Tenant-Id"`
+}
+
+type encodePagination struct {
+	Page int `in:"query" name:"page"`
+	Size int `in:"query" name:"size"`
+}
+
+type encodeNestedRequest struct {
+	*EncodeTenantHeader
+	Page   encodePagination
+	Trace  *encodeSourcesRequest
+	Status string `in:"query" name:"status"`
+}
+
+type encodeFormRequest struct {
+	Kind    string   `in:"path" name:"kind"`
+	Names   []string `in:"urlencoded" name:"names"`
+	Score   *float64 `in:"urlencoded" name:"score"`
+	Comment *string  `in:"urlencoded" name:"comment"`
+}
+
+// roundTrip 使用客户端编码请求，再经过服务端参数绑定解码
+func roundTrip(t *testing.T, method, route, rawUrl string, in, out interface{}) *http.Request {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	req, err := newRequestWithContext(context.Background(), method, rawUrl, in)
+	require.NoError(t, err)
+
+	engine := gin.New()
+	engine.UseRawPath = true
+	var bindErr error
+	engine.Handle(method, route, func(ctx *gin.Context) {
+		bindErr = ParameterBinding(ctx, out, GetOperatorTypeInfo(reflect.TypeOf(out)))
+	})
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, "route %s should match %s", route, req.URL.EscapedPath())
+	require.NoError(t, bindErr)
+	return req
+}
+
+func TestClientEncoder_RoundTrip(t *testing.T) {
+	keyword := "a&b c"
+	in := encodeParamsRequest{
+		ID:       "a b/中",
+		IDs:      []int{1, 2, 3},
+		Tags:     []string{"x", "y"},
+		Names:    [2]string{"foo", "bar"},
+		Keyword:  &keyword,
+		Since:    time.Unix(1700000000, 0),
+		Day:      time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC),
+		Status:   encoderStatusActive,
+		Statuses: []encoderStatus{encoderStatusActive, encoderStatusDisabled},
+		Timeout:  1500 * time.Millisecond,
+		Ratio:    0.25,
+		Enabled:  true,
+		Trace:    []string{"t1", "t2"},
+		Session:  "s=1",
+	}
+
+	out := &encodeParamsRequest{}
+	req := roundTrip(t, http.MethodGet, "/users/:id", "http://example.com/users/:id", in, out)
+
+	assert.Equal(t, "/users/a%20b%2F%E4%B8%AD", req.URL.EscapedPath())
+	assert.Equal(t, []string{"x,y"}, req.URL.Query()["tags"])
+	assert.Equal(t, []string{"1", "2", "3"}, req.URL.Query()["ids"])
+	assert.NotContains(t, req.URL.Query(), "limit", "nil pointer should not be sent")
+	assert.NotContains(t, req.URL.Query(), "offset", "omitempty zero value should not be sent")
+	// 有默认值的字段显式设置为零值时同样发送，不会被服务端默认值覆盖
+	assert.Equal(t, []string{"0"}, req.URL.Query()["size"])
+	assert.Equal(t, []string{"false"}, req.URL.Query()["notify"])
+
+	expected := in
+	assert.True(t, in.Since.Equal(out.Since))
+	assert.True(t, in.Day.Equal(out.Day))
+	expected.Since, expected.Day = out.Since, out.Day
+	assert.Equal(t, expected, *out)
+}
+
+func TestClientEncoder_URLEncoded(t *testing.T) {
+	score := 9.5
+	in := encodeFormRequest{
+		Kind:  "report",
+		Names: []string{"a", "b"},
+		Score: &score,
+	}
+
+	out := &encodeFormRequest{}
+	req := roundTrip(t, http.MethodPost, "/forms/:kind", "http://example.com/forms/:kind", in, out)
+
+	assert.Empty(t, req.URL.RawQuery, "urlencoded fields should not be sent as query")
+	assert.Equal(t, MineApplicationUrlencoded, req.Header.Get("Content-Type"))
+	assert.Equal(t, in, *out)
+}
+
+func TestClientEncoder_MultipleSources(t *testing.T) {
+	in := encodeSourcesRequest{Tenant: "t1", Token: "s1"}
+
+	out := &encodeSourcesRequest{}
+	req := roundTrip(t, http.MethodGet, "/tenants", "http://example.com/tenants", in, out)
+
+	// 使用优先级最高的来源发送
+	assert.Equal(t, "t1", req.Header.Get("tenant"))
+	assert.Empty(t, req.URL.RawQuery)
+	cookie, err := req.Cookie("token")
+	require.NoError(t, err)
+	assert.Equal(t, "s1", cookie.Value)
+	assert.Equal(t, in, *out)
+}
+
+func TestClientEncoder_NestedStruct(t *testing.T) {
+	in := encodeNestedRequest{
+		EncodeTenantHeader: &EncodeTenantHeader{TenantID: "t1"},
+		Page:               encodePagination{Page: 2, Size: 10},
+		Status:             "paid",
+	}
+
+	out := &encodeNestedRequest{}
+	req := roundTrip(t, http.MethodGet, "/orders", "http://example.com/orders", in, out)
+
+	assert.Equal(t, "t1", req.Header.Get("X-Tenant-Id"))
+	assert.Equal(t, "page=2&size=10&status=paid", req.URL.RawQuery)
+	// nil 的参数组不发送，服务端绑定时初始化
+	assert.Empty(t, req.Header.Get("tenant"))
+	in.Trace = &encodeSourcesRequest{}
+	assert.Equal(t, in, *out)
+}
+
+func TestClientEncoder_MergePatch(t *testing.T) {
+	in := struct {
+		Body map[string]interface{} `in:"body" mime:"application/merge-patch+json"`
+	}{Body: map[string]interface{}{"title": "new", "tags": nil}}
+
+	out := &struct {
+		Data MergePatch[testArticle] `in:"body"`
+	}{}
+	req := roundTrip(t, http.MethodPatch, "/articles", "http://example.com/articles", in, out)
+
+	// 只发送设置的字段，未设置与 null 可以区分
+	assert.Equal(t, MineApplicationMergePatch, req.Header.Get("Content-Type"))
+	assert.Equal(t, []string{"tags", "title"}, out.Data.Fields())
+	assert.True(t, out.Data.IsNull("tags"))
+	assert.False(t, out.Data.IsNull("title"))
+}
+
+func TestEncodeParamValues(t *testing.T) {
+	type params struct {
+		Nil    *int     `name:"nil"`
+		Empty  []string `name:"empty"`
+		SSV    []int    `name:"ssv" collection_format:"ssv"`
+		Map    map[string]int
+		Format []int `collection_format:"bad"`
+	}
+	value := reflect.ValueOf(params{
+		SSV:    []int{1, 2},
+		Map:    map[string]int{"a": 1},
+		Format: []int{1},
+	})
+	typ := value.Type()
+
+	_, ok, err := encodeParamValues(value.Field(0), typ.Field(0))
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	_, ok, err = encodeParamValues(value.Field(1), typ.Field(1))
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	values, ok, err := encodeParamValues(value.Field(2), typ.Field(2))
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"1 2"}, values)
+
+	values, _, err = encodeParamValues(value.Field(3), typ.Field(3))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`{"a":1}`}, values)
+
+	_, _, err = encodeParamValues(value.Field(4), typ.Field(4))
+	assert.Error(t, err)
+}
+
+type encodeMultipartRequest struct {
+	Name  string        `in:"multipart" name:"name"`
+	File  MultipartFile `in:"multipart" name:"file"`
+	Other []string      `in:"multipart" name:"other"`
+}
+
+type decodeMultipartRequest struct {
+	Name  string                `in:"multipart" name:"name"`
+	File  *multipart.FileHeader `in:"multipart" name:"file"`
+	Other []string              `in:"multipart" name:"other"`
+}
+
+func TestClientEncoder_MultipartStream(t *testing.T) {
+	content := strings.Repeat("a", 1024)
+	in := encodeMultipartRequest{
+		Name: "report",
+		File: MultipartFile{
+			Filename: "report.txt",
+			Header:   textproto.MIMEHeader{"Content-Type": {"text/plain"}},
+			Data:     io.MultiReader(strings.NewReader(content)),
+		},
+		Other: []string{"a", "b"},
+	}
+
+	out := &decodeMultipartRequest{}
+	req := roundTrip(t, http.MethodPost, "/upload", "http://example.com/upload", in, out)
+
+	// 包含文件时流式写入，请求体不会缓存
+	assert.Nil(t, req.GetBody)
+	assert.Equal(t, int64(0), req.ContentLength)
+
+	assert.Equal(t, "report", out.Name)
+	assert.Equal(t, []string{"a", "b"}, out.Other)
+	require.NotNil(t, out.File)
+	assert.Equal(t, "report.txt", out.File.Filename)
+	assert.Equal(t, "text/plain", out.File.Header.Get("Content-Type"))
+	assert.Equal(t, int64(len(content)), out.File.Size)
+
+	// 不包含文件时写入内存，请求可重放
+	req, err := newRequestWithContext(context.Background(), http.MethodPost, "http://example.com/upload", encodeMultipartRequest{Name: "report"})
+	require.NoError(t, err)
+	assert.NotNil(t, req.GetBody)
+	assert.Contains(t, req.Header.Get("Content-Type"), MineMultipartForm)
+}
+
+type readRecorder struct {
+	reads int32
+}
+
+func (r *readRecorder) Read(p []byte) (int, error) {
+	atomic.AddInt32(&r.reads, 1)
+	return 0, io.EOF
+}
+
+func TestClientEncoder_MultipartStreamNotSent(t *testing.T) {
+	data := &readRecorder{}
+	in := encodeMultipartRequest{Name: "report", File: MultipartFile{Filename: "report.txt", Data: data}}
+	req, err := newRequestWithContext(context.Background(), http.MethodPost, "http://127.0.0.1:1/upload", in)
+	require.NoError(t, err)
+
+	// 拦截器直接返回时请求体未被读取，不会启动写入协程
+	cached := func(ctx context.Context, req *http.Request, next RoundTripFunc) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusNoContent, Header: http.Header{}, Body: http.NoBody, Request: req}, nil
+	}
+	require.NoError(t, Invoke(context.Background(), req, nil, nil, &Client{}, nil, WithInterceptors(cached)))
+	require.NoError(t, req.Body.Close())
+	assert.Equal(t, int32(0), atomic.LoadInt32(&data.reads))
+
+	// 读取后写入协程在请求体读完时退出
+	body, contentType := streamMultipart([]formPart{formFieldPart("name", "report")})
+	content, err := io.ReadAll(body)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "report")
+	assert.Contains(t, contentType, MineMultipartForm)
+}
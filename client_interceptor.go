@@ -0,0 +1,73 @@
+package ginx
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// RoundTripFunc 执行一次 HTTP 请求
+type RoundTripFunc func(ctx context.Context, req *http.Request) (*http.Response, error)
+
+// ClientInterceptor 客户端拦截器
+// 拦截器可以在调用 next 前修改请求，或在调用 next 后处理响应，不调用 next 则直接返回结果。
+// 拦截器包裹整次调用（包括重试与熔断），需要重新发送请求时可通过 req.GetBody 获取请求体
+type ClientInterceptor func(ctx context.Context, req *http.Request, next RoundTripFunc) (*http.Response, error)
+
+var (
+	// registeredClientInterceptors 全局拦截器，注册时复制后整体替换，请求时无锁读取
+	registeredClientInterceptors atomic.Pointer[[]ClientInterceptor]
+	registerInterceptorMu        sync.Mutex
+)
+
+// RegisterClientInterceptor 注册全局客户端拦截器
+// 执行顺序：全局拦截器 > 客户端拦截器 > 单次请求拦截器，同一级别按注册顺序执行
+func RegisterClientInterceptor(interceptors ...ClientInterceptor) {
+	registerInterceptorMu.Lock()
+	defer registerInterceptorMu.Unlock()
+
+	list := append([]ClientInterceptor(nil), globalClientInterceptors()...)
+	for _, interceptor := range interceptors {
+		if interceptor == nil {
+			continue
+		}
+		list = append(list, interceptor)
+	}
+	registeredClientInterceptors.Store(&list)
+}
+
+// globalClientInterceptors 返回已注册的全局拦截器，返回值不可修改
+func globalClientInterceptors() []ClientInterceptor {
+	if list := registeredClientInterceptors.Load(); list != nil {
+		return *list
+	}
+	return nil
+}
+
+// WithInterceptors 为请求追加拦截器
+func WithInterceptors(interceptors ...ClientInterceptor) RequestOption {
+	return func(rc *RequestConfig) {
+		rc.Interceptors = append(rc.Interceptors, interceptors...)
+	}
+}
+
+// chainInterceptors 将拦截器串联到 handler 之前，第一个拦截器最先执行
+func chainInterceptors(handler RoundTripFunc, interceptors ...[]ClientInterceptor) RoundTripFunc {
+	var chain []ClientInterceptor
+	for _, list := range interceptors {
+		for _, interceptor := range list {
+			if interceptor != nil {
+				chain = append(chain, interceptor)
+			}
+		}
+	}
+
+	for i := len(chain) - 1; i >= 0; i-- {
+		interceptor, next := chain[i], handler
+		handler = func(ctx context.Context, req *http.Request) (*http.Response, error) {
+			return interceptor(ctx, req, next)
+		}
+	}
+	return handler
+}
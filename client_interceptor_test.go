@@ -0,0 +1,137 @@
+package ginx
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func recordInterceptor(name string, order *[]string) ClientInterceptor {
+	return func(ctx context.Context, req *http.Request, next RoundTripFunc) (*http.Response, error) {
+		*order = append(*order, name+":before")
+		resp, err := next(ctx, req)
+		*order = append(*order, name+":after")
+		return resp, err
+	}
+}
+
+func TestClientInterceptor_Order(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", MineApplicationJson)
+		_, _ = w.Write([]byte(`"` + r.Header.Get("X-Request-From") + `"`))
+	}))
+	defer server.Close()
+
+	var order []string
+	registered := registeredClientInterceptors.Load()
+	defer registeredClientInterceptors.Store(registered)
+	RegisterClientInterceptor(recordInterceptor("global", &order), nil)
+
+	defaults := &RequestConfig{Interceptors: []ClientInterceptor{recordInterceptor("client", &order)}}
+	propagate := func(ctx context.Context, req *http.Request, next RoundTripFunc) (*http.Response, error) {
+		req.Header.Set("X-Request-From", "order-svc")
+		return next(ctx, req)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	var from string
+	err := Invoke(context.Background(), req, &from, defaults, &Client{}, nil,
+		WithInterceptors(recordInterceptor("call", &order), propagate))
+	require.NoError(t, err)
+
+	assert.Equal(t, "order-svc", from)
+	assert.Equal(t, []string{
+		"global:before", "client:before", "call:before",
+		"call:after", "client:after", "global:after",
+	}, order)
+	assert.Len(t, defaults.Interceptors, 1, "per-call interceptors must not leak into defaults")
+}
+
+func TestClientInterceptor_ShortCircuit(t *testing.T) {
+	cached := func(ctx context.Context, req *http.Request, next RoundTripFunc) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{MineApplicationJson}},
+			Body:       io.NopCloser(strings.NewReader(`"cached"`)),
+			Request:    req,
+		}, nil
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "http://127.0.0.1:1", nil)
+	var body string
+	err := Invoke(context.Background(), req, &body, nil, &Client{}, nil, WithInterceptors(cached))
+	require.NoError(t, err)
+	assert.Equal(t, "cached", body)
+}
+
+func TestClientInterceptor_RefreshToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		if r.Header.Get("Authorization") != "Bearer fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", MineApplicationJson)
+		_, _ = w.Write([]byte(`"` + string(data) + `"`))
+	}))
+	defer server.Close()
+
+	token := "expired"
+	refresh := func(ctx context.Context, req *http.Request, next RoundTripFunc) (*http.Response, error) {
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := next(ctx, req)
+		if err != nil || resp.StatusCode != http.StatusUnauthorized {
+			return resp, err
+		}
+		resp.Body.Close()
+
+		token = "fresh"
+		retry := req.Clone(ctx)
+		retry.Body, _ = req.GetBody()
+		retry.Header.Set("Authorization", "Bearer "+token)
+		return next(ctx, retry)
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("payload"))
+	var body string
+	err := Invoke(context.Background(), req, &body, nil, &Client{}, nil, WithInterceptors(refresh))
+	require.NoError(t, err)
+	assert.Equal(t, "payload", body)
+}
+
+func TestRegisterClientInterceptor_Concurrent(t *testing.T) {
+	registered := registeredClientInterceptors.Load()
+	defer registeredClientInterceptors.Store(registered)
+	registeredClientInterceptors.Store(nil)
+
+	fake := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: http.NoBody, Request: req}, nil
+	})
+	pass := func(ctx context.Context, req *http.Request, next RoundTripFunc) (*http.Response, error) {
+		return next(ctx, req)
+	}
+
+	// 注册与请求并发执行，go test -race 下不应出现数据竞争
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			RegisterClientInterceptor(pass)
+		}()
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest(http.MethodGet, "http://user-svc/users", nil)
+			assert.NoError(t, Invoke(context.Background(), req, nil, &RequestConfig{Transport: fake}, &Client{}, nil))
+		}()
+	}
+	wg.Wait()
+	assert.Len(t, globalClientInterceptors(), 10)
+}
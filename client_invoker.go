@@ -2,6 +2,7 @@ package ginx
 
 import (
 	"context"
+	"io"
 )
 
 // InvokeMode 调用模式
@@ -55,11 +56,20 @@ func Invoke(
 		return err
 	}
 
+	meta := requestConfig.ResponseMeta
+	if requestConfig.ResponseWriter != nil {
+		resp = requestConfig.ResponseWriter
+	}
+	// 不关心响应体时同样读完并关闭响应体以复用连接，非 2xx 响应仍返回错误
 	if resp == nil {
-		return nil
+		resp = io.Discard
 	}
 
-	return response.Bind(resp)
+	err = response.Bind(resp)
+	if describer, ok := response.(ResponseMetaDescriber); ok && meta != nil {
+		*meta = describer.Meta()
+	}
+	return err
 }
 
 // buildRequestConfig 构建请求配置
@@ -0,0 +1,45 @@
+package ginx
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+
+	"github.com/shrewx/ginx/pkg/service_discovery"
+)
+
+// ErrResolverNotRegistered 使用 ServiceName 调用但未注册服务解析器
+var ErrResolverNotRegistered = errors.New("service resolver is not registered")
+
+var loadBalancer atomic.Pointer[service_discovery.LoadBalancer]
+
+// RegisterResolver 注册客户端服务解析器
+// 设置了 ServiceName 的请求会通过解析器查询健康实例，并按负载均衡策略选择其中一个发起请求
+func RegisterResolver(resolver service_discovery.Resolver, opts ...service_discovery.LoadBalancerOption) {
+	if resolver == nil {
+		loadBalancer.Store(nil)
+		return
+	}
+	loadBalancer.Store(service_discovery.NewLoadBalancer(resolver, opts...))
+}
+
+// resolveServiceInstance 为请求选择服务实例并写回 config 的 Host/Port
+func resolveServiceInstance(ctx context.Context, config *RequestConfig) (service_discovery.DoneFunc, error) {
+	lb := loadBalancer.Load()
+	if lb == nil {
+		return nil, ErrResolverNotRegistered
+	}
+
+	instance, done, err := lb.Pick(ctx, config.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Schema == "" {
+		config.Schema = "http"
+	}
+	config.Host = instance.Address
+	config.Port = uint16(instance.Port)
+
+	return done, nil
+}
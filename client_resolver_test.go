@@ -0,0 +1,95 @@
+package ginx
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shrewx/ginx/pkg/service_discovery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type resolverTestReq struct {
+	MethodGet
+	ID string `in:"path" name:"id"`
+}
+
+func (r *resolverTestReq) Path() string {
+	return "/users/:id"
+}
+
+func newInstanceServer(t *testing.T, name string) (*httptest.Server, service_discovery.Instance) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", MineApplicationJson)
+		_, _ = w.Write([]byte(`"` + name + r.URL.Path + `"`))
+	}))
+	host, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.NoError(t, err)
+	p, _ := strconv.Atoi(port)
+	return server, service_discovery.Instance{ID: name, Address: host, Port: p}
+}
+
+func TestClient_InvokeWithServiceName(t *testing.T) {
+	serverA, instanceA := newInstanceServer(t, "a")
+	defer serverA.Close()
+	serverB, instanceB := newInstanceServer(t, "b")
+	defer serverB.Close()
+
+	RegisterResolver(service_discovery.NewStaticResolver(map[string][]service_discovery.Instance{
+		"user-svc": {instanceA, instanceB},
+	}))
+	defer RegisterResolver(nil)
+
+	client := &Client{}
+	var got []string
+	for i := 0; i < 4; i++ {
+		var body string
+		err := Invoke(context.Background(), &resolverTestReq{ID: "1"}, &body,
+			&RequestConfig{Host: "unused", Port: 1}, client, nil, WithServiceName("user-svc"))
+		require.NoError(t, err)
+		got = append(got, body)
+	}
+	assert.Equal(t, []string{"a/users/1", "b/users/1", "a/users/1", "b/users/1"}, got)
+}
+
+func TestClient_InvokeWithoutResolver(t *testing.T) {
+	RegisterResolver(nil)
+
+	client := &Client{}
+	_, err := client.Invoke(context.Background(), &resolverTestReq{ID: "1"}, RequestConfig{ServiceName: "user-svc"})
+	assert.ErrorIs(t, err, ErrResolverNotRegistered)
+}
+
+func TestClient_RetryWithServiceName(t *testing.T) {
+	var failedCalls atomic.Int32
+	failed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		failedCalls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failed.Close()
+	host, port, err := net.SplitHostPort(failed.Listener.Addr().String())
+	require.NoError(t, err)
+	p, _ := strconv.Atoi(port)
+
+	serverB, instanceB := newInstanceServer(t, "b")
+	defer serverB.Close()
+
+	RegisterResolver(service_discovery.NewStaticResolver(map[string][]service_discovery.Instance{
+		"user-svc": {{ID: "a", Address: host, Port: p}, instanceB},
+	}))
+	defer RegisterResolver(nil)
+
+	// 重试时重新选择实例，不再请求失败的实例
+	var body string
+	err = Invoke(context.Background(), &resolverTestReq{ID: "1"}, &body, nil, &Client{}, nil,
+		WithServiceName("user-svc"), WithRetry(1, ConstantBackoff(time.Millisecond), nil))
+	require.NoError(t, err)
+	assert.Equal(t, "b/users/1", body)
+	assert.Equal(t, int32(1), failedCalls.Load())
+}
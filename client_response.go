@@ -0,0 +1,108 @@
+package ginx
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"mime"
+	"net/http"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/ugorji/go/codec"
+)
+
+// ResponseMeta 响应元信息
+type ResponseMeta struct {
+	StatusCode int
+	Header     http.Header
+	Cookies    []*http.Cookie
+	Body       []byte        // 原始响应体，绑定到 io.Writer 时为空
+	Latency    time.Duration // 发起调用到收到最终响应头的总耗时，包括拦截器、重试与退避等待
+	TraceID    string        // 下游服务返回的链路追踪 ID
+}
+
+// WithResponseMeta 在绑定响应体的同时获取响应元信息，请求失败时也会尽量填充
+// Latency 为整次调用的耗时，开启重试时包括每次重试与退避等待
+func WithResponseMeta(meta *ResponseMeta) RequestOption {
+	return func(rc *RequestConfig) {
+		rc.ResponseMeta = meta
+	}
+}
+
+// Meta 返回响应元信息，原始响应体在 Bind 之后可用
+func (r *Result) Meta() ResponseMeta {
+	if r.Response == nil {
+		return ResponseMeta{}
+	}
+	return ResponseMeta{
+		StatusCode: r.Response.StatusCode,
+		Header:     r.Response.Header,
+		Cookies:    r.Response.Cookies(),
+		Body:       r.body,
+		Latency:    r.Latency,
+		TraceID:    remoteTraceID(r.Response.Header),
+	}
+}
+
+// remoteTraceID 优先读取 X-Trace-Id，其次从 W3C traceparent 中解析
+func remoteTraceID(header http.Header) string {
+	if traceID := header.Get(TraceIDHeader); traceID != "" {
+		return traceID
+	}
+	// traceparent: version-traceid-spanid-flags
+	if parts := strings.Split(header.Get("traceparent"), "-"); len(parts) == 4 {
+		return parts[1]
+	}
+	return ""
+}
+
+// WithResponseWriter 将成功响应体直接写入 w，用于下载附件等场景，设置后不再绑定到返回值
+func WithResponseWriter(w io.Writer) RequestOption {
+	return func(rc *RequestConfig) {
+		rc.ResponseWriter = w
+	}
+}
+
+// bindSuccessBody 根据目标类型与响应 Content-Type 绑定成功响应
+func bindSuccessBody(header http.Header, data []byte, body interface{}) error {
+	contentType := header.Get("Content-Type")
+	switch v := body.(type) {
+	case *[]byte:
+		*v = data
+		return nil
+	case *MultipartFile:
+		_, params, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+		v.Filename = params["filename"]
+		v.Header = textproto.MIMEHeader(header)
+		v.Data = bytes.NewReader(data)
+		return nil
+	case *string:
+		if !isJSONContentType(contentType) {
+			*v = string(data)
+			return nil
+		}
+	}
+
+	if len(data) == 0 {
+		return nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case MineApplicationMSGPack, MineApplicationMSGPackX:
+		return codec.NewDecoderBytes(data, new(codec.MsgpackHandle)).Decode(body)
+	case MineApplicationXML, MineTextXml:
+		return xml.Unmarshal(data, body)
+	}
+	// 其他类型保持兼容，按 JSON 解析
+	return json.Unmarshal(data, body)
+}
+
+// isJSONContentType 判断是否为 JSON 类型，包含 application/problem+json 等
+func isJSONContentType(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return mediaType == "" || mediaType == MineApplicationJson || strings.HasSuffix(mediaType, "+json")
+}
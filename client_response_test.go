@@ -0,0 +1,127 @@
+package ginx
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ugorji/go/codec"
+)
+
+type responseUser struct {
+	ID   int    `json:"id" xml:"id" codec:"id"`
+	Name string `json:"name" xml:"name" codec:"name"`
+}
+
+func TestInvoke_WithResponseMeta(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", MineApplicationJson)
+		w.Header().Set(TraceIDHeader, "4bf92f3577b34da6a3ce929d0e0e4736")
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc"})
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadGateway)
+			_, _ = w.Write([]byte(`upstream down`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":1,"name":"neo"}`))
+	}))
+	defer server.Close()
+
+	var (
+		meta ResponseMeta
+		user responseUser
+	)
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	err := Invoke(context.Background(), req, &user, nil, &Client{}, nil, WithResponseMeta(&meta))
+	require.NoError(t, err)
+
+	assert.Equal(t, responseUser{ID: 1, Name: "neo"}, user)
+	assert.Equal(t, http.StatusCreated, meta.StatusCode)
+	assert.Equal(t, MineApplicationJson, meta.Header.Get("Content-Type"))
+	require.Len(t, meta.Cookies, 1)
+	assert.Equal(t, "abc", meta.Cookies[0].Value)
+	assert.Equal(t, `{"id":1,"name":"neo"}`, string(meta.Body))
+	assert.Greater(t, meta.Latency, time.Duration(0))
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", meta.TraceID)
+
+	// 失败响应与无返回值接口同样可以获取元信息
+	meta = ResponseMeta{}
+	req, _ = http.NewRequest(http.MethodGet, server.URL+"/fail", nil)
+	err = Invoke(context.Background(), req, nil, nil, &Client{}, nil, WithResponseMeta(&meta))
+	var remoteErr *RemoteHTTPError
+	require.ErrorAs(t, err, &remoteErr)
+	assert.Equal(t, http.StatusBadGateway, meta.StatusCode)
+	assert.Equal(t, "upstream down", string(meta.Body))
+}
+
+func TestResult_BindByContentType(t *testing.T) {
+	var msgpackData []byte
+	require.NoError(t, codec.NewEncoderBytes(&msgpackData, new(codec.MsgpackHandle)).Encode(responseUser{ID: 2, Name: "trinity"}))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/text":
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			_, _ = w.Write([]byte("pong"))
+		case "/msgpack":
+			w.Header().Set("Content-Type", MineApplicationMSGPack)
+			_, _ = w.Write(msgpackData)
+		case "/xml":
+			w.Header().Set("Content-Type", MineApplicationXML)
+			_, _ = w.Write([]byte(`<responseUser><id>3</id><name>morpheus</name></responseUser>`))
+		case "/attachment":
+			w.Header().Set("Content-Type", MineApplicationOctetStream)
+			w.Header().Set("Content-Disposition", `attachment; filename="report.csv"`)
+			_, _ = w.Write([]byte("a,b\n1,2\n"))
+		}
+	}))
+	defer server.Close()
+
+	invoke := func(path string, resp interface{}, opts ...RequestOption) {
+		req, _ := http.NewRequest(http.MethodGet, server.URL+path, nil)
+		require.NoError(t, Invoke(context.Background(), req, resp, nil, &Client{}, nil, opts...))
+	}
+
+	var text string
+	invoke("/text", &text)
+	assert.Equal(t, "pong", text)
+
+	var user responseUser
+	invoke("/msgpack", &user)
+	assert.Equal(t, responseUser{ID: 2, Name: "trinity"}, user)
+
+	user = responseUser{}
+	invoke("/xml", &user)
+	assert.Equal(t, responseUser{ID: 3, Name: "morpheus"}, user)
+
+	var file MultipartFile
+	invoke("/attachment", &file)
+	assert.Equal(t, "report.csv", file.Filename)
+	data, _ := io.ReadAll(file.Data)
+	assert.Equal(t, "a,b\n1,2\n", string(data))
+
+	// 附件直接写入 io.Writer，不再绑定到返回值
+	var buf bytes.Buffer
+	file = MultipartFile{}
+	invoke("/attachment", &file, WithResponseWriter(&buf))
+	assert.Equal(t, "a,b\n1,2\n", buf.String())
+	assert.Nil(t, file.Data)
+}
+
+func TestRemoteTraceID(t *testing.T) {
+	header := http.Header{}
+	assert.Empty(t, remoteTraceID(header))
+
+	header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", remoteTraceID(header))
+
+	header.Set(TraceIDHeader, "custom")
+	assert.Equal(t, "custom", remoteTraceID(header))
+}
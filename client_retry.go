@@ -0,0 +1,236 @@
+package ginx
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/shrewx/ginx/pkg/logx"
+	"github.com/shrewx/ginx/pkg/service_discovery"
+)
+
+// BackoffFunc 返回第 attempt 次重试前的等待时间，attempt 从 1 开始
+type BackoffFunc func(attempt int) time.Duration
+
+// RetryOnFunc 判断一次请求的结果是否需要重试，resp 与 err 可能为 nil
+type RetryOnFunc func(resp *http.Response, err error) bool
+
+// RetryPolicy 重试策略
+type RetryPolicy struct {
+	Max                int           // 最大重试次数，不包括首次请求
+	Backoff            BackoffFunc   // 退避策略，为 nil 时使用 DefaultBackoff
+	RetryOn            RetryOnFunc   // 重试条件，为 nil 时使用 DefaultRetryOn
+	RetryNonIdempotent bool          // 是否重试非幂等请求（POST/PATCH 等）
+	MaxRetryAfter      time.Duration // Retry-After 的最长等待时间，超出时按该值等待，为 0 时使用 DefaultMaxRetryAfter
+}
+
+// DefaultMaxRetryAfter 默认的 Retry-After 最长等待时间
+const DefaultMaxRetryAfter = 10 * time.Second
+
+// DefaultBackoff 默认退避策略，100ms 起指数增长，最长 2s
+var DefaultBackoff = ExponentialBackoff(100*time.Millisecond, 2*time.Second)
+
+// ConstantBackoff 固定间隔退避
+func ConstantBackoff(delay time.Duration) BackoffFunc {
+	return func(attempt int) time.Duration {
+		return delay
+	}
+}
+
+// ExponentialBackoff 指数退避，每次等待时间翻倍并叠加随机抖动，最长不超过 max
+func ExponentialBackoff(base, max time.Duration) BackoffFunc {
+	return func(attempt int) time.Duration {
+		delay := base
+		for i := 1; i < attempt && delay < max; i++ {
+			delay *= 2
+		}
+		if delay > max {
+			delay = max
+		}
+		// 在 [delay/2, delay] 区间内抖动，避免多个客户端同时重试
+		half := int64(delay / 2)
+		if half <= 0 {
+			return delay
+		}
+		return time.Duration(half + rand.Int63n(half+1))
+	}
+}
+
+// DefaultRetryOn 默认重试条件：网络错误以及 429/502/503/504 响应
+func DefaultRetryOn(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, ErrCircuitOpen)
+	}
+	if resp == nil {
+		return false
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// WithRetry 设置请求的重试策略
+// 默认只重试幂等请求（GET/HEAD/OPTIONS/PUT/DELETE/TRACE 或带有 Idempotency-Key 的请求），
+// 响应携带 Retry-After 时优先按其等待，最长不超过 MaxRetryAfter；通过服务发现调用时每次重试重新选择实例
+func WithRetry(max int, backoff BackoffFunc, retryOn RetryOnFunc) RequestOption {
+	return func(rc *RequestConfig) {
+		rc.Retry = &RetryPolicy{Max: max, Backoff: backoff, RetryOn: retryOn}
+	}
+}
+
+// WithRetryPolicy 设置完整的重试策略
+func WithRetryPolicy(policy RetryPolicy) RequestOption {
+	return func(rc *RequestConfig) {
+		rc.Retry = &policy
+	}
+}
+
+// WithoutRetry 关闭重试，可用于覆盖客户端的默认重试策略
+func WithoutRetry() RequestOption {
+	return WithRetryPolicy(RetryPolicy{})
+}
+
+// doWithRetry 按重试策略与熔断器执行请求
+func doWithRetry(ctx context.Context, httpClient *http.Client, req *http.Request, config RequestConfig) (*http.Response, error) {
+	policy := config.Retry
+	if policy == nil || policy.Max <= 0 || (!policy.RetryNonIdempotent && !isIdempotent(req)) || !replayable(req) {
+		return doWithBreaker(httpClient, req, config.CircuitBreaker)
+	}
+
+	backoff := policy.Backoff
+	if backoff == nil {
+		backoff = DefaultBackoff
+	}
+	retryOn := policy.RetryOn
+	if retryOn == nil {
+		retryOn = DefaultRetryOn
+	}
+	maxRetryAfter := policy.MaxRetryAfter
+	if maxRetryAfter <= 0 {
+		maxRetryAfter = DefaultMaxRetryAfter
+	}
+
+	// 重试时重新选择的实例，请求结束时释放
+	var release service_discovery.DoneFunc
+	defer func() {
+		if release != nil {
+			release()
+		}
+	}()
+
+	attemptReq := req
+	for attempt := 0; ; attempt++ {
+		resp, err := doWithBreaker(httpClient, attemptReq, config.CircuitBreaker)
+		if attempt >= policy.Max || !retryOn(resp, err) {
+			return resp, err
+		}
+
+		wait := backoff(attempt + 1)
+		if retryAfter, ok := parseRetryAfter(resp); ok {
+			wait = retryAfter
+			if wait > maxRetryAfter {
+				wait = maxRetryAfter
+			}
+		}
+		// 剩余时间不足以等待时直接返回本次结果
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return resp, err
+		}
+
+		if err != nil {
+			logx.Warnf("http request %s %s failed (attempt %d/%d): %s, retry after %s",
+				req.Method, req.URL.Redacted(), attempt+1, policy.Max+1, err.Error(), wait)
+		} else {
+			logx.Warnf("http request %s %s returned %d (attempt %d/%d), retry after %s",
+				req.Method, req.URL.Redacted(), resp.StatusCode, attempt+1, policy.Max+1, wait)
+			drainBody(resp)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		if attemptReq, err = cloneRequest(ctx, req); err != nil {
+			return nil, err
+		}
+		// 通过服务发现调用时每次重试重新选择实例，避免继续请求失败的实例
+		if config.ServiceName != "" {
+			if release != nil {
+				release()
+				release = nil
+			}
+			retryConfig := config
+			if release, err = resolveServiceInstance(ctx, &retryConfig); err != nil {
+				return nil, err
+			}
+			attemptReq.URL.Scheme = retryConfig.Schema
+			attemptReq.URL.Host = requestHost(retryConfig)
+			attemptReq.Host = ""
+		}
+	}
+}
+
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete, http.MethodTrace:
+		return true
+	}
+	return req.Header.Get("Idempotency-Key") != ""
+}
+
+// replayable 请求体可以重复读取时才允许重试
+func replayable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+func cloneRequest(ctx context.Context, req *http.Request) (*http.Request, error) {
+	clone := req.Clone(ctx)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		clone.Body = body
+	}
+	return clone, nil
+}
+
+// parseRetryAfter 解析 Retry-After 响应头，支持秒数与 HTTP 日期两种格式
+func parseRetryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// drainBody 读完并关闭响应体，以便连接可以复用
+func drainBody(resp *http.Response) {
+	if resp == nil || resp.Body == nil {
+		return
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	_ = resp.Body.Close()
+}
@@ -0,0 +1,257 @@
+package ginx
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDoWithRetry(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		header       http.Header
+		failures     int32
+		opts         []RequestOption
+		expectStatus int
+		expectCalls  int32
+	}{
+		{
+			name:         "retry idempotent request until success",
+			method:       http.MethodPut,
+			failures:     2,
+			opts:         []RequestOption{WithRetry(3, ConstantBackoff(time.Millisecond), nil)},
+			expectStatus: http.StatusOK,
+			expectCalls:  3,
+		},
+		{
+			name:         "stop after max retries",
+			method:       http.MethodGet,
+			failures:     10,
+			opts:         []RequestOption{WithRetry(2, ConstantBackoff(time.Millisecond), nil)},
+			expectStatus: http.StatusServiceUnavailable,
+			expectCalls:  3,
+		},
+		{
+			name:         "do not retry non-idempotent request",
+			method:       http.MethodPost,
+			failures:     1,
+			opts:         []RequestOption{WithRetry(3, ConstantBackoff(time.Millisecond), nil)},
+			expectStatus: http.StatusServiceUnavailable,
+			expectCalls:  1,
+		},
+		{
+			name:         "retry request with idempotency key",
+			method:       http.MethodPost,
+			header:       http.Header{"Idempotency-Key": []string{"abc"}},
+			failures:     1,
+			opts:         []RequestOption{WithRetry(3, ConstantBackoff(time.Millisecond), nil)},
+			expectStatus: http.StatusOK,
+			expectCalls:  2,
+		},
+		{
+			name:     "custom retry condition",
+			method:   http.MethodGet,
+			failures: 1,
+			opts: []RequestOption{WithRetry(3, ConstantBackoff(time.Millisecond), func(resp *http.Response, err error) bool {
+				return false
+			})},
+			expectStatus: http.StatusServiceUnavailable,
+			expectCalls:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				assert.Equal(t, "payload", string(body), "body should be replayed on retry")
+				if calls.Add(1) <= tt.failures {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			req, err := http.NewRequest(tt.method, server.URL, strings.NewReader("payload"))
+			require.NoError(t, err)
+			for k, v := range tt.header {
+				req.Header[k] = v
+			}
+
+			config := buildRequestConfig(tt.opts...)
+			resp, err := doWithRetry(context.Background(), http.DefaultClient, req, *config)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, tt.expectStatus, resp.StatusCode)
+			assert.Equal(t, tt.expectCalls, calls.Load())
+		})
+	}
+}
+
+func TestDoWithRetry_RetryAfter(t *testing.T) {
+	var (
+		calls int32
+		first time.Time
+		wait  time.Duration
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			first = time.Now()
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		wait = time.Since(first)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	config := buildRequestConfig(WithRetry(1, ConstantBackoff(time.Millisecond), nil))
+	resp, err := doWithRetry(context.Background(), http.DefaultClient, req, *config)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.GreaterOrEqual(t, wait, 900*time.Millisecond)
+
+	// 剩余时间不足 Retry-After 时不再等待
+	atomic.StoreInt32(&calls, 0)
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	req, _ = http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	resp, err = doWithRetry(ctx, http.DefaultClient, req, *config)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestDoWithRetry_MaxRetryAfter(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// Retry-After 超出上限时按上限等待
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	config := buildRequestConfig(WithRetryPolicy(RetryPolicy{Max: 1, MaxRetryAfter: 20 * time.Millisecond}))
+	start := time.Now()
+	resp, err := doWithRetry(context.Background(), http.DefaultClient, req, *config)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(100*time.Millisecond, time.Second)
+	for attempt, max := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 400 * time.Millisecond, 10: time.Second} {
+		d := backoff(attempt)
+		assert.GreaterOrEqual(t, d, max/2)
+		assert.LessOrEqual(t, d, max)
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	var healthy atomic.Bool
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cb := NewCircuitBreaker(WithFailureThreshold(2), WithOpenTimeout(50*time.Millisecond))
+	host := strings.TrimPrefix(server.URL, "http://")
+	do := func() (*http.Response, error) {
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		resp, err := doWithBreaker(http.DefaultClient, req, cb)
+		if resp != nil {
+			resp.Body.Close()
+		}
+		return resp, err
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err := do()
+		require.NoError(t, err)
+	}
+	assert.Equal(t, CircuitOpen, cb.State(host))
+
+	_, err := do()
+	assert.True(t, errors.Is(err, ErrCircuitOpen))
+	var openErr *CircuitOpenError
+	require.ErrorAs(t, err, &openErr)
+	assert.Equal(t, host, openErr.Host)
+	assert.Equal(t, int32(2), calls.Load(), "open circuit should fail fast")
+
+	// 半开状态探测失败重新打开
+	time.Sleep(60 * time.Millisecond)
+	assert.Equal(t, CircuitHalfOpen, cb.State(host))
+	_, err = do()
+	require.NoError(t, err)
+	assert.Equal(t, CircuitOpen, cb.State(host))
+
+	// 半开状态探测成功后关闭
+	healthy.Store(true)
+	time.Sleep(60 * time.Millisecond)
+	resp, err := do()
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, CircuitClosed, cb.State(host))
+}
+
+func TestInvoke_RetryAndBreakerDefaults(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	defaults := &RequestConfig{
+		Retry:          &RetryPolicy{Max: 2, Backoff: ConstantBackoff(time.Millisecond)},
+		CircuitBreaker: NewCircuitBreaker(WithFailureThreshold(3)),
+	}
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+
+	client := &Client{}
+	err := Invoke(context.Background(), req, nil, defaults, client, nil)
+	assert.Error(t, err)
+	assert.Equal(t, int32(3), calls.Load())
+
+	// 熔断器已打开，重试也不会再发出请求
+	req, _ = http.NewRequest(http.MethodGet, server.URL, nil)
+	err = Invoke(context.Background(), req, nil, defaults, client, nil)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, int32(3), calls.Load())
+
+	// 单次请求可以关闭默认重试
+	calls.Store(0)
+	req, _ = http.NewRequest(http.MethodGet, server.URL, nil)
+	err = Invoke(context.Background(), req, nil, &RequestConfig{Retry: defaults.Retry}, client, nil, WithoutRetry())
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+}
@@ -0,0 +1,219 @@
+package ginx
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shrewx/ginx/pkg/conf"
+	"github.com/shrewx/ginx/pkg/logx"
+)
+
+// defaultCompressionMinSize 默认最小压缩大小
+const defaultCompressionMinSize = 1024
+
+// defaultCompressionTypes 默认可压缩的响应类型，图片、音视频等已压缩的类型不再压缩
+var defaultCompressionTypes = []string{
+	MineApplicationJson,
+	"application/*+json",
+	"application/javascript",
+	MineApplicationXML,
+	"application/*+xml",
+	MineApplicationYaml,
+	"application/yaml",
+	MineApplicationToml,
+	"text/*",
+	MineImageSvg,
+}
+
+// Compressor 响应压缩算法，level 为 0 时使用默认级别
+type Compressor func(w io.Writer, level int) (io.WriteCloser, error)
+
+var (
+	// compressors 已注册的压缩算法，key 为 Content-Encoding
+	compressors = map[string]Compressor{
+		"gzip": func(w io.Writer, level int) (io.WriteCloser, error) {
+			if level == 0 {
+				level = gzip.DefaultCompression
+			}
+			return gzip.NewWriterLevel(w, level)
+		},
+		"deflate": func(w io.Writer, level int) (io.WriteCloser, error) {
+			if level == 0 {
+				level = zlib.DefaultCompression
+			}
+			return zlib.NewWriterLevel(w, level)
+		},
+	}
+	// compressionEncodings 压缩算法的优先级，客户端权重相同时靠前的优先
+	compressionEncodings = []string{"gzip", "deflate"}
+	// compression 响应压缩配置，为空时不压缩
+	compression *conf.Compression
+)
+
+// RegisterCompressor 注册响应压缩算法，如 br、zstd，后注册的算法在客户端权重相同时优先使用
+// 注册同名算法时替换原有实现
+//
+//	ginx.RegisterCompressor("br", func(w io.Writer, level int) (io.WriteCloser, error) {
+//		return brotli.NewWriterLevel(w, level), nil
+//	})
+func RegisterCompressor(encoding string, compressor Compressor) {
+	encoding = strings.ToLower(strings.TrimSpace(encoding))
+	if encoding == "" || encoding == "identity" || encoding == "*" {
+		panic("invalid content encoding: " + encoding)
+	}
+	if compressor == nil {
+		panic("compressor of " + encoding + " is nil")
+	}
+
+	if _, ok := compressors[encoding]; !ok {
+		compressionEncodings = append([]string{encoding}, compressionEncodings...)
+	}
+	compressors[encoding] = compressor
+}
+
+func loadCompression(config *conf.Server) {
+	if config.Compression == nil {
+		compression = nil
+		return
+	}
+
+	// 各压缩算法均需支持配置的级别，避免每次压缩响应时失败
+	for _, encoding := range compressionEncodings {
+		if _, err := compressors[encoding](io.Discard, config.Compression.Level); err != nil {
+			panic(fmt.Sprintf("invalid compression level %d of %s: %v", config.Compression.Level, encoding, err))
+		}
+	}
+
+	compression = &conf.Compression{
+		Level:   config.Compression.Level,
+		MinSize: defaultCompressionMinSize,
+		Types:   defaultCompressionTypes,
+	}
+	if config.Compression.MinSize > 0 {
+		compression.MinSize = config.Compression.MinSize
+	}
+	if len(config.Compression.Types) > 0 {
+		compression.Types = config.Compression.Types
+	}
+}
+
+// negotiateEncoding 根据 Accept-Encoding 选择权重最高的压缩算法，不支持时返回空
+func negotiateEncoding(acceptEncoding string) string {
+	if acceptEncoding == "" {
+		return ""
+	}
+
+	weights := make(map[string]float64)
+	for _, item := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(item, ";")
+		weight := 1.0
+		for _, param := range strings.Split(params, ";") {
+			key, value, _ := strings.Cut(param, "=")
+			if strings.TrimSpace(key) != "q" {
+				continue
+			}
+			if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				weight = q
+			}
+		}
+		weights[strings.ToLower(strings.TrimSpace(name))] = weight
+	}
+
+	encoding, best := "", 0.0
+	for _, name := range compressionEncodings {
+		weight, ok := weights[name]
+		if !ok {
+			weight = weights["*"]
+		}
+		if weight > best {
+			encoding, best = name, weight
+		}
+	}
+	return encoding
+}
+
+// addVary 添加 Vary 响应头，已存在时不重复添加
+func addVary(header http.Header, value string) {
+	for _, vary := range header.Values("Vary") {
+		for _, item := range strings.Split(vary, ",") {
+			item = strings.TrimSpace(item)
+			if item == "*" || strings.EqualFold(item, value) {
+				return
+			}
+		}
+	}
+	header.Add("Vary", value)
+}
+
+// responseEncoding 响应使用的压缩算法，未启用压缩、已设置 Content-Encoding 或 Content-Range、响应类型不可压缩或响应体过小时返回空
+func responseEncoding(ctx *gin.Context, status int, contentType string, size int) string {
+	if compression == nil {
+		return ""
+	}
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusPartialContent || status == http.StatusNotModified {
+		return ""
+	}
+
+	header := ctx.Writer.Header()
+	// 范围响应的 Content-Range 对应未压缩的内容，压缩后范围不再正确
+	if header.Get("Content-Encoding") != "" || header.Get("Content-Range") != "" {
+		return ""
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || !matchMediaType(compression.Types, mediaType) {
+		return ""
+	}
+
+	// 可压缩的响应随 Accept-Encoding 变化，缓存需要区分
+	addVary(header, "Accept-Encoding")
+	if size < compression.MinSize {
+		return ""
+	}
+	return negotiateEncoding(ctx.GetHeader("Accept-Encoding"))
+}
+
+// compress 使用指定的压缩算法压缩响应体
+func compress(encoding string, body []byte) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	writer, err := compressors[encoding](buf, compression.Level)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := writer.Write(body); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeResponse 写入响应，客户端支持且响应满足压缩条件时压缩响应体
+func writeResponse(ctx *gin.Context, status int, contentType string, body []byte) {
+	if encoding := responseEncoding(ctx, status, contentType, len(body)); encoding != "" {
+		compressed, err := compress(encoding, body)
+		if err != nil {
+			logx.Errorf("compress response with %s error: %v", encoding, err)
+			ctx.Data(status, contentType, body)
+			return
+		}
+
+		header := ctx.Writer.Header()
+		header.Set("Content-Encoding", encoding)
+		header.Del("Content-Length")
+		// 压缩后内容不再逐字节一致，强 ETag 改为弱 ETag
+		if etag := header.Get("ETag"); strings.HasPrefix(etag, `"`) {
+			header.Set("ETag", "W/"+etag)
+		}
+		body = compressed
+	}
+	ctx.Data(status, contentType, body)
+}
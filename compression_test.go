@@ -0,0 +1,181 @@
+package ginx
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shrewx/ginx/pkg/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCompressionOperator 按 type 参数返回不同类型的响应
+type TestCompressionOperator struct {
+	MethodGet
+	Type string `in:"query" name:"type"`
+	Size int    `in:"query" name:"size"`
+}
+
+func (t *TestCompressionOperator) Path() string { return "/api/compression" }
+
+func (t *TestCompressionOperator) Output(ctx *gin.Context) (interface{}, error) {
+	content := strings.Repeat("a", t.Size)
+	switch t.Type {
+	case "png":
+		image := NewImagePNG()
+		image.WriteString(content)
+		return image, nil
+	case "attachment":
+		attachment := NewAttachment("data.json.gz", MineApplicationJson)
+		attachment.SetContentEncoding("gzip")
+		writer := gzip.NewWriter(attachment)
+		_, _ = writer.Write([]byte(content))
+		_ = writer.Close()
+		return attachment, nil
+	case "range":
+		ctx.Header("Content-Range", fmt.Sprintf("bytes 0-%d/%d", t.Size-1, t.Size*2))
+	}
+	return map[string]string{"content": content}, nil
+}
+
+func TestCompression(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	loadCompression(&conf.Server{Compression: &conf.Compression{}})
+	defer loadCompression(&conf.Server{})
+
+	engine := gin.New()
+	engine.GET("/api/compression", ginHandleFuncWrapper(&TestCompressionOperator{}))
+
+	serve := func(query, acceptEncoding string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/compression?"+query, nil)
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+		engine.ServeHTTP(w, req)
+		return w
+	}
+	expected := `{"content":"` + strings.Repeat("a", 2048) + `"}`
+
+	t.Run("gzip", func(t *testing.T) {
+		w := serve("size=2048", "deflate;q=0.5, gzip")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+		assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+
+		reader, err := gzip.NewReader(w.Body)
+		require.NoError(t, err)
+		body, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(body))
+	})
+
+	t.Run("deflate", func(t *testing.T) {
+		w := serve("size=2048", "gzip;q=0, *")
+		require.Equal(t, "deflate", w.Header().Get("Content-Encoding"))
+
+		reader, err := zlib.NewReader(w.Body)
+		require.NoError(t, err)
+		body, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(body))
+	})
+
+	t.Run("not compressed", func(t *testing.T) {
+		for query, acceptEncoding := range map[string]string{
+			"size=2048": "",
+			"size=2049": "br",
+			"size=16":   "gzip",
+			"size=2050": "identity, *;q=0",
+		} {
+			w := serve(query, acceptEncoding)
+			assert.Empty(t, w.Header().Get("Content-Encoding"), query)
+			assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"), query)
+			assert.True(t, strings.HasPrefix(w.Body.String(), `{"content":"`), query)
+		}
+	})
+
+	t.Run("skip compressed content", func(t *testing.T) {
+		w := serve("size=2048&type=png", "gzip")
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Empty(t, w.Header().Get("Vary"))
+		assert.Equal(t, strings.Repeat("a", 2048), w.Body.String())
+
+		// 附件已设置编码，不再重复压缩
+		w = serve("size=2048&type=attachment", "gzip")
+		assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+		reader, err := gzip.NewReader(w.Body)
+		require.NoError(t, err)
+		body, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, strings.Repeat("a", 2048), string(body))
+
+		// 范围响应不压缩
+		w = serve("size=2048&type=range", "gzip")
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Equal(t, expected, w.Body.String())
+	})
+}
+
+func TestLoadCompression_Level(t *testing.T) {
+	defer loadCompression(&conf.Server{})
+
+	for _, level := range []int{gzip.HuffmanOnly, gzip.DefaultCompression, gzip.BestSpeed, gzip.BestCompression} {
+		assert.NotPanics(t, func() { loadCompression(&conf.Server{Compression: &conf.Compression{Level: level}}) }, level)
+	}
+	for _, level := range []int{-3, 10} {
+		assert.Panics(t, func() { loadCompression(&conf.Server{Compression: &conf.Compression{Level: level}}) }, level)
+	}
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	for acceptEncoding, expected := range map[string]string{
+		"":                                  "",
+		"gzip":                              "gzip",
+		"deflate, gzip":                     "gzip",
+		"deflate, gzip;q=0.8":               "deflate",
+		"GZIP; q=0.5, br":                   "gzip",
+		"*":                                 "gzip",
+		"*;q=0.5, deflate;q=1":              "deflate",
+		"gzip;q=0, deflate;q=0":             "",
+		"br, identity":                      "",
+		"gzip;level=1;q=0.3, deflate;q=0.2": "gzip",
+	} {
+		assert.Equal(t, expected, negotiateEncoding(acceptEncoding), acceptEncoding)
+	}
+}
+
+func TestRegisterCompressor(t *testing.T) {
+	encodings := compressionEncodings
+	defer func() {
+		compressionEncodings = encodings
+		delete(compressors, "test")
+	}()
+
+	assert.Panics(t, func() { RegisterCompressor("identity", compressors["gzip"]) })
+	assert.Panics(t, func() { RegisterCompressor("test", nil) })
+
+	RegisterCompressor("test", func(w io.Writer, level int) (io.WriteCloser, error) {
+		return nopWriteCloser{w}, nil
+	})
+	// 权重相同时后注册的算法优先
+	assert.Equal(t, "test", negotiateEncoding("gzip, test"))
+	assert.Equal(t, "gzip", negotiateEncoding("gzip, test;q=0.5"))
+
+	loadCompression(&conf.Server{Compression: &conf.Compression{MinSize: 1}})
+	defer loadCompression(&conf.Server{})
+	body, err := compress("test", []byte("content"))
+	require.NoError(t, err)
+	assert.Equal(t, "content", string(body))
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
@@ -18,6 +18,9 @@ const (
 	InjectParamsKey  = "x-inject-params"
 
 	RequestContextKey = "x-request-ctx-key"
+
+	// TraceIDHeader 服务端在响应头中返回的链路追踪 ID
+	TraceIDHeader = "X-Trace-Id"
 )
 const (
 	Success = "success"
@@ -27,6 +30,8 @@ const (
 // https://developer.mozilla.org/en-US/docs/Web/HTTP/Basics_of_HTTP/MIME_types
 const (
 	MineApplicationJson        = "application/json"
+	MineApplicationMergePatch  = "application/merge-patch+json"
+	MineApplicationJsonPatch   = "application/json-patch+json"
 	MineApplicationOctetStream = "application/octet-stream"
 	MineApplicationOgg         = "application/ogg"
 	MineApplicationXML         = "application/xml"
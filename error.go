@@ -2,6 +2,7 @@ package ginx
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 
 	"github.com/sirupsen/logrus"
@@ -212,7 +213,7 @@ func abortWithResponse(ctx *gin.Context, statusCode int, contentType string, bod
 		}
 	}
 	ctx.Abort()
-	ctx.Data(statusCode, contentType, body)
+	writeResponse(ctx, statusCode, contentType, body)
 }
 
 func WithStack(error error) error {
@@ -243,6 +244,9 @@ type RemoteHTTPError struct {
 }
 
 func (e *RemoteHTTPError) Error() string {
+	if len(e.body) == 0 {
+		return fmt.Sprintf("remote http error: %d %s", e.status, http.StatusText(e.status))
+	}
 	return string(e.body)
 }
 func (e *RemoteHTTPError) Status() int          { return e.status }
@@ -9,9 +9,11 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
 	e2 "github.com/shrewx/ginx/internal/errors"
 	"github.com/shrewx/ginx/internal/middleware"
 	"github.com/shrewx/ginx/pkg/logx"
+	"github.com/shrewx/ginx/pkg/statuserror"
 )
 
 type GinGroup struct {
@@ -97,9 +99,15 @@ func initGinEngine(r *GinRouter) *gin.Engine {
 	gin.SetMode(gin.ReleaseMode)
 
 	root := gin.New()
+	root.MaxMultipartMemory = multipartMemory
 
 	// health
 	root.GET("/health", func(c *gin.Context) {
+		// 进入关闭流程后返回 503，让负载均衡/探针尽快摘除流量
+		if instance().lifecycle.isShuttingDown() {
+			c.JSON(http.StatusServiceUnavailable, "shutting down")
+			return
+		}
 		c.JSON(http.StatusOK, "health")
 	})
 
@@ -213,10 +221,33 @@ func ginHandleFuncWrapper(op HandleOperator) gin.HandlerFunc {
 			ctx.Header(CurrentLangHeader(), I18nZH)
 		}
 
+		// 限制请求体大小，并在处理结束后清理 multipart 临时文件
+		if err := limitRequestBody(ctx, operator); err != nil {
+			executeErrorHandlers(err, ctx)
+			return
+		}
+		defer cleanupMultipartForm(ctx)
+
+		// 解压请求体并校验请求体类型，不支持时返回 415
+		if err := decodeContentEncoding(ctx, operator); err != nil {
+			executeErrorHandlers(err, ctx)
+			return
+		}
+		if err := checkContentType(ctx, operator); err != nil {
+			executeErrorHandlers(err, ctx)
+			return
+		}
+
 		// 使用高性能参数绑定，基于预解析的类型信息
 		if err := ParameterBinding(ctx, instance, typeInfo); err != nil {
 			logx.Error(err)
-			executeErrorHandlers(e2.BadRequest, ctx)
+			// 校验错误返回失败的字段，请求体过大返回 413，其他错误统一返回参数错误
+			var commonErr statuserror.CommonError
+			if errors.As(err, &commonErr) {
+				executeErrorHandlers(commonErr, ctx)
+			} else {
+				executeErrorHandlers(e2.BadRequest, ctx)
+			}
 			return
 		}
 
@@ -9,6 +9,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/shrewx/ginx/internal/errors"
+	"github.com/shrewx/ginx/pkg/conf"
+	"github.com/shrewx/ginx/pkg/i18nx"
 	"github.com/shrewx/ginx/pkg/statuserror"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -493,6 +495,33 @@ func (t *TestTypeOperatorMiddlewareWithRequiredParam) Output(ctx *gin.Context) (
 	return nil, nil
 }
 
+func TestGinHandleFuncWrapper_ValidationError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	i18nx.Load(&conf.I18N{Langs: []string{"zh", "en"}})
+
+	engine := gin.New()
+	engine.GET("/api/test", ginHandleFuncWrapper(&TestGinOperator{}))
+
+	for lang, message := range map[string]string{
+		"zh": "id为必填字段",
+		"en": "id is a required field",
+	} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/test", nil)
+		req.Header.Set(CurrentLangHeader(), lang)
+		engine.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+
+		var resp statuserror.ValidationError
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, int64(40000000001), resp.Code())
+		assert.Equal(t, []statuserror.FieldError{
+			{Field: "id", In: "path", Rule: "required", Message: message},
+		}, resp.Errors)
+	}
+}
+
 func TestGinMiddlewareWrapper_TypeOperator(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	ClearCache()
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/tls"
 	"errors"
+	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
@@ -114,6 +115,12 @@ func RunServer(config *conf.Server, r *GinRouter) {
 
 	showParams = config.ShowParams
 
+	// body limit
+	loadBodyLimit(config)
+
+	// response compression
+	loadCompression(config)
+
 	// init log
 	if config.Log == nil {
 		config.Log = &conf.Log{
@@ -141,18 +148,30 @@ func RunServer(config *conf.Server, r *GinRouter) {
 }
 
 type Server struct {
-	engine  *gin.Engine
-	server  *http.Server
-	watcher service_discovery.ServiceDiscovery
+	engine      *gin.Engine
+	server      *http.Server
+	watcher     service_discovery.ServiceDiscovery
+	serviceInfo *service_discovery.ServiceInfo
 
-	signalWaiter    func(err chan error) error
-	graceCloseHooks []Callback
+	signalWaiter func(err chan error) error
+	lifecycle    lifecycle
 }
 
 type Callback func()
 
+// AddShutdownHook 注册关闭钩子，按注册顺序在请求排空之后执行
+// 如需指定优先级或超时时间，请使用 OnShutdown
 func AddShutdownHook(callback ...Callback) {
-	instance().graceCloseHooks = append(ginx.server.graceCloseHooks, callback...)
+	for _, cb := range callback {
+		if cb == nil {
+			continue
+		}
+		cb := cb
+		instance().lifecycle.addShutdownHook(newHook(func(ctx context.Context) error {
+			cb()
+			return nil
+		}))
+	}
 }
 
 func Register(watcher service_discovery.ServiceDiscovery) {
@@ -167,14 +186,28 @@ func instance() *Server {
 	return ginx.server
 }
 
+// spin 启动服务并阻塞等待退出信号，依次执行：
+// 启动钩子 -> 开始监听 -> 注册服务 -> 等待信号 -> 优雅关闭
 func (s *Server) spin(conf *conf.Server) {
-	errCh := make(chan error)
+	s.prepare(conf)
+
+	if err := s.lifecycle.start(context.Background()); err != nil {
+		logx.Errorf("server start failed: %s", err.Error())
+		s.shutdown(conf)
+		return
+	}
+
+	// 监听协程可能在 spin 返回后才退出，使用本次创建的 http.Server，避免与下一次启动竞争
+	errCh := make(chan error, 1)
+	server := s.server
 	go func() {
-		errCh <- s.run(conf)
+		errCh <- serve(server, conf)
 	}()
 
 	// discovery
-	s.watch(conf)
+	if err := s.watch(conf); err != nil {
+		logx.Errorf("register service failed: %s", err.Error())
+	}
 
 	signalWaiter := waitSignal
 	if s.signalWaiter != nil {
@@ -182,26 +215,20 @@ func (s *Server) spin(conf *conf.Server) {
 	}
 
 	if err := signalWaiter(errCh); err != nil {
-		logx.Errorf("receive close signal: error=%s", err.Error())
-		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(conf.ExitWaitTimeout)*time.Second)
-		defer cancel()
-		s.server.Shutdown(ctx)
-		return
+		logx.Errorf("server exit with error: %s", err.Error())
 	}
+
+	s.shutdown(conf)
 }
 
-func (s *Server) run(conf *conf.Server) (err error) {
+// prepare 校验配置并创建 http.Server
+func (s *Server) prepare(conf *conf.Server) {
 	if conf.Https && (conf.TLS.CertFile == "" || conf.TLS.KeyFile == "") {
 		panic("use https but cert file or key file not set")
 	}
 	addr := conf.Host + ":" + strconv.Itoa(conf.Port)
 	s.server = &http.Server{Addr: addr, Handler: s.engine}
 
-	// hook
-	for _, hook := range s.graceCloseHooks {
-		s.server.RegisterOnShutdown(hook)
-	}
-
 	if conf.Https {
 		s.server.TLSConfig = &tls.Config{
 			InsecureSkipVerify: conf.TLS.InsecureSkipVerify,
@@ -209,19 +236,89 @@ func (s *Server) run(conf *conf.Server) (err error) {
 			MinVersion:         conf.TLS.MinVersion,
 			CipherSuites:       conf.TLS.CipherSuites,
 		}
-		err = s.server.ListenAndServeTLS(conf.TLS.CertFile, conf.TLS.KeyFile)
+	}
+}
+
+// serve 在已创建的 http.Server 上开始监听，主动关闭时返回 nil
+func serve(server *http.Server, conf *conf.Server) (err error) {
+	if conf.Https {
+		err = server.ListenAndServeTLS(conf.TLS.CertFile, conf.TLS.KeyFile)
 	} else {
-		err = s.server.ListenAndServe()
+		err = server.ListenAndServe()
 	}
 
-	if err != nil {
-		return err
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
 	}
 
 	return err
 }
 
+// shutdown 按阶段优雅关闭服务：
+// 1. 标记为不可用，健康检查返回 503
+// 2. 从注册中心摘除
+// 3. 等待负载均衡感知（ShutdownDelay）
+// 4. 在 ExitWaitTimeout 内排空正在处理的请求
+// 5. 按优先级执行关闭钩子
+// 6. 刷新链路追踪与日志
+func (s *Server) shutdown(conf *conf.Server) {
+	s.lifecycle.markShuttingDown()
+	logx.Infof("server is shutting down")
+
+	if err := s.deregister(); err != nil {
+		logx.Errorf("deregister service failed: %s", err.Error())
+	}
+
+	if conf.ShutdownDelay > 0 {
+		time.Sleep(time.Duration(conf.ShutdownDelay) * time.Second)
+	}
+
+	exitWaitTimeout := time.Duration(conf.ExitWaitTimeout) * time.Second
+	if exitWaitTimeout <= 0 {
+		exitWaitTimeout = DefaultExitWaitTimeout
+	}
+
+	if s.server != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), exitWaitTimeout)
+		if err := s.server.Shutdown(ctx); err != nil {
+			logx.Errorf("drain in-flight requests failed: %s", err.Error())
+		}
+		cancel()
+	}
+
+	s.lifecycle.shutdown(context.Background())
+
+	flushCtx, cancel := context.WithTimeout(context.Background(), exitWaitTimeout)
+	defer cancel()
+	if traceAgent != nil {
+		if err := traceAgent.Shutdown(flushCtx); err != nil {
+			logx.Errorf("flush trace failed: %s", err.Error())
+		}
+	}
+
+	logx.Infof("server exited")
+	if err := logx.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "close log failed: %s\n", err.Error())
+	}
+}
+
 func (s *Server) watch(conf *conf.Server) error {
+	if s.watcher == nil && conf.Discovery.Type != "" {
+		watcher, err := service_discovery.New(service_discovery.Config{
+			Type:      conf.Discovery.Type,
+			Endpoints: conf.Discovery.Endpoints,
+			Username:  conf.Discovery.Username,
+			Password:  conf.Discovery.Password,
+			Token:     conf.Discovery.Token,
+			Namespace: conf.Discovery.Namespace,
+			Group:     conf.Discovery.Group,
+		})
+		if err != nil {
+			return err
+		}
+		s.watcher = watcher
+	}
+
 	if s.watcher != nil {
 		info := service_discovery.ServiceInfo{
 			Name:           conf.Name,
@@ -229,40 +326,47 @@ func (s *Server) watch(conf *conf.Server) error {
 			Port:           conf.Port,
 			Tags:           conf.Discovery.Tags,
 			ID:             conf.ID,
+			Meta:           conf.Discovery.Meta,
+			Weight:         conf.Discovery.Weight,
+			CheckType:      conf.Discovery.CheckType,
 			HealthPath:     conf.Discovery.HeathPath,
 			Timeout:        conf.Discovery.Timeout,
 			Interval:       conf.Discovery.Interval,
+			TTL:            conf.Discovery.TTL,
 			DeregisterTime: conf.Discovery.DeregisterTime,
 		}
 		info.Default()
 		if err := s.watcher.Watch(info); err != nil {
 			return err
 		}
+		s.serviceInfo = &info
 	}
 
 	return nil
 }
 
+// deregister 从注册中心摘除服务并停止心跳
+func (s *Server) deregister() error {
+	if s.watcher == nil || s.serviceInfo == nil {
+		return nil
+	}
+	return s.watcher.Deregister(*s.serviceInfo)
+}
+
+// waitSignal 等待退出信号或服务异常退出
+// SIGINT、SIGHUP、SIGTERM 均触发优雅关闭，服务异常退出时返回对应错误
 func waitSignal(errCh chan error) error {
 	signals := make(chan os.Signal, 1)
 	signal.Notify(signals, syscall.SIGINT, syscall.SIGHUP, syscall.SIGTERM)
+	defer signal.Stop(signals)
 
 	select {
 	case sig := <-signals:
-		switch sig {
-		case syscall.SIGTERM:
-			// force exit
-			return errors.New(sig.String())
-		case syscall.SIGHUP, syscall.SIGINT:
-			// graceful shutdown
-			return nil
-		}
+		logx.Infof("receive signal %s, start graceful shutdown", sig.String())
+		return nil
 	case err := <-errCh:
-
 		return err
 	}
-
-	return nil
 }
 
 func initTrace(conf *conf.Server) *trace.Agent {
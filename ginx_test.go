@@ -1,6 +1,7 @@
 package ginx
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -27,9 +28,10 @@ type TestConfig struct {
 
 // MockServiceDiscovery 模拟服务发现
 type MockServiceDiscovery struct {
-	watchCalled bool
-	serviceInfo service_discovery.ServiceInfo
-	shouldError bool
+	watchCalled      bool
+	deregisterCalled bool
+	serviceInfo      service_discovery.ServiceInfo
+	shouldError      bool
 }
 
 func (m *MockServiceDiscovery) Watch(info service_discovery.ServiceInfo) error {
@@ -41,6 +43,12 @@ func (m *MockServiceDiscovery) Watch(info service_discovery.ServiceInfo) error {
 	return nil
 }
 
+func (m *MockServiceDiscovery) Deregister(info service_discovery.ServiceInfo) error {
+	m.deregisterCalled = true
+	m.serviceInfo = info
+	return nil
+}
+
 func TestParse(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -310,10 +318,10 @@ func TestAddShutdownHook(t *testing.T) {
 
 	// 验证钩子已添加
 	server := instance()
-	assert.Len(t, server.graceCloseHooks, 1)
+	assert.Len(t, server.lifecycle.shutdownHooks, 1)
 
 	// 执行钩子
-	server.graceCloseHooks[0]()
+	server.lifecycle.shutdown(context.Background())
 	assert.True(t, called)
 }
 
@@ -397,6 +405,57 @@ func TestServer_watch(t *testing.T) {
 	}
 }
 
+func TestServer_watchByType(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "registry.json")
+	server := &Server{}
+
+	err := server.watch(&conf.Server{
+		Name: "test-service",
+		Port: 8080,
+		Discovery: conf.Discovery{
+			Type:      service_discovery.TypeFile,
+			Endpoints: []string{path},
+			Address:   "http://127.0.0.1",
+		},
+	})
+	require.NoError(t, err)
+	assert.IsType(t, &service_discovery.FileRegistry{}, server.watcher)
+
+	instances, err := service_discovery.NewFileRegistry(path).Resolve(context.Background(), "test-service")
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+	assert.Equal(t, 8080, instances[0].Port)
+
+	require.NoError(t, server.deregister())
+
+	err = (&Server{}).watch(&conf.Server{Discovery: conf.Discovery{Type: "unknown"}})
+	assert.Error(t, err)
+}
+
+func TestServer_shutdownDeregister(t *testing.T) {
+	mockSD := &MockServiceDiscovery{}
+	server := &Server{watcher: mockSD}
+
+	err := server.watch(&conf.Server{
+		Name: "test-service",
+		Port: 8080,
+		Discovery: conf.Discovery{
+			CheckType: service_discovery.CheckTypeTTL,
+			Meta:      map[string]string{"version": "v1"},
+			Weight:    10,
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, service_discovery.CheckTypeTTL, mockSD.serviceInfo.CheckType)
+	assert.Equal(t, 10, mockSD.serviceInfo.Weight)
+	assert.Equal(t, "v1", mockSD.serviceInfo.Meta["version"])
+
+	server.shutdown(&conf.Server{})
+	assert.True(t, mockSD.deregisterCalled)
+	assert.Equal(t, server.serviceInfo.ID, mockSD.serviceInfo.ID)
+	assert.True(t, server.lifecycle.isShuttingDown())
+}
+
 func TestWaitSignal(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -405,10 +464,9 @@ func TestWaitSignal(t *testing.T) {
 		errMessage string
 	}{
 		{
-			name:       "SIGTERM",
-			signal:     syscall.SIGTERM,
-			expectErr:  true,
-			errMessage: "terminated",
+			name:      "SIGTERM",
+			signal:    syscall.SIGTERM,
+			expectErr: false,
 		},
 		{
 			name:      "SIGINT",
@@ -479,77 +537,46 @@ func TestInitTrace(t *testing.T) {
 	})
 }
 
-func TestServer_run(t *testing.T) {
+func TestServer_prepareAndServe(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	server := &Server{
 		engine: gin.New(),
 	}
 
-	tests := []struct {
-		name        string
-		config      *conf.Server
-		expectPanic bool
-	}{
-		{
-			name: "HTTP server",
-			config: &conf.Server{
-				Host:  "localhost",
-				Port:  0, // 使用随机可用端口
-				Https: false,
-			},
-			expectPanic: false,
-		},
-		{
-			name: "HTTPS server without cert files",
-			config: &conf.Server{
+	t.Run("HTTPS server without cert files", func(t *testing.T) {
+		assert.Panics(t, func() {
+			server.prepare(&conf.Server{
 				Host:  "localhost",
 				Port:  0,
 				Https: true,
-				TLS: conf.TLS{
-					CertFile: "",
-					KeyFile:  "",
-				},
-			},
-			expectPanic: true,
-		},
-	}
+			})
+		})
+	})
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if tt.expectPanic {
-				assert.Panics(t, func() {
-					server.run(tt.config)
-				})
-			} else {
-				// 在goroutine中运行服务器
-				done := make(chan error, 1)
-				go func() {
-					done <- server.run(tt.config)
-				}()
-
-				// 立即关闭服务器
-				if server.server != nil {
-					go func() {
-						time.Sleep(10 * time.Millisecond)
-						server.server.Close()
-					}()
-				}
+	t.Run("HTTP server", func(t *testing.T) {
+		config := &conf.Server{
+			Host: "localhost",
+			Port: 0, // 使用随机可用端口
+		}
+		server.prepare(config)
+		require.NotNil(t, server.server)
 
-				// 等待结果
-				select {
-				case err := <-done:
-					// 服务器关闭时会返回错误，这是正常的
-					assert.Error(t, err)
-				case <-time.After(100 * time.Millisecond):
-					// 超时，关闭服务器
-					if server.server != nil {
-						server.server.Close()
-					}
-				}
-			}
-		})
-	}
+		done := make(chan error, 1)
+		go func() {
+			done <- serve(server.server, config)
+		}()
+		time.Sleep(10 * time.Millisecond)
+		require.NoError(t, server.server.Close())
+
+		// 主动关闭时返回 nil
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("serve did not return after close")
+		}
+	})
 }
 
 func TestServer_spin(t *testing.T) {
@@ -37,6 +37,17 @@ type ContentTypeDescriber interface {
 	ContentType() string
 }
 
+// BodyLimitDescriber 操作符的请求体大小上限(字节)，大于 0 时覆盖服务配置的 MaxBodySize，小于 0 表示不限制
+type BodyLimitDescriber interface {
+	BodyLimit() int64
+}
+
+// ConsumesDescriber 操作符接受的请求体类型，如 application/json、image/*，请求的 Content-Type 不符合时返回 415
+// 同时作为文档中 requestBody.content 的类型
+type ConsumesDescriber interface {
+	Consumes() []string
+}
+
 type MineDescriber interface {
 	ContentTypeDescriber
 	Bytes() []byte
@@ -62,6 +73,11 @@ type ResponseBind interface {
 	Bind(interface{}) error
 }
 
+// ResponseMetaDescriber 提供响应元信息，配合 WithResponseMeta 使用
+type ResponseMetaDescriber interface {
+	Meta() ResponseMeta
+}
+
 type Request interface {
 	PathDescriber
 	MethodDescriber
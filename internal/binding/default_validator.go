@@ -91,5 +91,22 @@ func (v *defaultValidator) lazyinit() {
 	v.once.Do(func() {
 		v.validate = validator.New()
 		v.validate.SetTagName("validate")
+		v.validate.RegisterTagNameFunc(fieldName)
+		registerValidations(v.validate)
+		registerTranslations(v.validate)
 	})
 }
+
+// fieldName 校验错误中的字段名与参数名一致，优先使用 name 标签，其次使用 json 标签
+func fieldName(field reflect.StructField) string {
+	for _, key := range []string{"name", "json"} {
+		name, _, _ := strings.Cut(field.Tag.Get(key), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return ""
+}
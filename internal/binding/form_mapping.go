@@ -5,6 +5,7 @@
 package binding
 
 import (
+	"encoding"
 	"errors"
 	"fmt"
 
@@ -193,6 +194,11 @@ func trySetCustom(val string, value reflect.Value) (isSet bool, err error) {
 	switch v := value.Addr().Interface().(type) {
 	case BindUnmarshaler:
 		return true, v.UnmarshalParam(val)
+	case *time.Time:
+		// time.Time keeps using time_format
+		return false, nil
+	case encoding.TextUnmarshaler:
+		return true, v.UnmarshalText(bytesconv.StringToBytes(val))
 	}
 	return false, nil
 }
@@ -462,6 +468,12 @@ func setTimeField(val string, structField reflect.StructField, value reflect.Val
 
 func setArray(vals []string, value reflect.Value, field reflect.StructField) error {
 	for i, s := range vals {
+		if ok, err := trySetCustom(s, value.Index(i)); ok {
+			if err != nil {
+				return err
+			}
+			continue
+		}
 		err := setWithProperType(s, value.Index(i), field)
 		if err != nil {
 			return err
@@ -540,6 +552,11 @@ func ParseSetOptions(structField reflect.StructField) SetOptions {
 	return opt
 }
 
+// HasDefault 是否设置了默认值，参数缺失时使用默认值
+func (opt SetOptions) HasDefault() bool {
+	return opt.isDefaultExists
+}
+
 // SetFieldByForm 直接绑定单个字段，使用 form 数据源
 // value: 字段的 reflect.Value
 // field: 字段的 reflect.StructField（用于标签解析，如 time_format）
@@ -0,0 +1,224 @@
+package binding
+
+import (
+	"strings"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/zh"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+	zh_translations "github.com/go-playground/validator/v10/translations/zh"
+)
+
+// translator 校验错误的翻译器，不支持的语言使用中文
+var translator = ut.New(zh.New(), zh.New(), en.New())
+
+var defaultTranslations = map[string]func(*validator.Validate, ut.Translator) error{
+	"zh": zh_translations.RegisterDefaultTranslations,
+	"en": en_translations.RegisterDefaultTranslations,
+}
+
+// registerTranslations 注册内置校验规则的中英文翻译，validator 未提供翻译的规则与框架内置规则由 extraTranslations 补充
+func registerTranslations(validate *validator.Validate) {
+	for lang, register := range defaultTranslations {
+		trans, _ := translator.GetTranslator(lang)
+		if err := register(validate, trans); err != nil {
+			panic(err)
+		}
+
+		for tag, text := range extraTranslations[lang] {
+			if err := RegisterTranslation(validate, lang, tag, text); err != nil {
+				panic(err)
+			}
+		}
+	}
+}
+
+// RegisterTranslation 注册校验规则的翻译，text 中 {0} 为字段名，{1} 为规则参数
+func RegisterTranslation(validate *validator.Validate, lang, tag, text string) error {
+	trans, found := translator.GetTranslator(lang)
+	if !found {
+		return nil
+	}
+
+	return validate.RegisterTranslation(tag, trans, func(ut ut.Translator) error {
+		return ut.Add(tag, text, true)
+	}, func(ut ut.Translator, fe validator.FieldError) string {
+		msg, err := ut.T(fe.Tag(), fe.Field(), fe.Param())
+		if err != nil {
+			return fe.Error()
+		}
+		return msg
+	})
+}
+
+// Translate 按语言翻译校验错误，规则没有翻译时返回空字符串
+func Translate(lang string, fe validator.FieldError) string {
+	trans, _ := translator.FindTranslator(lang, baseLang(lang))
+	if msg := fe.Translate(trans); msg != fe.Error() {
+		return msg
+	}
+	return ""
+}
+
+// baseLang 返回语言的主标签，如 zh-CN 返回 zh
+func baseLang(lang string) string {
+	if i := strings.IndexAny(lang, "-_"); i > 0 {
+		return lang[:i]
+	}
+	return lang
+}
+
+var extraTranslations = map[string]map[string]string{
+	"en": {
+		"alphanumunicode":            "{0} can only contain unicode alphanumeric characters",
+		"alphaunicode":               "{0} can only contain unicode alphabetic characters",
+		"base32":                     "{0} must be a valid Base32 string",
+		"base64rawurl":               "{0} must be a valid Base64 raw URL string",
+		"base64url":                  "{0} must be a valid Base64 URL string",
+		"bcp47_language_tag":         "{0} must be a valid BCP 47 language tag",
+		"bic":                        "{0} must be a valid BIC (SWIFT) code",
+		"btc_addr":                   "{0} must be a valid Bitcoin address",
+		"btc_addr_bech32":            "{0} must be a valid Bech32 Bitcoin address",
+		"containsrune":               "{0} must contain the character '{1}'",
+		"country_code":               "{0} must be a valid country code",
+		"credit_card":                "{0} must be a valid credit card number",
+		"dir":                        "{0} must be an existing directory",
+		"dirpath":                    "{0} must be a valid directory path",
+		"dns_rfc1035_label":          "{0} must be a valid DNS label",
+		"ein":                        "{0} must be a valid EIN",
+		"endsnotwith":                "{0} cannot end with '{1}'",
+		"endswith":                   "{0} must end with '{1}'",
+		"enum":                       "{0} must be a valid enum value",
+		"eq_ignore_case":             "{0} must be equal to {1} (case-insensitive)",
+		"eth_addr":                   "{0} must be a valid Ethereum address",
+		"eth_addr_checksum":          "{0} must be a valid checksummed Ethereum address",
+		"eu_country_code":            "{0} must be a valid EU country code",
+		"fieldcontains":              "{0} must contain the value of {1}",
+		"fieldexcludes":              "{0} cannot contain the value of {1}",
+		"file":                       "{0} must be an existing file",
+		"filepath":                   "{0} must be a valid file path",
+		"hostname":                   "{0} must be a valid hostname",
+		"hostname_port":              "{0} must be a valid host:port",
+		"hostname_rfc1123":           "{0} must be a valid hostname",
+		"html":                       "{0} must be valid HTML",
+		"html_encoded":               "{0} must be HTML-encoded",
+		"http_url":                   "{0} must be a valid HTTP URL",
+		"iso3166_1_alpha2":           "{0} must be a valid ISO 3166-1 alpha-2 country code",
+		"iso3166_1_alpha2_eu":        "{0} must be a valid ISO 3166-1 alpha-2 EU country code",
+		"iso3166_1_alpha3":           "{0} must be a valid ISO 3166-1 alpha-3 country code",
+		"iso3166_1_alpha3_eu":        "{0} must be a valid ISO 3166-1 alpha-3 EU country code",
+		"iso3166_1_alpha_numeric":    "{0} must be a valid ISO 3166-1 numeric country code",
+		"iso3166_1_alpha_numeric_eu": "{0} must be a valid ISO 3166-1 numeric EU country code",
+		"iso3166_2":                  "{0} must be a valid ISO 3166-2 subdivision code",
+		"iso4217":                    "{0} must be a valid ISO 4217 currency code",
+		"iso4217_numeric":            "{0} must be a valid ISO 4217 numeric currency code",
+		"luhn_checksum":              "{0} must have a valid Luhn checksum",
+		"md4":                        "{0} must be a valid MD4 hash",
+		"md5":                        "{0} must be a valid MD5 hash",
+		"mongodb":                    "{0} must be a valid MongoDB ObjectID",
+		"mongodb_connection_string":  "{0} must be a valid MongoDB connection string",
+		"ne_ignore_case":             "{0} must not be equal to {1} (case-insensitive)",
+		"oneofci":                    "{0} must be one of [{1}] (case-insensitive)",
+		"phone":                      "{0} must be a valid phone number",
+		"port":                       "{0} must be a valid port",
+		"ripemd128":                  "{0} must be a valid RIPEMD-128 hash",
+		"ripemd160":                  "{0} must be a valid RIPEMD-160 hash",
+		"semver":                     "{0} must be a valid semantic version",
+		"sha256":                     "{0} must be a valid SHA256 hash",
+		"sha384":                     "{0} must be a valid SHA384 hash",
+		"sha512":                     "{0} must be a valid SHA512 hash",
+		"skip_unless":                "{0} is a required field",
+		"spicedb":                    "{0} must be a valid SpiceDB identifier",
+		"startsnotwith":              "{0} cannot start with '{1}'",
+		"startswith":                 "{0} must start with '{1}'",
+		"tiger128":                   "{0} must be a valid TIGER128 hash",
+		"tiger160":                   "{0} must be a valid TIGER160 hash",
+		"tiger192":                   "{0} must be a valid TIGER192 hash",
+		"timezone":                   "{0} must be a valid time zone",
+		"url_encoded":                "{0} must be URL-encoded",
+		"uuid3_rfc4122":              "{0} must be a valid RFC 4122 version 3 UUID",
+		"uuid4_rfc4122":              "{0} must be a valid RFC 4122 version 4 UUID",
+		"uuid5_rfc4122":              "{0} must be a valid RFC 4122 version 5 UUID",
+		"uuid_rfc4122":               "{0} must be a valid RFC 4122 UUID",
+	},
+	"zh": {
+		"base32":                        "{0}必须是一个有效的Base32字符串",
+		"base64rawurl":                  "{0}必须是一个有效的无填充Base64 URL字符串",
+		"base64url":                     "{0}必须是一个有效的Base64 URL字符串",
+		"bcp47_language_tag":            "{0}必须是一个有效的BCP 47语言标签",
+		"bic":                           "{0}必须是一个有效的BIC（SWIFT）代码",
+		"boolean":                       "{0}必须是一个有效的布尔值",
+		"btc_addr":                      "{0}必须是一个有效的比特币地址",
+		"btc_addr_bech32":               "{0}必须是一个有效的Bech32比特币地址",
+		"country_code":                  "{0}必须是一个有效的国家代码",
+		"credit_card":                   "{0}必须是一个有效的信用卡号",
+		"cron":                          "{0}必须是一个有效的cron表达式",
+		"cve":                           "{0}必须是一个有效的CVE编号",
+		"dir":                           "{0}必须是一个存在的目录",
+		"dirpath":                       "{0}必须是一个有效的目录路径",
+		"dns_rfc1035_label":             "{0}必须是一个有效的DNS标签",
+		"e164":                          "{0}必须是一个有效的E.164格式电话号码",
+		"ein":                           "{0}必须是一个有效的EIN",
+		"endsnotwith":                   "{0}不能以'{1}'结尾",
+		"enum":                          "{0}必须是有效的枚举值",
+		"eq_ignore_case":                "{0}必须等于{1}（忽略大小写）",
+		"eth_addr":                      "{0}必须是一个有效的以太坊地址",
+		"eth_addr_checksum":             "{0}必须是一个有效的带校验和的以太坊地址",
+		"eu_country_code":               "{0}必须是一个有效的欧盟国家代码",
+		"fieldcontains":                 "{0}必须包含{1}的值",
+		"fieldexcludes":                 "{0}不能包含{1}的值",
+		"file":                          "{0}必须是一个存在的文件",
+		"filepath":                      "{0}必须是一个有效的文件路径",
+		"fqdn":                          "{0}必须是一个有效的完全限定域名",
+		"hostname":                      "{0}必须是一个有效的主机名",
+		"hostname_port":                 "{0}必须是一个有效的主机名:端口",
+		"hostname_rfc1123":              "{0}必须是一个有效的主机名",
+		"html":                          "{0}必须是有效的HTML",
+		"html_encoded":                  "{0}必须是HTML编码的",
+		"http_url":                      "{0}必须是一个有效的HTTP URL",
+		"iso3166_1_alpha2":              "{0}必须是一个有效的ISO 3166-1 alpha-2国家代码",
+		"iso3166_1_alpha2_eu":           "{0}必须是一个有效的ISO 3166-1 alpha-2欧盟国家代码",
+		"iso3166_1_alpha3":              "{0}必须是一个有效的ISO 3166-1 alpha-3国家代码",
+		"iso3166_1_alpha3_eu":           "{0}必须是一个有效的ISO 3166-1 alpha-3欧盟国家代码",
+		"iso3166_1_alpha_numeric":       "{0}必须是一个有效的ISO 3166-1数字国家代码",
+		"iso3166_1_alpha_numeric_eu":    "{0}必须是一个有效的ISO 3166-1数字欧盟国家代码",
+		"iso3166_2":                     "{0}必须是一个有效的ISO 3166-2行政区划代码",
+		"iso4217":                       "{0}必须是一个有效的ISO 4217货币代码",
+		"iso4217_numeric":               "{0}必须是一个有效的ISO 4217数字货币代码",
+		"jwt":                           "{0}必须是一个有效的JWT",
+		"luhn_checksum":                 "{0}必须通过Luhn校验",
+		"md4":                           "{0}必须是一个有效的MD4哈希值",
+		"md5":                           "{0}必须是一个有效的MD5哈希值",
+		"mongodb":                       "{0}必须是一个有效的MongoDB ObjectID",
+		"mongodb_connection_string":     "{0}必须是一个有效的MongoDB连接字符串",
+		"ne_ignore_case":                "{0}不能等于{1}（忽略大小写）",
+		"oneofci":                       "{0}必须是[{1}]中的一个（忽略大小写）",
+		"phone":                         "{0}必须是有效的手机号",
+		"port":                          "{0}必须是一个有效的端口",
+		"postcode_iso3166_alpha2":       "{0}必须是国家{1}的有效邮政编码",
+		"postcode_iso3166_alpha2_field": "{0}必须是{1}中国家的有效邮政编码",
+		"ripemd128":                     "{0}必须是一个有效的RIPEMD-128哈希值",
+		"ripemd160":                     "{0}必须是一个有效的RIPEMD-160哈希值",
+		"semver":                        "{0}必须是一个有效的语义化版本号",
+		"sha256":                        "{0}必须是一个有效的SHA256哈希值",
+		"sha384":                        "{0}必须是一个有效的SHA384哈希值",
+		"sha512":                        "{0}必须是一个有效的SHA512哈希值",
+		"skip_unless":                   "{0}为必填字段",
+		"spicedb":                       "{0}必须是一个有效的SpiceDB标识",
+		"startsnotwith":                 "{0}不能以'{1}'开头",
+		"tiger128":                      "{0}必须是一个有效的TIGER128哈希值",
+		"tiger160":                      "{0}必须是一个有效的TIGER160哈希值",
+		"tiger192":                      "{0}必须是一个有效的TIGER192哈希值",
+		"timezone":                      "{0}必须是一个有效的时区",
+		"unique":                        "{0}不能包含重复的值",
+		"url_encoded":                   "{0}必须是URL编码的",
+		"urn_rfc2141":                   "{0}必须是一个有效的RFC 2141 URN",
+		"uuid3_rfc4122":                 "{0}必须是一个有效的RFC 4122 V3 UUID",
+		"uuid4_rfc4122":                 "{0}必须是一个有效的RFC 4122 V4 UUID",
+		"uuid5_rfc4122":                 "{0}必须是一个有效的RFC 4122 V5 UUID",
+		"uuid_rfc4122":                  "{0}必须是一个有效的RFC 4122 UUID",
+		"validateFn":                    "{0}校验失败",
+	},
+}
@@ -0,0 +1,47 @@
+package binding
+
+import (
+	"regexp"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/shrewx/ginx/pkg/enum"
+)
+
+// PhonePattern 手机号的格式，可带 +86 或 86 前缀
+const PhonePattern = `^(\+?86)?1[3-9]\d{9}$`
+
+var rePhone = regexp.MustCompile(PhonePattern)
+
+// builtinValidations 框架内置的校验规则，cidr、semver 等规则由 validator 提供
+var builtinValidations = map[string]validator.Func{
+	"enum":  isEnum,
+	"phone": isPhone,
+}
+
+func registerValidations(validate *validator.Validate) {
+	for tag, fn := range builtinValidations {
+		if err := validate.RegisterValidation(tag, fn); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// isEnum 校验枚举值是否在 Values() 中，字段类型需实现 enum.Enum
+func isEnum(fl validator.FieldLevel) bool {
+	e, ok := fl.Field().Interface().(enum.Enum)
+	if !ok {
+		return false
+	}
+
+	for _, v := range e.Values() {
+		if v.Int() == e.Int() && v.String() == e.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// isPhone 校验手机号
+func isPhone(fl validator.FieldLevel) bool {
+	return rePhone.MatchString(fl.Field().String())
+}
@@ -36,6 +36,18 @@ const (
 	Conflict StatusError = http.StatusConflict*1e8 + iota + 1
 )
 
+const (
+	// @errZH 请求体过大
+	// @errEN request entity too large
+	RequestEntityTooLarge StatusError = http.StatusRequestEntityTooLarge*1e8 + iota + 1
+)
+
+const (
+	// @errZH 不支持的媒体类型
+	// @errEN unsupported media type
+	UnsupportedMediaType StatusError = http.StatusUnsupportedMediaType*1e8 + iota + 1
+)
+
 const (
 	// @errZH 未知的异常信息：请联系技术服务工程师进行排查
 	// @errEN internal server error
@@ -49,6 +49,10 @@ func (v StatusError) Key() string {
 		return "NotFound"
 	case Conflict:
 		return "Conflict"
+	case RequestEntityTooLarge:
+		return "RequestEntityTooLarge"
+	case UnsupportedMediaType:
+		return "UnsupportedMediaType"
 	case InternalServerError:
 		return "InternalServerError"
 	}
@@ -86,6 +90,8 @@ func GetStatusErrorMap() map[string]map[StatusError]string {
 			Forbidden: "forbidden",
 			NotFound: "not found",
 			Conflict: "conflict",
+			RequestEntityTooLarge: "request entity too large",
+			UnsupportedMediaType: "unsupported media type",
 			InternalServerError: "internal server error",
 			
 		},
@@ -95,6 +101,8 @@ func GetStatusErrorMap() map[string]map[StatusError]string {
 			Forbidden: "禁止操作",
 			NotFound: "资源未找到",
 			Conflict: "资源冲突",
+			RequestEntityTooLarge: "请求体过大",
+			UnsupportedMediaType: "不支持的媒体类型",
 			InternalServerError: "未知的异常信息：请联系技术服务工程师进行排查",
 			
 		},
@@ -2,6 +2,7 @@
 package fields
 
 import (
+	"fmt"
 	"github.com/shrewx/ginx/pkg/i18nx"
 	"github.com/nicksnyder/go-i18n/v2/i18n"
 )
@@ -40,11 +41,15 @@ func (v CommonField) Message(lang string) string {
 func GetCommonFieldMap() map[string]map[CommonField]string {
 	return map[string]map[CommonField]string{
 		"en": {
+			AcceptEncodings: "acceptable encodings",
+			AcceptMediaTypes: "acceptable media types",
 			ErrorIndex: "index",
 			ErrorLine: "line",
 			
 		},
 		"zh": {
+			AcceptEncodings: "支持的编码",
+			AcceptMediaTypes: "支持的类型",
 			ErrorIndex: "索引",
 			ErrorLine: "行",
 			
@@ -58,7 +63,7 @@ func RegisterI18nMessages() {
 	for lang, messages := range messageMap {
 		var i18nMessages []*i18n.Message
 		for key, message := range messages {
-			i18nMessages = append(i18nMessages, &i18n.Message{ID: key.ID(), Other: message})
+			i18nMessages = append(i18nMessages, &i18n.Message{ID: fmt.Sprintf("%s.%s", lang, key.ID()), Other: message})
 		}
 		i18nx.AddMessages(lang, i18nMessages)
 	}
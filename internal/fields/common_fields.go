@@ -11,4 +11,10 @@ const (
 	// @i18nZH 索引
 	// @i18nEN index
 	ErrorIndex CommonField = "err_index"
+	// @i18nZH 支持的类型
+	// @i18nEN acceptable media types
+	AcceptMediaTypes CommonField = "accept"
+	// @i18nZH 支持的编码
+	// @i18nEN acceptable encodings
+	AcceptEncodings CommonField = "accept_encoding"
 )
@@ -18,6 +18,7 @@ const (
 	TracerKey         = "x-tracer-key"
 	RequestContextKey = "x-request-ctx-key"
 	TracerName        = "github.com/shrewx/ginx/tracer"
+	TraceIDHeader     = "X-Trace-Id"
 )
 
 func Telemetry(agent *ptrace.Agent) gin.HandlerFunc {
@@ -56,6 +57,9 @@ func Telemetry(agent *ptrace.Agent) gin.HandlerFunc {
 		}
 
 		ctx, span := tracer.Start(ctx, c.Request.URL.Path, opts...)
+		if spanContext := span.SpanContext(); spanContext.HasTraceID() {
+			c.Header(TraceIDHeader, spanContext.TraceID().String())
+		}
 		defer span.End()
 
 		c.Request = c.Request.WithContext(ctx)
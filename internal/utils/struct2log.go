@@ -3,6 +3,7 @@ package utils
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 )
 
@@ -163,14 +164,26 @@ func formatFieldValueWithFilter(v reflect.Value, parentPath string, noLogPaths m
 		}
 		var builder strings.Builder
 		builder.WriteString("map[")
+		// 与 fmt 保持一致，按 key 排序保证输出稳定
 		keys := v.MapKeys()
+		formattedKeys := make([]string, len(keys))
 		for i, key := range keys {
+			formattedKeys[i] = formatFieldValueWithFilter(key, parentPath, noLogPaths)
+		}
+		order := make([]int, len(keys))
+		for i := range order {
+			order[i] = i
+		}
+		sort.Slice(order, func(i, j int) bool {
+			return formattedKeys[order[i]] < formattedKeys[order[j]]
+		})
+		for i, idx := range order {
 			if i > 0 {
 				builder.WriteString(" ")
 			}
-			builder.WriteString(formatFieldValueWithFilter(key, parentPath, noLogPaths))
+			builder.WriteString(formattedKeys[idx])
 			builder.WriteString(":")
-			builder.WriteString(formatFieldValueWithFilter(v.MapIndex(key), parentPath, noLogPaths))
+			builder.WriteString(formatFieldValueWithFilter(v.MapIndex(keys[idx]), parentPath, noLogPaths))
 		}
 		builder.WriteString("]")
 		return builder.String()
@@ -0,0 +1,168 @@
+package ginx
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/shrewx/ginx/pkg/logx"
+)
+
+const (
+	// DefaultHookTimeout 单个生命周期钩子的默认超时时间
+	DefaultHookTimeout = 10 * time.Second
+	// DefaultExitWaitTimeout 未配置 ExitWaitTimeout 时排空请求的默认等待时间
+	DefaultExitWaitTimeout = 5 * time.Second
+)
+
+// HookFunc 生命周期钩子函数，ctx 会在钩子超时后被取消
+type HookFunc func(ctx context.Context) error
+
+// Hook 生命周期钩子
+type Hook struct {
+	Name     string        // 钩子名称，用于日志
+	Priority int           // 优先级，数值越小越先执行，相同优先级按注册顺序执行
+	Timeout  time.Duration // 单个钩子的超时时间，为 0 时使用 DefaultHookTimeout
+	Fn       HookFunc
+}
+
+// HookOption 用于配置生命周期钩子
+type HookOption func(h *Hook)
+
+// WithHookName 设置钩子名称
+func WithHookName(name string) HookOption {
+	return func(h *Hook) {
+		h.Name = name
+	}
+}
+
+// WithHookPriority 设置钩子优先级
+func WithHookPriority(priority int) HookOption {
+	return func(h *Hook) {
+		h.Priority = priority
+	}
+}
+
+// WithHookTimeout 设置钩子超时时间
+func WithHookTimeout(timeout time.Duration) HookOption {
+	return func(h *Hook) {
+		h.Timeout = timeout
+	}
+}
+
+func newHook(fn HookFunc, opts ...HookOption) *Hook {
+	h := &Hook{Fn: fn}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// OnStart 注册启动钩子
+// 启动钩子在服务开始监听之前按优先级顺序执行，任一钩子失败服务都不会启动
+func OnStart(fn HookFunc, opts ...HookOption) {
+	if fn == nil {
+		return
+	}
+	instance().lifecycle.addStartHook(newHook(fn, opts...))
+}
+
+// OnShutdown 注册关闭钩子
+// 关闭钩子在摘除流量、排空请求之后按优先级顺序执行，单个钩子失败或超时不影响后续钩子
+func OnShutdown(fn HookFunc, opts ...HookOption) {
+	if fn == nil {
+		return
+	}
+	instance().lifecycle.addShutdownHook(newHook(fn, opts...))
+}
+
+// lifecycle 管理服务的启动/关闭钩子以及就绪状态
+type lifecycle struct {
+	mu            sync.Mutex
+	startHooks    []*Hook
+	shutdownHooks []*Hook
+	shuttingDown  atomic.Bool
+}
+
+func (l *lifecycle) addStartHook(hooks ...*Hook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.startHooks = append(l.startHooks, hooks...)
+}
+
+func (l *lifecycle) addShutdownHook(hooks ...*Hook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.shutdownHooks = append(l.shutdownHooks, hooks...)
+}
+
+// sortedHooks 返回按优先级稳定排序后的钩子副本
+func (l *lifecycle) sortedHooks(hooks []*Hook) []*Hook {
+	l.mu.Lock()
+	sorted := make([]*Hook, len(hooks))
+	copy(sorted, hooks)
+	l.mu.Unlock()
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Priority < sorted[j].Priority
+	})
+	return sorted
+}
+
+// start 执行启动钩子，遇到第一个失败的钩子即返回
+func (l *lifecycle) start(ctx context.Context) error {
+	for _, hook := range l.sortedHooks(l.startHooks) {
+		if err := runHook(ctx, hook); err != nil {
+			return fmt.Errorf("start hook %s failed: %w", hook.Name, err)
+		}
+	}
+	return nil
+}
+
+// shutdown 执行关闭钩子，失败的钩子只记录日志
+func (l *lifecycle) shutdown(ctx context.Context) {
+	for _, hook := range l.sortedHooks(l.shutdownHooks) {
+		if err := runHook(ctx, hook); err != nil {
+			logx.Errorf("shutdown hook %s failed: %s", hook.Name, err.Error())
+		}
+	}
+}
+
+// markShuttingDown 标记服务进入关闭流程，健康检查将返回不可用
+func (l *lifecycle) markShuttingDown() {
+	l.shuttingDown.Store(true)
+}
+
+func (l *lifecycle) isShuttingDown() bool {
+	return l.shuttingDown.Load()
+}
+
+// runHook 在独立的超时上下文中执行钩子，并将 panic 转换为错误
+func runHook(ctx context.Context, hook *Hook) error {
+	timeout := hook.Timeout
+	if timeout <= 0 {
+		timeout = DefaultHookTimeout
+	}
+	hookCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		done <- hook.Fn(hookCtx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-hookCtx.Done():
+		return hookCtx.Err()
+	}
+}
@@ -0,0 +1,178 @@
+package ginx
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shrewx/ginx/pkg/conf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLifecycle_ShutdownHookOrder(t *testing.T) {
+	l := &lifecycle{}
+
+	var order []string
+	record := func(name string) HookFunc {
+		return func(ctx context.Context) error {
+			order = append(order, name)
+			return nil
+		}
+	}
+
+	l.addShutdownHook(newHook(record("default-1")))
+	l.addShutdownHook(newHook(record("late"), WithHookPriority(10)))
+	l.addShutdownHook(newHook(record("early"), WithHookPriority(-10)))
+	l.addShutdownHook(newHook(record("default-2")))
+
+	l.shutdown(context.Background())
+
+	assert.Equal(t, []string{"early", "default-1", "default-2", "late"}, order)
+}
+
+func TestLifecycle_HookTimeoutAndPanic(t *testing.T) {
+	l := &lifecycle{}
+
+	var lastCalled bool
+	l.addShutdownHook(newHook(func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(time.Second)
+		return nil
+	}, WithHookName("blocking"), WithHookTimeout(20*time.Millisecond)))
+	l.addShutdownHook(newHook(func(ctx context.Context) error {
+		panic("boom")
+	}, WithHookName("panic")))
+	l.addShutdownHook(newHook(func(ctx context.Context) error {
+		lastCalled = true
+		return nil
+	}, WithHookName("last")))
+
+	start := time.Now()
+	l.shutdown(context.Background())
+
+	assert.True(t, lastCalled, "hooks after a failed hook should still run")
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+func TestLifecycle_StartStopsOnError(t *testing.T) {
+	l := &lifecycle{}
+
+	var secondCalled bool
+	l.addStartHook(newHook(func(ctx context.Context) error {
+		return errors.New("connect db failed")
+	}, WithHookName("db")))
+	l.addStartHook(newHook(func(ctx context.Context) error {
+		secondCalled = true
+		return nil
+	}, WithHookPriority(1)))
+
+	err := l.start(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "db")
+	assert.False(t, secondCalled)
+}
+
+func TestOnStartAndOnShutdown(t *testing.T) {
+	ginx.once = sync.Once{}
+	ginx.server = nil
+
+	OnStart(func(ctx context.Context) error { return nil }, WithHookName("start"))
+	OnShutdown(func(ctx context.Context) error { return nil }, WithHookName("stop"), WithHookPriority(1))
+	OnShutdown(nil)
+
+	server := instance()
+	require.Len(t, server.lifecycle.startHooks, 1)
+	require.Len(t, server.lifecycle.shutdownHooks, 1)
+	assert.Equal(t, "stop", server.lifecycle.shutdownHooks[0].Name)
+	assert.Equal(t, 1, server.lifecycle.shutdownHooks[0].Priority)
+}
+
+func TestHealthDuringShutdown(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ClearCache()
+	ginx.once = sync.Once{}
+	ginx.server = nil
+
+	engine := initGinEngine(NewRouter(&TestGinOperator{}))
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	instance().lifecycle.markShuttingDown()
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestServer_ShutdownDrainsBeforeHooks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, listener.Close())
+
+	var (
+		mu      sync.Mutex
+		events  []string
+		started = make(chan struct{})
+	)
+	record := func(event string) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+	}
+
+	engine := gin.New()
+	engine.GET("/slow", func(c *gin.Context) {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		record("request")
+		c.String(http.StatusOK, "done")
+	})
+
+	server := &Server{engine: engine}
+	server.lifecycle.addShutdownHook(newHook(func(ctx context.Context) error {
+		record("hook")
+		return nil
+	}))
+	server.signalWaiter = func(errCh chan error) error {
+		<-started
+		return nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		server.spin(&conf.Server{Host: "127.0.0.1", Port: port, ExitWaitTimeout: 2})
+	}()
+
+	var resp *http.Response
+	require.Eventually(t, func() bool {
+		resp, err = http.Get("http://127.0.0.1:" + strconv.Itoa(port) + "/slow")
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		t.Fatal("spin did not return after shutdown")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"request", "hook"}, events)
+	assert.True(t, server.lifecycle.isShuttingDown())
+}
@@ -10,8 +10,9 @@ import (
 )
 
 type Attachment struct {
-	filename    string
-	contentType string
+	filename        string
+	contentType     string
+	contentEncoding string
 	bytes.Buffer
 }
 
@@ -28,6 +29,14 @@ func (a *Attachment) Header(ctx *gin.Context) {
 	} else {
 		ctx.Writer.Header().Set("Content-Disposition", `attachment; filename*=UTF-8''`+url.QueryEscape(a.filename))
 	}
+	if a.contentEncoding != "" {
+		ctx.Writer.Header().Set("Content-Encoding", a.contentEncoding)
+	}
+}
+
+// SetContentEncoding 设置已压缩内容的编码（如 gzip），设置后响应不再压缩
+func (a *Attachment) SetContentEncoding(encoding string) {
+	a.contentEncoding = encoding
 }
 
 func (a *Attachment) Bytes() []byte {
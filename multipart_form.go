@@ -0,0 +1,157 @@
+package ginx
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"os"
+	"reflect"
+	"unsafe"
+)
+
+const (
+	// multipartValueMemory 非文件部分额外允许保存在内存中的大小，与标准库一致
+	multipartValueMemory = 10 << 20
+	// multipartMaxParts multipart 表单的部分数量上限，与标准库一致
+	multipartMaxParts = 1000
+)
+
+var (
+	fileHeaderContent = fileHeaderField("content", reflect.TypeOf([]byte(nil)))
+	fileHeaderTmpfile = fileHeaderField("tmpfile", reflect.TypeOf(""))
+	// fileHeaderSettable 能否设置 multipart.FileHeader 的文件内容，不能时只能由标准库写入系统临时目录
+	fileHeaderSettable = fileHeaderContent != nil && fileHeaderTmpfile != nil
+)
+
+// fileHeaderField 获取 multipart.FileHeader 未导出字段的索引，字段不存在或类型不符时返回 nil
+func fileHeaderField(name string, typ reflect.Type) []int {
+	field, ok := reflect.TypeOf(multipart.FileHeader{}).FieldByName(name)
+	if !ok || field.Type != typ {
+		return nil
+	}
+	return field.Index
+}
+
+func setFileHeaderField(fh *multipart.FileHeader, index []int, value interface{}) {
+	field := reflect.ValueOf(fh).Elem().FieldByIndex(index)
+	reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem().Set(reflect.ValueOf(value))
+}
+
+// parseMultipartForm 解析 multipart 表单，超出内存大小的文件写入 multipartTempDir
+// 标准库只会写入系统临时目录，未配置目录时直接使用 http.Request.ParseMultipartForm
+func parseMultipartForm(req *http.Request) error {
+	if multipartTempDir == "" || !fileHeaderSettable || req.MultipartForm != nil {
+		return req.ParseMultipartForm(multipartMemory)
+	}
+
+	var parseFormErr error
+	if req.Form == nil {
+		parseFormErr = req.ParseForm()
+	}
+
+	reader, err := req.MultipartReader()
+	if err != nil {
+		return err
+	}
+	form, err := readMultipartForm(reader, multipartMemory, multipartTempDir)
+	if err != nil {
+		req.MultipartForm = nil
+		return err
+	}
+
+	if req.PostForm == nil {
+		req.PostForm = make(url.Values)
+	}
+	for k, v := range form.Value {
+		req.Form[k] = append(req.Form[k], v...)
+		req.PostForm[k] = append(req.PostForm[k], v...)
+	}
+	req.MultipartForm = form
+	return parseFormErr
+}
+
+// readMultipartForm 与 multipart.Reader.ReadForm 一致，文件总大小超过 maxMemory 时写入 dir 下的临时文件
+func readMultipartForm(reader *multipart.Reader, maxMemory int64, dir string) (_ *multipart.Form, err error) {
+	form := &multipart.Form{
+		Value: make(map[string][]string),
+		File:  make(map[string][]*multipart.FileHeader),
+	}
+	defer func() {
+		if err != nil {
+			_ = form.RemoveAll()
+		}
+	}()
+
+	maxValueBytes := maxMemory + multipartValueMemory
+	for parts := 0; ; parts++ {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return form, nil
+		}
+		if err != nil {
+			return nil, streamError(err)
+		}
+		if parts >= multipartMaxParts {
+			return nil, multipart.ErrMessageTooLarge
+		}
+
+		name := part.FormName()
+		if name == "" {
+			continue
+		}
+
+		var buf bytes.Buffer
+		if part.FileName() == "" {
+			// 非文件部分保存在内存中
+			n, err := io.CopyN(&buf, part, maxValueBytes+1)
+			if err != nil && err != io.EOF {
+				return nil, streamError(err)
+			}
+			maxValueBytes -= n
+			if maxValueBytes < 0 {
+				return nil, multipart.ErrMessageTooLarge
+			}
+			form.Value[name] = append(form.Value[name], buf.String())
+			continue
+		}
+
+		fh := &multipart.FileHeader{
+			Filename: part.FileName(),
+			Header:   textproto.MIMEHeader(part.Header),
+		}
+		n, err := io.CopyN(&buf, part, maxMemory+1)
+		if err != nil && err != io.EOF {
+			return nil, streamError(err)
+		}
+		if n <= maxMemory {
+			maxMemory -= n
+			fh.Size = n
+			setFileHeaderField(fh, fileHeaderContent, buf.Bytes())
+			form.File[name] = append(form.File[name], fh)
+			continue
+		}
+
+		// 超出内存大小，写入临时文件，先加入表单以便出错时删除
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return nil, err
+		}
+		file, err := os.CreateTemp(dir, "multipart-")
+		if err != nil {
+			return nil, err
+		}
+		setFileHeaderField(fh, fileHeaderTmpfile, file.Name())
+		form.File[name] = append(form.File[name], fh)
+
+		size, err := io.Copy(file, io.MultiReader(&buf, part))
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return nil, streamError(err)
+		}
+		fh.Size = size
+	}
+}
@@ -0,0 +1,136 @@
+package ginx
+
+import (
+	"bufio"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	e2 "github.com/shrewx/ginx/internal/errors"
+)
+
+// sniffLen 识别文件类型读取的字节数，与 http.DetectContentType 一致
+const sniffLen = 512
+
+var multipartStreamType = reflect.TypeOf(MultipartStream{})
+
+// MultipartStream 流式读取的 multipart 请求体，字段声明为 in:"multipart"
+// 绑定时不解析整个表单，由 Output 通过 NextPart 逐个读取，适用于大文件上传
+// mime 标签限制文件类型（按文件内容识别，支持 image/* 形式），max_size 标签限制单个文件的大小(字节)
+//
+//	type Upload struct {
+//		ginx.MethodPost
+//		Files ginx.MultipartStream `in:"multipart" name:"file" mime:"image/png,image/jpeg" max_size:"10485760"`
+//	}
+type MultipartStream struct {
+	reader  *multipart.Reader
+	accept  []string
+	maxSize int64
+}
+
+// NextPart 读取下一个部分，读取完成时返回 io.EOF
+// 文件类型不符合时返回 415 错误，读取文件超过大小限制时返回 413 错误
+func (s *MultipartStream) NextPart() (*MultipartPart, error) {
+	if s.reader == nil {
+		return nil, io.EOF
+	}
+
+	part, err := s.reader.NextPart()
+	if err != nil {
+		return nil, streamError(err)
+	}
+
+	p := &MultipartPart{Part: part, reader: part}
+	if part.FileName() == "" {
+		return p, nil
+	}
+
+	// 按文件头部识别类型，读取的内容保留在缓冲中
+	buf := bufio.NewReaderSize(part, sniffLen)
+	head, err := buf.Peek(sniffLen)
+	if err != nil && err != io.EOF {
+		return nil, streamError(err)
+	}
+	p.contentType = http.DetectContentType(head)
+	if !s.accepts(p.contentType) {
+		return nil, e2.UnsupportedMediaType
+	}
+
+	p.reader = buf
+	p.maxSize = s.maxSize
+	return p, nil
+}
+
+func (s *MultipartStream) accepts(contentType string) bool {
+	if len(s.accept) == 0 {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return matchMediaType(s.accept, mediaType)
+}
+
+// MultipartPart multipart 请求体中的一个部分，读取文件时校验大小
+type MultipartPart struct {
+	*multipart.Part
+
+	reader      io.Reader
+	contentType string
+	maxSize     int64
+	size        int64
+}
+
+// ContentType 按文件内容识别的类型，非文件部分为空
+func (p *MultipartPart) ContentType() string {
+	return p.contentType
+}
+
+func (p *MultipartPart) Read(b []byte) (int, error) {
+	n, err := p.reader.Read(b)
+	p.size += int64(n)
+	if over := p.size - p.maxSize; p.maxSize > 0 && over > 0 {
+		return max(n-int(over), 0), e2.RequestEntityTooLarge
+	}
+	return n, streamError(err)
+}
+
+// streamError 读取请求体超过大小限制时转换为 413 错误
+func streamError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return e2.RequestEntityTooLarge
+	}
+	return err
+}
+
+// bindMultipartStream 绑定流式读取的 multipart 请求体
+func bindMultipartStream(ctx *gin.Context, fieldValue reflect.Value, field FieldInfo) error {
+	reader, err := ctx.Request.MultipartReader()
+	if err != nil {
+		return err
+	}
+
+	stream := MultipartStream{reader: reader}
+	if mimeTag := field.StructField.Tag.Get("mime"); mimeTag != "" {
+		for _, accept := range strings.Split(mimeTag, ",") {
+			stream.accept = append(stream.accept, strings.TrimSpace(accept))
+		}
+	}
+	if sizeTag := field.StructField.Tag.Get("max_size"); sizeTag != "" {
+		if stream.maxSize, err = strconv.ParseInt(sizeTag, 10, 64); err != nil {
+			return err
+		}
+	}
+
+	fieldValue.Set(reflect.ValueOf(stream))
+	return nil
+}
@@ -0,0 +1,112 @@
+package ginx
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shrewx/ginx/pkg/conf"
+	"github.com/shrewx/ginx/pkg/i18nx"
+	"github.com/shrewx/ginx/pkg/statuserror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStreamUploadOperator 流式上传的操作符
+type TestStreamUploadOperator struct {
+	MethodPost
+	Files MultipartStream `in:"multipart" name:"file" mime:"image/png,text/*" max_size:"64"`
+}
+
+func (t *TestStreamUploadOperator) Path() string { return "/api/stream" }
+
+func (t *TestStreamUploadOperator) Output(ctx *gin.Context) (interface{}, error) {
+	var result []string
+	for {
+		part, err := t.Files.NextPart()
+		if err == io.EOF {
+			return result, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := io.ReadAll(part)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, part.FormName()+":"+part.ContentType()+":"+strconv.Itoa(len(data)))
+	}
+}
+
+func TestMultipartStream(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	i18nx.Load(&conf.I18N{Langs: []string{"zh", "en"}})
+
+	engine := gin.New()
+	engine.POST("/api/stream", ginHandleFuncWrapper(&TestStreamUploadOperator{}))
+
+	png := append([]byte("\x89PNG\x0D\x0A\x1A\x0A"), bytes.Repeat([]byte{0}, 8)...)
+
+	upload := func(files map[string][]byte) *httptest.ResponseRecorder {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		require.NoError(t, writer.WriteField("name", "avatar"))
+		for filename, data := range files {
+			part, err := writer.CreateFormFile("file", filename)
+			require.NoError(t, err)
+			_, err = part.Write(data)
+			require.NoError(t, err)
+		}
+		require.NoError(t, writer.Close())
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/stream", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		req.Header.Set(CurrentLangHeader(), "en")
+		engine.ServeHTTP(w, req)
+		return w
+	}
+
+	errorCode := func(w *httptest.ResponseRecorder) int64 {
+		var resp statuserror.StatusErr
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.Code()
+	}
+
+	t.Run("read parts", func(t *testing.T) {
+		w := upload(map[string][]byte{"a.png": png})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var result []string
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		assert.Equal(t, []string{"name::6", "file:image/png:16"}, result)
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		// 按内容识别类型，与文件名无关
+		w := upload(map[string][]byte{"a.png": []byte("%PDF-1.4 fake")})
+		require.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+		assert.Equal(t, int64(41500000001), errorCode(w))
+	})
+
+	t.Run("file too large", func(t *testing.T) {
+		w := upload(map[string][]byte{"a.txt": bytes.Repeat([]byte("a"), 65)})
+		require.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		assert.Equal(t, int64(41300000001), errorCode(w))
+	})
+
+	t.Run("not multipart", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/stream", bytes.NewBufferString("{}"))
+		req.Header.Set("Content-Type", MineApplicationJson)
+		engine.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
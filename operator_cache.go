@@ -3,6 +3,7 @@ package ginx
 import (
 	"fmt"
 	"reflect"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -16,12 +17,22 @@ var methodDescriberType = reflect.TypeOf((*MethodDescriber)(nil)).Elem()
 // FieldInfo 缓存的字段信息
 type FieldInfo struct {
 	Index       int                 // 字段索引
+	IndexPath   []int               // 从操作符结构体到字段的索引路径，嵌入结构体的字段包含多级索引
 	In          string              // 参数来源 (query, path, body, etc.)
+	Sources     []string            // 多个参数来源时按优先级排列，如 in:"header,query"
 	ParamName   string              // 参数名称
 	StructField reflect.StructField // 字段的完整结构
 	Path        string              // 字段路径，用于嵌套结构体，如 "Body.Comment"
 }
 
+// indexPath 返回字段的索引路径，未设置时为一级字段
+func (f FieldInfo) indexPath() []int {
+	if len(f.IndexPath) == 0 {
+		return []int{f.Index}
+	}
+	return f.IndexPath
+}
+
 // LimitedPool 带大小限制的对象池
 // 解决高 QPS 后对象池积累过多对象导致内存占用过高的问题
 type LimitedPool struct {
@@ -132,8 +143,8 @@ func resetOperatorInstance(instance interface{}, info *OperatorTypeInfo) {
 	// 重置所有字段为零值，确保实例状态清洁
 	// 只重置可设置的字段，避免对不可导出字段的操作
 	for _, field := range info.Fields {
-		// 检查索引是否有效
-		if field.Index >= v.NumField() {
+		// 检查索引是否有效，嵌套字段随所在的一级字段一起重置
+		if field.Index >= v.NumField() || len(field.IndexPath) > 1 {
 			continue
 		}
 
@@ -234,11 +245,11 @@ func parseOperatorType(operatorType reflect.Type) *OperatorTypeInfo {
 
 // parseFields 解析结构体字段（支持嵌套结构体）
 func parseFields(structType reflect.Type, info *OperatorTypeInfo) {
-	parseFieldsRecursive(structType, info, "", make(map[reflect.Type]bool))
+	parseFieldsRecursive(structType, info, "", nil, make(map[reflect.Type]bool))
 }
 
 // parseFieldsRecursive 递归解析结构体字段
-func parseFieldsRecursive(structType reflect.Type, info *OperatorTypeInfo, prefix string, visited map[reflect.Type]bool) {
+func parseFieldsRecursive(structType reflect.Type, info *OperatorTypeInfo, prefix string, indexPrefix []int, visited map[reflect.Type]bool) {
 	// 避免循环引用导致的无限递归
 	if visited[structType] {
 		return
@@ -271,23 +282,16 @@ func parseFieldsRecursive(structType reflect.Type, info *OperatorTypeInfo, prefi
 
 		fieldInfo := FieldInfo{
 			Index:       i,
+			IndexPath:   append(append([]int{}, indexPrefix...), i),
 			StructField: field,
 			Path:        fieldPath,
 		}
 
 		// 解析标签
 		if tag, ok := field.Tag.Lookup("in"); ok {
-			fieldInfo.In = tag
+			fieldInfo.In, fieldInfo.Sources = parseSources(tag, field)
 
-			// 解析参数名称
-			if name := field.Tag.Get("name"); name != "" {
-				fieldInfo.ParamName = name
-			} else if jsonName := field.Tag.Get("json"); jsonName != "" {
-				fieldInfo.ParamName = jsonName
-			} else {
-				// 默认使用小写首字母的字段名
-				fieldInfo.ParamName = toLowerFirst(field.Name)
-			}
+			fieldInfo.ParamName = parseParamName(field)
 		}
 
 		// 解析 log 标签，如果值为 "-" 则添加到 NoLogFields，否则添加到 Fields
@@ -311,14 +315,59 @@ func parseFieldsRecursive(structType reflect.Type, info *OperatorTypeInfo, prefi
 		}
 		// 如果是结构体类型，递归处理
 		if fieldType.Kind() == reflect.Struct {
-			// 跳过匿名嵌入字段的递归（避免重复）
-			if !field.Anonymous {
-				parseFieldsRecursive(fieldType, info, fieldPath, visited)
+			// 匿名嵌入且没有 in 标签的结构体或结构体指针（如分页、排序参数）展开绑定其字段
+			if field.Anonymous {
+				if fieldInfo.In == "" {
+					parseFieldsRecursive(fieldType, info, fieldPath, fieldInfo.IndexPath, visited)
+				}
+			} else {
+				parseFieldsRecursive(fieldType, info, fieldPath, fieldInfo.IndexPath, visited)
 			}
 		}
 	}
 }
 
+// paramSourceIns 支持声明多个来源的参数位置与 OpenAPI 参数位置的对应关系
+var paramSourceIns = map[string]string{
+	Path:    "path",
+	Query:   "query",
+	Head:    "header",
+	Cookies: "cookie",
+}
+
+// ParamSourceIn 返回 in 标签中支持多个来源的参数位置对应的 OpenAPI 参数位置，如 cookies 对应 cookie
+func ParamSourceIn(source string) (string, bool) {
+	in, ok := paramSourceIns[source]
+	return in, ok
+}
+
+// parseSources 解析 in 标签，多个来源以逗号分隔，第一个来源为默认来源
+func parseSources(tag string, field reflect.StructField) (string, []string) {
+	if !strings.Contains(tag, ",") {
+		return tag, nil
+	}
+
+	sources := strings.Split(tag, ",")
+	for i := range sources {
+		sources[i] = strings.TrimSpace(sources[i])
+		if _, ok := ParamSourceIn(sources[i]); !ok {
+			panic(fmt.Sprintf("field %s: in %q does not support multiple sources", field.Name, sources[i]))
+		}
+	}
+	return sources[0], sources
+}
+
+// parseParamName 解析参数名称：优先使用 name 标签，其次 json 标签，最后使用小写首字母的字段名
+// 标签中逗号之后的部分（如 default=1、omitempty）为选项，不属于参数名称
+func parseParamName(field reflect.StructField) string {
+	for _, key := range []string{"name", "json"} {
+		if name, _, _ := strings.Cut(field.Tag.Get(key), ","); name != "" {
+			return name
+		}
+	}
+	return toLowerFirst(field.Name)
+}
+
 // toLowerFirst 将首字母转换为小写
 func toLowerFirst(s string) string {
 	if len(s) == 0 {
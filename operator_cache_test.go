@@ -194,6 +194,29 @@ func TestToLowerFirst(t *testing.T) {
 	}
 }
 
+func TestParamSourceIn(t *testing.T) {
+	tests := []struct {
+		source string
+		in     string
+		ok     bool
+	}{
+		{"path", "path", true},
+		{"query", "query", true},
+		{"header", "header", true},
+		{"cookies", "cookie", true},
+		{"body", "", false},
+		{"multipart", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			in, ok := ParamSourceIn(tt.source)
+			assert.Equal(t, tt.in, in)
+			assert.Equal(t, tt.ok, ok)
+		})
+	}
+}
+
 func TestClearCache(t *testing.T) {
 	// 添加一些缓存项
 	opType := reflect.TypeOf((*TestOperator)(nil))
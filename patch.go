@@ -0,0 +1,580 @@
+package ginx
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/pkg/errors"
+	e2 "github.com/shrewx/ginx/internal/errors"
+)
+
+// patchValidator 只校验请求中出现字段的请求体
+type patchValidator interface {
+	validatePresent(validate *validator.Validate) error
+}
+
+// MergePatch JSON Merge Patch (RFC 7396) 请求体，字段声明为 in:"body"
+// 请求体只解析一次，Value 为解析后的值，同时记录请求中出现的字段，用于区分未传入与零值
+// 校验时只校验请求中出现的字段，值为 null 的字段表示删除
+//
+//	type UpdateUser struct {
+//		ginx.MethodPatch
+//		ID   uint64                     `in:"path" name:"id"`
+//		Data ginx.MergePatch[UserPatch] `in:"body"`
+//	}
+type MergePatch[T any] struct {
+	Value T `validate:"-"`
+
+	raw map[string]json.RawMessage
+}
+
+func (p *MergePatch[T]) UnmarshalJSON(data []byte) error {
+	raw := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	p.Value, p.raw = value, raw
+	return nil
+}
+
+func (p MergePatch[T]) MarshalJSON() ([]byte, error) {
+	if p.raw == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(p.raw)
+}
+
+// Has 请求中是否包含字段，field 为 JSON 字段名
+func (p MergePatch[T]) Has(field string) bool {
+	_, ok := p.raw[field]
+	return ok
+}
+
+// IsNull 请求中字段的值是否为 null，即删除该字段
+func (p MergePatch[T]) IsNull(field string) bool {
+	value, ok := p.raw[field]
+	return ok && isJSONNull(value)
+}
+
+// Fields 请求中出现的字段，按字段名排序
+func (p MergePatch[T]) Fields() []string {
+	fields := make([]string, 0, len(p.raw))
+	for field := range p.raw {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// Apply 将补丁合并到 target 中，target 为结构体指针，字段按 JSON 字段名对应
+// 值为 null 的字段被重置为零值，嵌套对象按 RFC 7396 递归合并，未出现的字段保持不变
+func (p MergePatch[T]) Apply(target interface{}) error {
+	doc, err := jsonDocument(target)
+	if err != nil {
+		return err
+	}
+
+	fields := p.Fields()
+	for _, field := range fields {
+		var patch interface{}
+		if err := unmarshalJSONNumber(p.raw[field], &patch); err != nil {
+			return err
+		}
+		if patch == nil {
+			delete(doc, field)
+			continue
+		}
+		doc[field] = mergePatch(doc[field], patch)
+	}
+	return applyDocument(target, doc, fields)
+}
+
+func (p *MergePatch[T]) validatePresent(validate *validator.Validate) error {
+	typ := reflect.TypeOf(p.Value)
+	present := presentFields(typ, p.raw)
+	err := validate.StructFiltered(&p.Value, func(ns []byte) bool {
+		// 去掉命名空间中的结构体名，按第一级字段过滤
+		path := strings.TrimPrefix(string(ns), typ.Name()+".")
+		name, _, _ := strings.Cut(path, ".")
+		name, _, _ = strings.Cut(name, "[")
+		return !present[name]
+	})
+	var invalidErr *validator.InvalidValidationError
+	if errors.As(err, &invalidErr) {
+		return nil
+	}
+	return err
+}
+
+// presentFields 请求中出现的字段对应的结构体字段名，嵌入的结构体按展开后的字段计算
+func presentFields(typ reflect.Type, raw map[string]json.RawMessage) map[string]bool {
+	present := map[string]bool{}
+	if typ.Kind() != reflect.Struct {
+		return present
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name, ok := jsonFieldName(field)
+		if !ok {
+			continue
+		}
+		if name == "" {
+			// 嵌入的结构体中有字段出现时校验嵌入的结构体
+			if len(presentFields(indirectType(field.Type), raw)) > 0 {
+				present[field.Name] = true
+			}
+			continue
+		}
+		if _, ok := raw[name]; ok {
+			present[field.Name] = true
+		}
+	}
+	return present
+}
+
+// JSONPatchOperation JSON Patch (RFC 6902) 中的一个操作
+type JSONPatchOperation struct {
+	// 操作类型：add、remove、replace、move、copy、test
+	Op string `json:"op"`
+	// 操作的位置，为 JSON Pointer，如 /tags/0
+	Path string `json:"path"`
+	// move 与 copy 操作的来源位置
+	From string `json:"from,omitempty"`
+	// add、replace 与 test 操作的值
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// JSONPatch JSON Patch (RFC 6902) 请求体，字段声明为 in:"body"
+// 操作按顺序执行，任一操作失败时不修改 target
+type JSONPatch []JSONPatchOperation
+
+func (p *JSONPatch) UnmarshalJSON(data []byte) error {
+	var operations []JSONPatchOperation
+	if err := json.Unmarshal(data, &operations); err != nil {
+		return err
+	}
+	for i, op := range operations {
+		switch op.Op {
+		case "add", "replace", "test":
+			if op.Value == nil {
+				return errors.Errorf("missing value of operation %d", i)
+			}
+		case "move", "copy":
+			if _, err := parsePointer(op.From); err != nil {
+				return err
+			}
+		case "remove":
+		default:
+			return errors.Errorf("unsupported operation %q", op.Op)
+		}
+		if _, err := parsePointer(op.Path); err != nil {
+			return err
+		}
+	}
+	*p = operations
+	return nil
+}
+
+// Fields 操作修改的字段，为 path 与 from 的第一级字段名，按字段名排序
+func (p JSONPatch) Fields() []string {
+	set := map[string]bool{}
+	for _, op := range p {
+		if op.Op == "test" {
+			continue
+		}
+		for _, pointer := range []string{op.Path, op.From} {
+			if tokens, err := parsePointer(pointer); err == nil && len(tokens) > 0 {
+				set[tokens[0]] = true
+			}
+		}
+	}
+
+	fields := make([]string, 0, len(set))
+	for field := range set {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// Apply 按顺序将操作应用到 target 中，target 为结构体指针，字段按 JSON 字段名对应
+// 不支持对整个文档的操作，操作失败时返回参数错误
+func (p JSONPatch) Apply(target interface{}) error {
+	doc, err := jsonDocument(target)
+	if err != nil {
+		return err
+	}
+
+	var root interface{} = doc
+	for _, op := range p {
+		if root, err = applyOperation(root, op); err != nil {
+			return e2.BadRequest.WithField("body", op.Path)
+		}
+	}
+	return applyDocument(target, root.(map[string]interface{}), p.Fields())
+}
+
+func applyOperation(doc interface{}, op JSONPatchOperation) (interface{}, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+	if len(path) == 0 {
+		return nil, errors.New("operation on the whole document is not supported")
+	}
+
+	var value interface{}
+	switch op.Op {
+	case "add", "replace", "test":
+		if err := unmarshalJSONNumber(op.Value, &value); err != nil {
+			return nil, err
+		}
+	case "move", "copy":
+		from, err := parsePointer(op.From)
+		if err != nil || len(from) == 0 {
+			return nil, errors.New("invalid from")
+		}
+		if value, err = pointerGet(doc, from); err != nil {
+			return nil, err
+		}
+		if op.Op == "move" {
+			if doc, err = pointerRemove(doc, from); err != nil {
+				return nil, err
+			}
+		} else {
+			value = deepCopy(value)
+		}
+	}
+
+	switch op.Op {
+	case "add", "move", "copy":
+		return pointerAdd(doc, path, value)
+	case "remove":
+		return pointerRemove(doc, path)
+	case "replace":
+		if doc, err = pointerRemove(doc, path); err != nil {
+			return nil, err
+		}
+		return pointerAdd(doc, path, value)
+	case "test":
+		current, err := pointerGet(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !jsonEqual(current, value) {
+			return nil, errors.New("test failed")
+		}
+		return doc, nil
+	}
+	return nil, errors.Errorf("unsupported operation %q", op.Op)
+}
+
+// jsonEqual 按 RFC 6902 比较两个 JSON 值，数字按数值比较，如 1 与 1.0 相等
+func jsonEqual(a, b interface{}) bool {
+	switch x := a.(type) {
+	case json.Number:
+		y, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		rx, okx := new(big.Rat).SetString(string(x))
+		ry, oky := new(big.Rat).SetString(string(y))
+		return okx && oky && rx.Cmp(ry) == 0
+	case map[string]interface{}:
+		y, ok := b.(map[string]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for k, v := range x {
+			if w, ok := y[k]; !ok || !jsonEqual(v, w) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		y, ok := b.([]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !jsonEqual(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// parsePointer 解析 JSON Pointer (RFC 6901)
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, errors.Errorf("invalid json pointer %q", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func pointerGet(doc interface{}, path []string) (interface{}, error) {
+	for _, token := range path {
+		switch node := doc.(type) {
+		case map[string]interface{}:
+			value, ok := node[token]
+			if !ok {
+				return nil, errors.Errorf("path %q not found", token)
+			}
+			doc = value
+		case []interface{}:
+			i, err := arrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			doc = node[i]
+		default:
+			return nil, errors.Errorf("path %q not found", token)
+		}
+	}
+	return doc, nil
+}
+
+// pointerAdd 在 path 处添加值，数组中插入到指定位置，- 表示追加到末尾
+func pointerAdd(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	return pointerUpdate(doc, path, func(parent interface{}, token string) (interface{}, error) {
+		switch node := parent.(type) {
+		case map[string]interface{}:
+			node[token] = value
+			return node, nil
+		case []interface{}:
+			if token == "-" {
+				return append(node, value), nil
+			}
+			i, err := arrayIndex(token, len(node))
+			if err != nil {
+				return nil, err
+			}
+			node = append(node, nil)
+			copy(node[i+1:], node[i:])
+			node[i] = value
+			return node, nil
+		}
+		return nil, errors.Errorf("path %q not found", token)
+	})
+}
+
+func pointerRemove(doc interface{}, path []string) (interface{}, error) {
+	return pointerUpdate(doc, path, func(parent interface{}, token string) (interface{}, error) {
+		switch node := parent.(type) {
+		case map[string]interface{}:
+			if _, ok := node[token]; !ok {
+				return nil, errors.Errorf("path %q not found", token)
+			}
+			delete(node, token)
+			return node, nil
+		case []interface{}:
+			i, err := arrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			return append(node[:i], node[i+1:]...), nil
+		}
+		return nil, errors.Errorf("path %q not found", token)
+	})
+}
+
+// pointerUpdate 修改 path 的父节点，数组长度变化时替换父节点中的数组
+func pointerUpdate(doc interface{}, path []string, update func(parent interface{}, token string) (interface{}, error)) (interface{}, error) {
+	if len(path) == 1 {
+		return update(doc, path[0])
+	}
+
+	child, err := pointerGet(doc, path[:1])
+	if err != nil {
+		return nil, err
+	}
+	if child, err = pointerUpdate(child, path[1:], update); err != nil {
+		return nil, err
+	}
+
+	switch node := doc.(type) {
+	case map[string]interface{}:
+		node[path[0]] = child
+	case []interface{}:
+		i, _ := arrayIndex(path[0], len(node)-1)
+		node[i] = child
+	}
+	return doc, nil
+}
+
+func arrayIndex(token string, max int) (int, error) {
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i > max || (len(token) > 1 && token[0] == '0') {
+		return 0, errors.Errorf("invalid array index %q", token)
+	}
+	return i, nil
+}
+
+// mergePatch 按 RFC 7396 将 patch 合并到 doc 中
+func mergePatch(doc interface{}, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	docObject, ok := doc.(map[string]interface{})
+	if !ok {
+		docObject = map[string]interface{}{}
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(docObject, key)
+		} else {
+			docObject[key] = mergePatch(docObject[key], value)
+		}
+	}
+	return docObject
+}
+
+func deepCopy(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[key] = deepCopy(item)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, item := range v {
+			s[i] = deepCopy(item)
+		}
+		return s
+	}
+	return value
+}
+
+// jsonDocument 将结构体指针转换为 JSON 对象
+func jsonDocument(target interface{}) (map[string]interface{}, error) {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return nil, errors.Errorf("patch target must be a non-nil pointer to struct, got %T", target)
+	}
+
+	data, err := json.Marshal(target)
+	if err != nil {
+		return nil, err
+	}
+	doc := map[string]interface{}{}
+	if err := unmarshalJSONNumber(data, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// applyDocument 将 JSON 对象中修改的字段写回 target，修改的字段先重置为零值，删除的字段保持零值
+func applyDocument(target interface{}, doc map[string]interface{}, fields []string) error {
+	changed := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		if value, ok := doc[field]; ok {
+			changed[field] = value
+		}
+	}
+	data, err := json.Marshal(changed)
+	if err != nil {
+		return err
+	}
+
+	// 先解析到副本中，失败时不修改 target
+	rv := reflect.ValueOf(target).Elem()
+	result := reflect.New(rv.Type())
+	result.Elem().Set(rv)
+	for _, field := range fields {
+		if fv, ok := jsonField(result.Elem(), field); ok {
+			fv.Set(reflect.Zero(fv.Type()))
+		}
+	}
+	if err := json.Unmarshal(data, result.Interface()); err != nil {
+		return err
+	}
+	rv.Set(result.Elem())
+	return nil
+}
+
+// jsonField 按 JSON 字段名查找结构体字段，包括嵌入结构体中的字段
+func jsonField(v reflect.Value, name string) (reflect.Value, bool) {
+	typ := v.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		fieldName, ok := jsonFieldName(field)
+		if !ok {
+			continue
+		}
+		if fieldName == "" {
+			fv := v.Field(i)
+			if fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					continue
+				}
+				fv = fv.Elem()
+			}
+			if f, ok := jsonField(fv, name); ok {
+				return f, true
+			}
+			continue
+		}
+		if fieldName == name {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// jsonFieldName 字段序列化后的名称，嵌入的结构体返回空，不参与序列化的字段返回 false
+func jsonFieldName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if field.Anonymous && name == "" && indirectType(field.Type).Kind() == reflect.Struct {
+		return "", true
+	}
+	if !field.IsExported() {
+		return "", false
+	}
+	if name == "" {
+		name = field.Name
+	}
+	return name, true
+}
+
+func indirectType(typ reflect.Type) reflect.Type {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ
+}
+
+func isJSONNull(data json.RawMessage) bool {
+	return bytes.Equal(bytes.TrimSpace(data), []byte("null"))
+}
+
+func unmarshalJSONNumber(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
@@ -0,0 +1,195 @@
+package ginx
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shrewx/ginx/pkg/statuserror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testArticle struct {
+	Title   string            `json:"title" validate:"required,max=8"`
+	Content string            `json:"content" validate:"required"`
+	Tags    []string          `json:"tags"`
+	Meta    map[string]string `json:"meta"`
+	Author  testAuthor        `json:"author"`
+}
+
+type testAuthor struct {
+	Name  string `json:"name"`
+	Email string `json:"email" validate:"omitempty,email"`
+}
+
+// TestMergePatchOperator 部分更新的操作符
+type TestMergePatchOperator struct {
+	MethodPatch
+	Data MergePatch[testArticle] `in:"body"`
+}
+
+func (t *TestMergePatchOperator) Path() string { return "/api/articles" }
+
+func (t *TestMergePatchOperator) Output(ctx *gin.Context) (interface{}, error) {
+	article := testArticle{Title: "old", Content: "content", Tags: []string{"a"}, Meta: map[string]string{"k": "v"}}
+	if err := t.Data.Apply(&article); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"fields": t.Data.Fields(), "article": article}, nil
+}
+
+func TestMergePatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	engine := gin.New()
+	engine.PATCH("/api/articles", ginHandleFuncWrapper(&TestMergePatchOperator{}))
+
+	serve := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPatch, "/api/articles", strings.NewReader(body))
+		req.Header.Set("Content-Type", MineApplicationMergePatch)
+		engine.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("apply present fields", func(t *testing.T) {
+		w := serve(`{"title":"new","tags":null,"meta":{"k":null,"x":"y"},"author":{"name":"tom"}}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var resp struct {
+			Fields  []string    `json:"fields"`
+			Article testArticle `json:"article"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, []string{"author", "meta", "tags", "title"}, resp.Fields)
+		assert.Equal(t, testArticle{
+			Title:   "new",
+			Content: "content",
+			Meta:    map[string]string{"x": "y"},
+			Author:  testAuthor{Name: "tom"},
+		}, resp.Article)
+	})
+
+	t.Run("validate present fields only", func(t *testing.T) {
+		// content 未传入，不校验 required
+		assert.Equal(t, http.StatusOK, serve(`{"author":{"email":"tom@example.com"}}`).Code)
+
+		for body, fieldErr := range map[string]statuserror.FieldError{
+			`{"title":"too long title"}`:     {Field: "title", In: "body", Rule: "max", Param: "8", Message: "title长度不能超过8个字符"},
+			`{"content":""}`:                 {Field: "content", In: "body", Rule: "required", Message: "content为必填字段"},
+			`{"author":{"email":"invalid"}}`: {Field: "author.email", In: "body", Rule: "email", Message: "author.email必须是一个有效的邮箱"},
+		} {
+			w := serve(body)
+			require.Equal(t, http.StatusBadRequest, w.Code, body)
+
+			var resp statuserror.ValidationError
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, []statuserror.FieldError{fieldErr}, resp.Errors)
+		}
+	})
+
+	t.Run("not an object", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, serve(`[1]`).Code)
+	})
+}
+
+func TestMergePatch_Presence(t *testing.T) {
+	var patch MergePatch[testArticle]
+	require.NoError(t, json.Unmarshal([]byte(`{"title":"","content":null}`), &patch))
+
+	assert.True(t, patch.Has("title"))
+	assert.False(t, patch.IsNull("title"))
+	assert.True(t, patch.IsNull("content"))
+	assert.False(t, patch.Has("tags"))
+
+	data, err := json.Marshal(patch)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"title":"","content":null}`, string(data))
+}
+
+func TestJSONPatch(t *testing.T) {
+	article := func() *testArticle {
+		return &testArticle{Title: "old", Content: "content", Tags: []string{"a", "b"}, Author: testAuthor{Name: "tom"}}
+	}
+
+	t.Run("apply", func(t *testing.T) {
+		var patch JSONPatch
+		require.NoError(t, json.Unmarshal([]byte(`[
+			{"op":"test","path":"/title","value":"old"},
+			{"op":"replace","path":"/title","value":"new"},
+			{"op":"add","path":"/tags/1","value":"c"},
+			{"op":"add","path":"/tags/-","value":"d"},
+			{"op":"remove","path":"/tags/0"},
+			{"op":"copy","from":"/author/name","path":"/content"},
+			{"op":"move","from":"/author/name","path":"/author/email"}
+		]`), &patch))
+		assert.Equal(t, []string{"author", "content", "tags", "title"}, patch.Fields())
+
+		target := article()
+		require.NoError(t, patch.Apply(target))
+		assert.Equal(t, &testArticle{
+			Title:   "new",
+			Content: "tom",
+			Tags:    []string{"c", "b", "d"},
+			Author:  testAuthor{Email: "tom"},
+		}, target)
+	})
+
+	t.Run("failed operation", func(t *testing.T) {
+		for _, ops := range []string{
+			`[{"op":"replace","path":"/title","value":"new"},{"op":"test","path":"/content","value":"other"}]`,
+			`[{"op":"remove","path":"/tags/5"}]`,
+			`[{"op":"replace","path":"/unknown/x","value":1}]`,
+			`[{"op":"add","path":"","value":{}}]`,
+		} {
+			var patch JSONPatch
+			require.NoError(t, json.Unmarshal([]byte(ops), &patch))
+
+			// 操作失败时不修改 target
+			target := article()
+			require.Error(t, patch.Apply(target), ops)
+			assert.Equal(t, article(), target)
+		}
+	})
+
+	t.Run("test numbers", func(t *testing.T) {
+		type product struct {
+			Price  float64            `json:"price"`
+			Stock  int                `json:"stock"`
+			Sizes  []float64          `json:"sizes"`
+			Prices map[string]float64 `json:"prices"`
+		}
+		for ops, ok := range map[string]bool{
+			`[{"op":"test","path":"/price","value":1}]`:                     true,
+			`[{"op":"test","path":"/stock","value":2.0}]`:                   true,
+			`[{"op":"test","path":"/stock","value":2e0}]`:                   true,
+			`[{"op":"test","path":"/sizes","value":[1.50, 2]}]`:             true,
+			`[{"op":"test","path":"/prices","value":{"cny":10.0}}]`:         true,
+			`[{"op":"test","path":"/price","value":1.5}]`:                   false,
+			`[{"op":"test","path":"/stock","value":"2"}]`:                   false,
+			`[{"op":"test","path":"/sizes","value":[1.5]}]`:                 false,
+			`[{"op":"test","path":"/prices","value":{"cny":10,"usd":1.0}}]`: false,
+		} {
+			var patch JSONPatch
+			require.NoError(t, json.Unmarshal([]byte(ops), &patch))
+			target := &product{Price: 1.0, Stock: 2, Sizes: []float64{1.5, 2}, Prices: map[string]float64{"cny": 10}}
+			assert.Equal(t, ok, patch.Apply(target) == nil, ops)
+		}
+	})
+
+	t.Run("invalid operation", func(t *testing.T) {
+		for _, ops := range []string{
+			`[{"op":"merge","path":"/title"}]`,
+			`[{"op":"add","path":"/title"}]`,
+			`[{"op":"remove","path":"title"}]`,
+			`{"op":"remove","path":"/title"}`,
+		} {
+			var patch JSONPatch
+			assert.Error(t, json.Unmarshal([]byte(ops), &patch), ops)
+		}
+	})
+}
@@ -0,0 +1,261 @@
+package asyncx
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/shrewx/ginx"
+	"github.com/shrewx/ginx/pkg/logx"
+)
+
+const (
+	// DefaultPollInterval 默认轮询间隔
+	DefaultPollInterval = time.Second
+	// DefaultBatchSize 每次轮询最多发送的消息数
+	DefaultBatchSize = 100
+	// DefaultLease 消息被领取后的租约时间，发送进程崩溃时租约到期后由其他进程重新发送
+	DefaultLease = 30 * time.Second
+	// DefaultOutboxMaxAttempts 发件箱消息默认最大尝试次数
+	DefaultOutboxMaxAttempts = 10
+)
+
+// DefaultOutboxBackoff 发件箱消息默认重试间隔
+var DefaultOutboxBackoff = ginx.ExponentialBackoff(time.Second, 5*time.Minute)
+
+// MessageDeadLetterFunc 处理进入死信的发件箱消息
+type MessageDeadLetterFunc func(ctx context.Context, msg *OutboxMessage, err error)
+
+// Dispatcher 从发件箱中领取到期的消息并发送，可在多个实例上同时运行
+type Dispatcher struct {
+	outbox       *Outbox
+	invoker      ginx.SyncInvoker
+	config       ginx.RequestConfig
+	pollInterval time.Duration
+	batchSize    int
+	lease        time.Duration
+	maxAttempts  int
+	backoff      ginx.BackoffFunc
+	deadLetter   MessageDeadLetterFunc
+
+	mu      sync.Mutex
+	cancel  context.CancelFunc
+	done    chan struct{}
+	metrics metrics
+}
+
+// DispatcherOption 用于配置 Dispatcher
+type DispatcherOption func(d *Dispatcher)
+
+// WithPollInterval 设置轮询间隔，默认 DefaultPollInterval
+func WithPollInterval(interval time.Duration) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.pollInterval = interval
+	}
+}
+
+// WithBatchSize 设置每次轮询最多发送的消息数，默认 DefaultBatchSize
+func WithBatchSize(n int) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.batchSize = n
+	}
+}
+
+// WithLease 设置消息租约时间，应大于单次请求的超时时间，默认 DefaultLease
+func WithLease(lease time.Duration) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.lease = lease
+	}
+}
+
+// WithDispatchRetry 设置最大尝试次数与重试间隔，backoff 为 nil 时使用 DefaultOutboxBackoff
+func WithDispatchRetry(maxAttempts int, backoff ginx.BackoffFunc) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.maxAttempts = maxAttempts
+		d.backoff = backoff
+	}
+}
+
+// WithDeadLetter 设置死信处理函数，消息状态已更新为 OutboxDead，可通过 Outbox.Requeue 重新发送
+func WithDeadLetter(fn MessageDeadLetterFunc) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.deadLetter = fn
+	}
+}
+
+// WithRequestConfig 设置发送时使用的请求配置，如超时、Transport、拦截器等
+func WithRequestConfig(config ginx.RequestConfig) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.config = config
+	}
+}
+
+// NewDispatcher 创建发件箱分发器，invoker 通常为 &ginx.Client{}
+func NewDispatcher(outbox *Outbox, invoker ginx.SyncInvoker, opts ...DispatcherOption) *Dispatcher {
+	d := &Dispatcher{
+		outbox:       outbox,
+		invoker:      invoker,
+		pollInterval: DefaultPollInterval,
+		batchSize:    DefaultBatchSize,
+		lease:        DefaultLease,
+		maxAttempts:  DefaultOutboxMaxAttempts,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	if d.backoff == nil {
+		d.backoff = DefaultOutboxBackoff
+	}
+	if d.maxAttempts <= 0 {
+		d.maxAttempts = 1
+	}
+	return d
+}
+
+// Start 在后台开始轮询发送，可配合 ginx.OnStart 使用
+func (d *Dispatcher) Start(ctx context.Context) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.cancel != nil {
+		return nil
+	}
+
+	ctx, d.cancel = context.WithCancel(context.WithoutCancel(ctx))
+	d.done = make(chan struct{})
+	go d.loop(ctx, d.done)
+	return nil
+}
+
+// Stop 停止轮询并等待当前批次发送完成，可配合 ginx.OnShutdown 使用
+func (d *Dispatcher) Stop(ctx context.Context) error {
+	d.mu.Lock()
+	cancel, done := d.cancel, d.done
+	d.cancel, d.done = nil, nil
+	d.mu.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stats 返回统计信息
+func (d *Dispatcher) Stats() Stats {
+	return d.metrics.snapshot()
+}
+
+func (d *Dispatcher) loop(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := d.DispatchOnce(ctx); err != nil && ctx.Err() == nil {
+			logx.Errorf("dispatch outbox messages failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchOnce 领取并发送一批到期的消息，返回处理的消息数
+func (d *Dispatcher) DispatchOnce(ctx context.Context) (int, error) {
+	now := time.Now()
+	var messages []*OutboxMessage
+	err := d.outbox.db.WithContext(ctx).
+		Where("status = ? AND next_attempt_at <= ?", OutboxPending, now).
+		Order("next_attempt_at").
+		Limit(d.batchSize).
+		Find(&messages).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	for _, msg := range messages {
+		if ctx.Err() != nil {
+			break
+		}
+		claimed, err := d.claim(ctx, msg, now)
+		if err != nil {
+			return count, err
+		}
+		if !claimed {
+			continue
+		}
+		if err := d.dispatch(ctx, msg); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// claim 通过条件更新领取消息，避免多个实例重复发送
+func (d *Dispatcher) claim(ctx context.Context, msg *OutboxMessage, now time.Time) (bool, error) {
+	leaseUntil := now.Add(d.lease)
+	result := d.outbox.db.WithContext(ctx).Model(&OutboxMessage{}).
+		Where("id = ? AND status = ? AND next_attempt_at <= ?", msg.ID, OutboxPending, now).
+		Update("next_attempt_at", leaseUntil)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	msg.NextAttemptAt = leaseUntil
+	return result.RowsAffected == 1, nil
+}
+
+// dispatch 发送消息并记录结果
+func (d *Dispatcher) dispatch(ctx context.Context, msg *OutboxMessage) error {
+	err := d.send(ctx, msg)
+
+	msg.Attempts++
+	updates := map[string]interface{}{"attempts": msg.Attempts, "last_error": ""}
+	switch {
+	case err == nil:
+		msg.Status = OutboxSucceeded
+		d.metrics.succeeded.Add(1)
+	case msg.Attempts >= d.maxAttempts:
+		msg.Status = OutboxDead
+		updates["last_error"] = err.Error()
+		d.metrics.deadLettered.Add(1)
+	default:
+		msg.NextAttemptAt = time.Now().Add(d.backoff(msg.Attempts))
+		updates["last_error"] = err.Error()
+		updates["next_attempt_at"] = msg.NextAttemptAt
+		d.metrics.retried.Add(1)
+	}
+	updates["status"] = msg.Status
+
+	// 使用独立的 context 记录结果，避免停止时消息停留在租约中
+	if dbErr := d.outbox.db.WithContext(context.WithoutCancel(ctx)).Model(msg).Updates(updates).Error; dbErr != nil {
+		return dbErr
+	}
+
+	if msg.Status == OutboxDead {
+		if d.deadLetter != nil {
+			d.deadLetter(ctx, msg, err)
+		} else {
+			logx.Errorf("outbox message %d moved to dead letter after %d attempts: %v", msg.ID, msg.Attempts, err)
+		}
+	}
+	return nil
+}
+
+func (d *Dispatcher) send(ctx context.Context, msg *OutboxMessage) error {
+	req, err := msg.Request(ctx)
+	if err != nil {
+		return err
+	}
+	config := d.config
+	config.ServiceName = msg.ServiceName
+	return invoke(ctx, d.invoker, req, config)
+}
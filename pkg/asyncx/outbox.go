@@ -0,0 +1,120 @@
+package asyncx
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/shrewx/ginx"
+	"github.com/shrewx/ginx/pkg/dbhelper"
+	"gorm.io/gorm"
+)
+
+// OutboxStatus 发件箱消息状态
+type OutboxStatus string
+
+const (
+	// OutboxPending 等待发送
+	OutboxPending OutboxStatus = "pending"
+	// OutboxSucceeded 发送成功
+	OutboxSucceeded OutboxStatus = "succeeded"
+	// OutboxDead 超过最大尝试次数，进入死信
+	OutboxDead OutboxStatus = "dead"
+)
+
+// OutboxMessage 发件箱中持久化的请求
+type OutboxMessage struct {
+	ID            uint64 `gorm:"primaryKey;autoIncrement"`
+	ServiceName   string `gorm:"size:128"` // 不为空时发送前通过服务发现选择实例
+	Method        string `gorm:"size:16"`
+	URL           string `gorm:"type:text"`
+	Header        string `gorm:"type:text"` // JSON 编码的请求头
+	Body          []byte
+	Status        OutboxStatus `gorm:"size:16;index:idx_ginx_outbox_dispatch,priority:1"`
+	Attempts      int
+	NextAttemptAt time.Time `gorm:"index:idx_ginx_outbox_dispatch,priority:2"`
+	LastError     string    `gorm:"type:text"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (OutboxMessage) TableName() string {
+	return "ginx_outbox_messages"
+}
+
+// Request 将消息还原为 HTTP 请求
+func (m *OutboxMessage) Request(ctx context.Context) (*http.Request, error) {
+	var header http.Header
+	if m.Header != "" {
+		if err := json.Unmarshal([]byte(m.Header), &header); err != nil {
+			return nil, err
+		}
+	}
+	return newRequest(ctx, m.Method, m.URL, header, m.Body)
+}
+
+// Outbox 事务发件箱异步调用器
+// InvokeAsync 在调用方的事务中写入请求（通过 dbhelper.TransactionManager 传递的 ctx），
+// 业务数据与请求同时提交或回滚，由 Dispatcher 在后台发送
+type Outbox struct {
+	db      *dbhelper.DB
+	metrics metrics
+}
+
+// NewOutbox 创建发件箱
+func NewOutbox(db *dbhelper.DB) *Outbox {
+	return &Outbox{db: db}
+}
+
+// Migrate 创建发件箱表
+func (o *Outbox) Migrate() error {
+	return o.db.AutoMigrate(&OutboxMessage{})
+}
+
+// InvokeAsync 将请求编码后写入发件箱，ctx 中存在事务时使用该事务
+func (o *Outbox) InvokeAsync(ctx context.Context, req interface{}, config ginx.RequestConfig) error {
+	httpReq, body, err := readRequest(ctx, req, config)
+	if err != nil {
+		return err
+	}
+	header, err := json.Marshal(httpReq.Header)
+	if err != nil {
+		return err
+	}
+
+	msg := &OutboxMessage{
+		ServiceName:   config.ServiceName,
+		Method:        httpReq.Method,
+		URL:           httpReq.URL.String(),
+		Header:        string(header),
+		Body:          body,
+		Status:        OutboxPending,
+		NextAttemptAt: time.Now(),
+	}
+	if err := o.conn(ctx).Create(msg).Error; err != nil {
+		return err
+	}
+	o.metrics.enqueued.Add(1)
+	return nil
+}
+
+// Requeue 将死信消息重新放回待发送队列
+func (o *Outbox) Requeue(ctx context.Context, id uint64) error {
+	return o.conn(ctx).Model(&OutboxMessage{}).
+		Where("id = ? AND status = ?", id, OutboxDead).
+		Updates(map[string]interface{}{
+			"status":          OutboxPending,
+			"attempts":        0,
+			"next_attempt_at": time.Now(),
+		}).Error
+}
+
+// Stats 返回统计信息，Enqueued 为当前进程写入的消息数
+func (o *Outbox) Stats() Stats {
+	return o.metrics.snapshot()
+}
+
+func (o *Outbox) conn(ctx context.Context) *gorm.DB {
+	return dbhelper.GetCtxDB(ctx, o.db.DB).WithContext(ctx)
+}
@@ -0,0 +1,185 @@
+package asyncx
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shrewx/ginx"
+	"github.com/shrewx/ginx/pkg/conf"
+	"github.com/shrewx/ginx/pkg/dbhelper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type orderRecord struct {
+	ID   uint64 `gorm:"primaryKey"`
+	Name string
+}
+
+func (orderRecord) TableName() string {
+	return "orders"
+}
+
+type createOrderReq struct {
+	Body orderRecord `in:"body"`
+}
+
+func (r *createOrderReq) Path() string   { return "/orders" }
+func (r *createOrderReq) Method() string { return http.MethodPost }
+
+func newTestOutbox(t *testing.T) (*Outbox, *dbhelper.DB) {
+	db, err := dbhelper.NewDB(conf.DB{Type: conf.Sqlite, Dsn: filepath.Join(t.TempDir(), "outbox.db"), MaxOpenConns: 1})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&orderRecord{}))
+
+	outbox := NewOutbox(db)
+	require.NoError(t, outbox.Migrate())
+	return outbox, db
+}
+
+func TestOutbox_Transactional(t *testing.T) {
+	outbox, db := newTestOutbox(t)
+	tm := dbhelper.NewGormTransactionManager(db.DB)
+	config := ginx.RequestConfig{Schema: "http", Host: "order-svc", Headers: map[string]string{"X-Source": "test"}}
+
+	// 事务回滚时请求不会写入发件箱
+	err := tm.Transaction(context.Background(), func(ctx context.Context) error {
+		require.NoError(t, dbhelper.GetCtxDB(ctx, db.DB).Create(&orderRecord{ID: 1, Name: "a"}).Error)
+		require.NoError(t, outbox.InvokeAsync(ctx, &createOrderReq{Body: orderRecord{ID: 1, Name: "a"}}, config))
+		return errors.New("rollback")
+	})
+	require.Error(t, err)
+
+	var count int64
+	db.Model(&OutboxMessage{}).Count(&count)
+	assert.Zero(t, count)
+
+	err = tm.Transaction(context.Background(), func(ctx context.Context) error {
+		if err := dbhelper.GetCtxDB(ctx, db.DB).Create(&orderRecord{ID: 2, Name: "b"}).Error; err != nil {
+			return err
+		}
+		return outbox.InvokeAsync(ctx, &createOrderReq{Body: orderRecord{ID: 2, Name: "b"}}, config)
+	})
+	require.NoError(t, err)
+
+	var msg OutboxMessage
+	require.NoError(t, db.First(&msg).Error)
+	assert.Equal(t, OutboxPending, msg.Status)
+	assert.Equal(t, http.MethodPost, msg.Method)
+	assert.Equal(t, "http://order-svc/orders", msg.URL)
+	assert.JSONEq(t, `{"ID":2,"Name":"b"}`, string(msg.Body))
+
+	req, err := msg.Request(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "test", req.Header.Get("X-Source"))
+	assert.Equal(t, ginx.MineApplicationJson, req.Header.Get("Content-Type"))
+}
+
+func TestDispatcher_DispatchAndDeadLetter(t *testing.T) {
+	var (
+		calls  atomic.Int32
+		failed atomic.Bool
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		data, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"ID":1,"Name":"a"}`, string(data))
+		if failed.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	outbox, db := newTestOutbox(t)
+	config := requestConfig(server)
+	require.NoError(t, outbox.InvokeAsync(context.Background(), &createOrderReq{Body: orderRecord{ID: 1, Name: "a"}}, config))
+
+	var dead []*OutboxMessage
+	dispatcher := NewDispatcher(outbox, &ginx.Client{},
+		WithDispatchRetry(2, ginx.ConstantBackoff(0)),
+		WithDeadLetter(func(ctx context.Context, msg *OutboxMessage, err error) {
+			dead = append(dead, msg)
+		}),
+	)
+
+	n, err := dispatcher.DispatchOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	var msg OutboxMessage
+	require.NoError(t, db.First(&msg).Error)
+	assert.Equal(t, OutboxSucceeded, msg.Status)
+	assert.Equal(t, 1, msg.Attempts)
+
+	// 已发送的消息不会重复发送
+	n, err = dispatcher.DispatchOnce(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	// 失败重试后进入死信，Requeue 后重新发送
+	failed.Store(true)
+	require.NoError(t, outbox.InvokeAsync(context.Background(), &createOrderReq{Body: orderRecord{ID: 1, Name: "a"}}, config))
+	for i := 0; i < 2; i++ {
+		_, err = dispatcher.DispatchOnce(context.Background())
+		require.NoError(t, err)
+	}
+	require.Len(t, dead, 1)
+	assert.Equal(t, OutboxDead, dead[0].Status)
+
+	var deadMsg OutboxMessage
+	require.NoError(t, db.First(&deadMsg, dead[0].ID).Error)
+	assert.Equal(t, OutboxDead, deadMsg.Status)
+	assert.NotEmpty(t, deadMsg.LastError)
+
+	failed.Store(false)
+	require.NoError(t, outbox.Requeue(context.Background(), deadMsg.ID))
+	n, err = dispatcher.DispatchOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	assert.Equal(t, int32(4), calls.Load())
+	assert.Equal(t, Stats{Succeeded: 2, Retried: 1, DeadLettered: 1}, dispatcher.Stats())
+}
+
+func TestDispatcher_ClaimOnce(t *testing.T) {
+	server, calls := newTestServer(t, 0)
+	outbox, _ := newTestOutbox(t)
+	require.NoError(t, outbox.InvokeAsync(context.Background(), &notifyReq{Event: "created"}, requestConfig(server)))
+
+	first := NewDispatcher(outbox, &ginx.Client{})
+	second := NewDispatcher(outbox, &ginx.Client{})
+
+	var msg OutboxMessage
+	require.NoError(t, outbox.db.First(&msg).Error)
+	now := time.Now()
+	claimed, err := first.claim(context.Background(), &msg, now)
+	require.NoError(t, err)
+	assert.True(t, claimed)
+
+	// 租约未到期时其他实例无法领取
+	n, err := second.DispatchOnce(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	assert.Zero(t, calls.Load())
+}
+
+func TestDispatcher_StartStop(t *testing.T) {
+	server, calls := newTestServer(t, 0)
+	outbox, _ := newTestOutbox(t)
+	require.NoError(t, outbox.InvokeAsync(context.Background(), &notifyReq{Event: "created"}, requestConfig(server)))
+
+	dispatcher := NewDispatcher(outbox, &ginx.Client{}, WithPollInterval(10*time.Millisecond))
+	require.NoError(t, dispatcher.Start(context.Background()))
+	assert.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, 10*time.Millisecond)
+	require.NoError(t, dispatcher.Stop(context.Background()))
+	assert.Equal(t, int64(1), dispatcher.Stats().Succeeded)
+}
@@ -0,0 +1,89 @@
+package asyncx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/shrewx/ginx"
+)
+
+var (
+	// ErrQueueFull 工作池队列已满
+	ErrQueueFull = errors.New("async invoker queue is full")
+	// ErrClosed 调用器已关闭
+	ErrClosed = errors.New("async invoker is closed")
+)
+
+// Stats 异步调用统计信息
+type Stats struct {
+	Enqueued     int64 // 入队的请求数
+	Rejected     int64 // 队列已满或已关闭被拒绝的请求数
+	Succeeded    int64 // 发送成功的请求数
+	Retried      int64 // 重试次数
+	DeadLettered int64 // 超过最大尝试次数进入死信的请求数
+}
+
+type metrics struct {
+	enqueued     atomic.Int64
+	rejected     atomic.Int64
+	succeeded    atomic.Int64
+	retried      atomic.Int64
+	deadLettered atomic.Int64
+}
+
+func (m *metrics) snapshot() Stats {
+	return Stats{
+		Enqueued:     m.enqueued.Load(),
+		Rejected:     m.rejected.Load(),
+		Succeeded:    m.succeeded.Load(),
+		Retried:      m.retried.Load(),
+		DeadLettered: m.deadLettered.Load(),
+	}
+}
+
+// readRequest 构建请求并将请求体读入内存，入队后调用方修改请求不影响发送内容，每次发送时可重新构建请求
+func readRequest(ctx context.Context, req interface{}, config ginx.RequestConfig) (*http.Request, []byte, error) {
+	httpReq, ok := req.(*http.Request)
+	if !ok {
+		var err error
+		if httpReq, err = ginx.NewRequest(ctx, req, config); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var body []byte
+	if httpReq.Body != nil && httpReq.Body != http.NoBody {
+		data, err := io.ReadAll(httpReq.Body)
+		_ = httpReq.Body.Close()
+		if err != nil {
+			return nil, nil, err
+		}
+		body = data
+	}
+	return httpReq, body, nil
+}
+
+// newRequest 使用快照中的请求体重新构建请求
+func newRequest(ctx context.Context, method, url string, header http.Header, body []byte) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if header != nil {
+		req.Header = header.Clone()
+	}
+	return req, nil
+}
+
+// invoke 同步发送请求，非 2xx 响应视为失败
+func invoke(ctx context.Context, invoker ginx.SyncInvoker, req interface{}, config ginx.RequestConfig) error {
+	resp, err := invoker.Invoke(ctx, req, config)
+	if err != nil {
+		return err
+	}
+	return resp.Bind(io.Discard)
+}
@@ -0,0 +1,237 @@
+package asyncx
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/shrewx/ginx"
+	"github.com/shrewx/ginx/pkg/logx"
+)
+
+const (
+	// DefaultWorkers 工作池默认并发数
+	DefaultWorkers = 4
+	// DefaultQueueSize 工作池默认队列长度
+	DefaultQueueSize = 1024
+	// DefaultMaxAttempts 默认最大尝试次数（包含首次发送）
+	DefaultMaxAttempts = 3
+)
+
+// Task 工作池中的异步请求，入队时保存请求的快照，每次发送时重新构建请求
+type Task struct {
+	Method   string
+	URL      string
+	Header   http.Header
+	Body     []byte
+	Config   ginx.RequestConfig
+	Attempts int // 已尝试次数
+
+	ctx context.Context
+}
+
+// Request 将任务还原为 HTTP 请求
+func (t *Task) Request(ctx context.Context) (*http.Request, error) {
+	return newRequest(ctx, t.Method, t.URL, t.Header, t.Body)
+}
+
+// TaskDeadLetterFunc 处理超过最大尝试次数的任务
+type TaskDeadLetterFunc func(ctx context.Context, task *Task, err error)
+
+// WorkerPool 进程内异步调用器，请求进入有界队列后由固定数量的 worker 发送
+// 进程退出时队列中未发送的请求会丢失，需要可靠投递时使用 Outbox
+type WorkerPool struct {
+	invoker     ginx.SyncInvoker
+	workers     int
+	queueSize   int
+	maxAttempts int
+	backoff     ginx.BackoffFunc
+	deadLetter  TaskDeadLetterFunc
+
+	queue   chan *Task
+	stop    chan struct{}
+	wg      sync.WaitGroup
+	mu      sync.RWMutex
+	closed  bool
+	metrics metrics
+}
+
+// WorkerPoolOption 用于配置工作池
+type WorkerPoolOption func(p *WorkerPool)
+
+// WithWorkers 设置 worker 数量，默认 DefaultWorkers
+func WithWorkers(n int) WorkerPoolOption {
+	return func(p *WorkerPool) {
+		p.workers = n
+	}
+}
+
+// WithQueueSize 设置队列长度，队列满时 InvokeAsync 返回 ErrQueueFull，默认 DefaultQueueSize
+func WithQueueSize(n int) WorkerPoolOption {
+	return func(p *WorkerPool) {
+		p.queueSize = n
+	}
+}
+
+// WithPoolRetry 设置最大尝试次数与重试间隔，backoff 为 nil 时使用 ginx.DefaultBackoff
+func WithPoolRetry(maxAttempts int, backoff ginx.BackoffFunc) WorkerPoolOption {
+	return func(p *WorkerPool) {
+		p.maxAttempts = maxAttempts
+		p.backoff = backoff
+	}
+}
+
+// WithPoolDeadLetter 设置死信处理函数，默认只记录日志
+func WithPoolDeadLetter(fn TaskDeadLetterFunc) WorkerPoolOption {
+	return func(p *WorkerPool) {
+		p.deadLetter = fn
+	}
+}
+
+// NewWorkerPool 创建并启动工作池，invoker 通常为 &ginx.Client{}
+func NewWorkerPool(invoker ginx.SyncInvoker, opts ...WorkerPoolOption) *WorkerPool {
+	p := &WorkerPool{
+		invoker:     invoker,
+		workers:     DefaultWorkers,
+		queueSize:   DefaultQueueSize,
+		maxAttempts: DefaultMaxAttempts,
+		stop:        make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.backoff == nil {
+		p.backoff = ginx.DefaultBackoff
+	}
+	if p.maxAttempts <= 0 {
+		p.maxAttempts = 1
+	}
+
+	p.queue = make(chan *Task, p.queueSize)
+	for i := 0; i < p.workers; i++ {
+		p.wg.Add(1)
+		go p.work()
+	}
+	return p
+}
+
+// InvokeAsync 将请求放入队列，请求在调用方 ctx 取消后仍会发送
+// 入队时读取请求体，之后调用方修改请求不影响发送内容，重试时发送相同的请求体
+func (p *WorkerPool) InvokeAsync(ctx context.Context, req interface{}, config ginx.RequestConfig) error {
+	httpReq, body, err := readRequest(ctx, req, config)
+	if err != nil {
+		return err
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		p.metrics.rejected.Add(1)
+		return ErrClosed
+	}
+
+	task := &Task{
+		Method: httpReq.Method,
+		URL:    httpReq.URL.String(),
+		Header: httpReq.Header.Clone(),
+		Body:   body,
+		Config: config,
+		ctx:    context.WithoutCancel(ctx),
+	}
+	select {
+	case p.queue <- task:
+		p.metrics.enqueued.Add(1)
+		return nil
+	default:
+		p.metrics.rejected.Add(1)
+		return ErrQueueFull
+	}
+}
+
+// Close 停止接收新请求并等待队列中的请求发送完成
+// ctx 结束时放弃等待中的重试并返回 ctx.Err()
+func (p *WorkerPool) Close(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.queue)
+	}
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		p.abort()
+		return ctx.Err()
+	}
+}
+
+// Stats 返回统计信息
+func (p *WorkerPool) Stats() Stats {
+	return p.metrics.snapshot()
+}
+
+func (p *WorkerPool) abort() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	select {
+	case <-p.stop:
+	default:
+		close(p.stop)
+	}
+}
+
+func (p *WorkerPool) work() {
+	defer p.wg.Done()
+	for task := range p.queue {
+		p.run(task)
+	}
+}
+
+func (p *WorkerPool) run(task *Task) {
+	var err error
+	for {
+		task.Attempts++
+		if err = p.send(task); err == nil {
+			p.metrics.succeeded.Add(1)
+			return
+		}
+		if task.Attempts >= p.maxAttempts {
+			break
+		}
+
+		p.metrics.retried.Add(1)
+		timer := time.NewTimer(p.backoff(task.Attempts))
+		select {
+		case <-timer.C:
+			continue
+		case <-p.stop:
+			timer.Stop()
+		}
+		break
+	}
+
+	p.metrics.deadLettered.Add(1)
+	if p.deadLetter != nil {
+		p.deadLetter(task.ctx, task, err)
+		return
+	}
+	logx.Errorf("async request dropped after %d attempts: %v", task.Attempts, err)
+}
+
+func (p *WorkerPool) send(task *Task) error {
+	req, err := task.Request(task.ctx)
+	if err != nil {
+		return err
+	}
+	return invoke(task.ctx, p.invoker, req, task.Config)
+}
@@ -0,0 +1,147 @@
+package asyncx
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shrewx/ginx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type notifyReq struct {
+	Event string `in:"query" name:"event"`
+}
+
+func (r *notifyReq) Path() string   { return "/notify" }
+func (r *notifyReq) Method() string { return http.MethodPost }
+
+func newTestServer(t *testing.T, failures int32) (*httptest.Server, *atomic.Int32) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func requestConfig(server *httptest.Server) ginx.RequestConfig {
+	u, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	return ginx.RequestConfig{Schema: "http", Host: u.URL.Host, Headers: map[string]string{}}
+}
+
+func TestWorkerPool_RetryAndClose(t *testing.T) {
+	server, calls := newTestServer(t, 1)
+	pool := NewWorkerPool(&ginx.Client{}, WithWorkers(2), WithPoolRetry(3, ginx.ConstantBackoff(time.Millisecond)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, pool.InvokeAsync(ctx, &notifyReq{Event: "created"}, requestConfig(server)))
+	// 调用方 ctx 取消后请求仍会发送
+	cancel()
+
+	require.NoError(t, pool.Close(context.Background()))
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Equal(t, Stats{Enqueued: 1, Succeeded: 1, Retried: 1}, pool.Stats())
+
+	assert.ErrorIs(t, pool.InvokeAsync(context.Background(), &notifyReq{}, requestConfig(server)), ErrClosed)
+}
+
+func TestWorkerPool_DeadLetterAndQueueFull(t *testing.T) {
+	server, _ := newTestServer(t, 100)
+
+	var dead atomic.Int32
+	block := make(chan struct{})
+	pool := NewWorkerPool(&ginx.Client{},
+		WithWorkers(1),
+		WithQueueSize(1),
+		WithPoolRetry(2, ginx.ConstantBackoff(time.Millisecond)),
+		WithPoolDeadLetter(func(ctx context.Context, task *Task, err error) {
+			<-block
+			assert.Equal(t, 2, task.Attempts)
+			assert.Error(t, err)
+			dead.Add(1)
+		}),
+	)
+
+	config := requestConfig(server)
+	require.NoError(t, pool.InvokeAsync(context.Background(), &notifyReq{Event: "a"}, config))
+	// 等待 worker 取走第一个任务后队列只能再容纳一个
+	assert.Eventually(t, func() bool { return len(pool.queue) == 0 }, time.Second, time.Millisecond)
+	require.NoError(t, pool.InvokeAsync(context.Background(), &notifyReq{Event: "b"}, config))
+	assert.ErrorIs(t, pool.InvokeAsync(context.Background(), &notifyReq{Event: "c"}, config), ErrQueueFull)
+
+	close(block)
+	require.NoError(t, pool.Close(context.Background()))
+	assert.Equal(t, int32(2), dead.Load())
+
+	stats := pool.Stats()
+	assert.Equal(t, int64(2), stats.Enqueued)
+	assert.Equal(t, int64(1), stats.Rejected)
+	assert.Equal(t, int64(2), stats.DeadLettered)
+}
+
+func TestInvoke_AsyncModeWithWorkerPool(t *testing.T) {
+	server, calls := newTestServer(t, 0)
+	pool := NewWorkerPool(&ginx.Client{})
+
+	config := requestConfig(server)
+	err := ginx.Invoke(context.Background(), &notifyReq{Event: "created"}, nil, &config, &ginx.Client{}, pool, ginx.WithAsyncInvokeMode())
+	require.NoError(t, err)
+	require.NoError(t, pool.Close(context.Background()))
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+type notifyBodyReq struct {
+	Data struct {
+		Event string `json:"event"`
+	} `in:"body"`
+}
+
+func (r *notifyBodyReq) Path() string   { return "/notify" }
+func (r *notifyBodyReq) Method() string { return http.MethodPost }
+
+func TestWorkerPool_RetrySendsSameBody(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		bodies []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		bodies = append(bodies, string(data))
+		if len(bodies)%2 == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	pool := NewWorkerPool(&ginx.Client{}, WithWorkers(1), WithPoolRetry(2, ginx.ConstantBackoff(time.Millisecond)))
+
+	// 入队后修改请求不影响发送内容
+	req := &notifyBodyReq{}
+	req.Data.Event = "created"
+	require.NoError(t, pool.InvokeAsync(context.Background(), req, requestConfig(server)))
+	req.Data.Event = "changed"
+
+	// *http.Request 的请求体重试时同样重新发送
+	httpReq, _ := http.NewRequest(http.MethodPost, server.URL+"/notify", io.NopCloser(strings.NewReader(`{"event":"raw"}`)))
+	require.NoError(t, pool.InvokeAsync(context.Background(), httpReq, ginx.RequestConfig{}))
+
+	require.NoError(t, pool.Close(context.Background()))
+	assert.Equal(t, []string{`{"event":"created"}`, `{"event":"created"}`, `{"event":"raw"}`, `{"event":"raw"}`}, bodies)
+	assert.Equal(t, int64(2), pool.Stats().Succeeded)
+}
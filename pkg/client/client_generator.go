@@ -1,8 +1,9 @@
 package client
 
 import (
+	"bytes"
 	"context"
-	"encoding/json"
+	"io"
 	"io/ioutil"
 	"log"
 	"net/http"
@@ -15,6 +16,7 @@ import (
 	"github.com/go-courier/codegen"
 	"github.com/go-courier/oas"
 	"github.com/pkg/errors"
+	"github.com/shrewx/ginx/pkg/openapi"
 	"golang.org/x/mod/modfile"
 	"golang.org/x/tools/go/packages"
 )
@@ -53,12 +55,25 @@ type ClientGenerator struct {
 	GenOption
 }
 
+// NewClientGeneratorWithOpenAPI 使用已有的文档创建生成器，如通过 openapi.OpenAPIGenerator 扫描源码得到的文档
+func NewClientGeneratorWithOpenAPI(serviceName string, spec *oas.OpenAPI, opts ...GenOptionFn) *ClientGenerator {
+	g := NewClientGenerator(serviceName, nil, opts...)
+
+	normalized, err := openapi.NormalizeSpec(spec)
+	if err != nil {
+		panic(err)
+	}
+	g.openAPI = normalized
+
+	return g
+}
+
 func (g *ClientGenerator) Load() {
 	if g.URL == nil {
 		panic(errors.Errorf("missing spec-url or file"))
 	}
 
-	if g.URL.Scheme == "file" {
+	if g.URL.Scheme == "file" || g.URL.Scheme == "" {
 		g.loadByFile()
 	} else {
 		g.loadBySpecURL()
@@ -71,9 +86,7 @@ func (g *ClientGenerator) loadByFile() {
 		panic(err)
 	}
 
-	if err := json.Unmarshal(data, g.openAPI); err != nil {
-		panic(err)
-	}
+	g.unmarshal(data)
 }
 
 func (g *ClientGenerator) loadBySpecURL() {
@@ -87,10 +100,23 @@ func (g *ClientGenerator) loadBySpecURL() {
 	if err != nil {
 		panic(err)
 	}
+	defer resp.Body.Close()
 
-	if err := json.NewDecoder(resp.Body).Decode(g.openAPI); err != nil {
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		panic(err)
+	}
+
+	g.unmarshal(data)
+}
+
+// unmarshal 解析 JSON 或 YAML 格式的文档
+func (g *ClientGenerator) unmarshal(data []byte) {
+	spec, err := openapi.UnmarshalSpec(data)
+	if err != nil {
 		panic(err)
 	}
+	g.openAPI = spec
 }
 
 func (g *ClientGenerator) vendorImportsByGoMod(cwd string) map[string]bool {
@@ -147,35 +173,82 @@ func VendorImportsFromContext(ctx context.Context) map[string]bool {
 	return map[string]bool{}
 }
 
-func (g *ClientGenerator) Output(cwd string) {
+// clientFile 生成的文件及其路径
+type clientFile struct {
+	*codegen.File
+	filename string
+}
+
+// files 生成客户端的所有文件
+func (g *ClientGenerator) files(cwd string) []clientFile {
 	pkgName := codegen.LowerSnakeCase("Client-" + g.ServiceName)
 	rootPath := path.Join(cwd, pkgName)
 
 	ctx := WithVendorImports(context.Background(), g.vendorImportsByGoMod(cwd))
 
+	files := make([]clientFile, 0)
+
 	{
-		file := codegen.NewFile(pkgName, path.Join(rootPath, "client.go"))
+		filename := path.Join(rootPath, "client.go")
+		file := codegen.NewFile(pkgName, filename)
 		NewServiceClientGenerator(g.ServiceName, file).Scan(ctx, g.openAPI)
-		_, _ = file.WriteFile()
+		files = append(files, clientFile{File: file, filename: filename})
 	}
 
 	{
-		file := codegen.NewFile(pkgName, path.Join(rootPath, "operations.go"))
+		filename := path.Join(rootPath, "operations.go")
+		file := codegen.NewFile(pkgName, filename)
 		NewOperationGenerator(g.ServiceName, file).Scan(ctx, g.openAPI)
-		_, _ = file.WriteFile()
+		files = append(files, clientFile{File: file, filename: filename})
 	}
 
 	{
-		file := codegen.NewFile(pkgName, path.Join(rootPath, "types.go"))
+		filename := path.Join(rootPath, "types.go")
+		file := codegen.NewFile(pkgName, filename)
 		NewTypeGenerator(g.ServiceName, file).Scan(ctx, g.openAPI)
-		_, _ = file.WriteFile()
+		files = append(files, clientFile{File: file, filename: filename})
+	}
+
+	{
+		filename := path.Join(rootPath, "errors.go")
+		file := codegen.NewFile(pkgName, filename)
+		NewStatusErrorGenerator(g.ServiceName, file).Scan(ctx, g.openAPI)
+		files = append(files, clientFile{File: file, filename: filename})
+	}
+
+	{
+		filename := path.Join(rootPath, "mock.go")
+		file := codegen.NewFile(pkgName, filename)
+		NewMockGenerator(g.ServiceName, file).Scan(ctx, g.openAPI)
+		files = append(files, clientFile{File: file, filename: filename})
 	}
 
 	{
-		file := codegen.NewFile(pkgName, path.Join(rootPath, "options.go"))
+		filename := path.Join(rootPath, "options.go")
+		file := codegen.NewFile(pkgName, filename)
 		NewOptionsGenerator(g.ServiceName, file).Scan()
+		files = append(files, clientFile{File: file, filename: filename})
+	}
+
+	return files
+}
+
+func (g *ClientGenerator) Output(cwd string) {
+	for _, file := range g.files(cwd) {
 		_, _ = file.WriteFile()
 	}
 
-	log.Printf("generated client of %s into %s", g.ServiceName, color.MagentaString(rootPath))
+	log.Printf("generated client of %s into %s", g.ServiceName, color.MagentaString(path.Join(cwd, codegen.LowerSnakeCase("Client-"+g.ServiceName))))
+}
+
+// Check 检查已生成的客户端是否与文档一致，返回缺失或过期的文件
+func (g *ClientGenerator) Check(cwd string) []string {
+	stale := make([]string, 0)
+	for _, file := range g.files(cwd) {
+		data, err := os.ReadFile(file.filename)
+		if err != nil || !bytes.Equal(data, file.Bytes()) {
+			stale = append(stale, file.filename)
+		}
+	}
+	return stale
 }
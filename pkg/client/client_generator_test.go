@@ -0,0 +1,57 @@
+package client
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shrewx/ginx/pkg/openapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSpec = `{
+  "openapi": "3.0.3",
+  "info": {"title": "", "version": ""},
+  "paths": {
+    "/users/{id}": {
+      "get": {
+        "operationId": "GetUser",
+        "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "integer", "format": "int64"}}],
+        "responses": {
+          "200": {"description": "", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}},
+          "404": {"description": "", "x-status-errors": ["[UserNotFound][404001]"]}
+        }
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "User": {"type": "object", "properties": {"id": {"type": "integer", "format": "int64"}, "name": {"type": "string"}}}
+    }
+  }
+}`
+
+func TestClientGenerator_Check(t *testing.T) {
+	dir := t.TempDir()
+	specFile := filepath.Join(dir, "openapi.json")
+	require.NoError(t, os.WriteFile(specFile, []byte(testSpec), 0o644))
+
+	spec, err := openapi.UnmarshalSpec([]byte(testSpec))
+	require.NoError(t, err)
+	g := NewClientGeneratorWithOpenAPI("user", spec)
+	assert.Len(t, g.Check(dir), 6)
+
+	g.Output(dir)
+	assert.Empty(t, g.Check(dir))
+
+	// 从文件加载的文档与传入的文档生成的代码一致
+	loaded := NewClientGenerator("user", &url.URL{Path: specFile})
+	loaded.Load()
+	assert.Empty(t, loaded.Check(dir))
+
+	filename := filepath.Join(dir, "client_user", "types.go")
+	require.NoError(t, os.WriteFile(filename, []byte("// stale"), 0o644))
+	assert.Equal(t, []string{filename}, g.Check(dir))
+}
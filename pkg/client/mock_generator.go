@@ -0,0 +1,23 @@
+package client
+
+import (
+	"context"
+
+	"github.com/go-courier/codegen"
+	"github.com/go-courier/oas"
+)
+
+func NewMockGenerator(serviceName string, file *codegen.File) *MockGenerator {
+	return &MockGenerator{
+		ServiceClientGenerator: NewServiceClientGenerator(serviceName, file),
+	}
+}
+
+// MockGenerator 生成实现客户端接口的 mock，基于 mockx.Mock 记录调用并返回预设结果
+type MockGenerator struct {
+	*ServiceClientGenerator
+}
+
+func (g *MockGenerator) Scan(ctx context.Context, openapi *oas.OpenAPI) {
+	g.render("mock", TplMock, g.templateData(ctx, openapi))
+}
@@ -10,6 +10,7 @@ import (
 	"text/template"
 
 	"github.com/shrewx/ginx"
+	"github.com/shrewx/ginx/pkg/openapi"
 
 	"github.com/go-courier/codegen"
 	"github.com/go-courier/oas"
@@ -86,7 +87,7 @@ type OperationTemplateItem struct {
 	Method       string
 	HasResp      bool
 	RespType     string
-	StatusErrors []string
+	StatusErrors []StatusErrorData
 }
 
 func (g *OperationGenerator) Scan(ctx context.Context, openapi *oas.OpenAPI) {
@@ -132,6 +133,9 @@ func (g *OperationGenerator) buildOperationData(ctx context.Context, method stri
 	fields := make([]*codegen.SnippetField, 0)
 
 	for i := range operation.Parameters {
+		if !isPrimarySource(operation.Parameters[i]) {
+			continue
+		}
 		fields = append(fields, g.ParamField(ctx, operation.Parameters[i]))
 	}
 
@@ -146,7 +150,8 @@ func (g *OperationGenerator) buildOperationData(ctx context.Context, method stri
 	fieldData := g.extractFieldsFromStruct(fields)
 
 	// 获取响应类型和状态错误
-	respType, statusErrors := g.ResponseType(ctx, &operation.Responses)
+	respType, _ := g.ResponseType(ctx, &operation.Responses)
+	statusErrors, _ := statusErrorsOf(&operation.Responses)
 	var respTypeStr string
 	hasResp := false
 	if respType != nil {
@@ -162,7 +167,7 @@ func (g *OperationGenerator) buildOperationData(ctx context.Context, method stri
 		Method:       fmt.Sprintf("%q", method),
 		HasResp:      hasResp,
 		RespType:     respTypeStr,
-		StatusErrors: statusErrors,
+		StatusErrors: parseStatusErrors(statusErrors, nil),
 	}
 }
 
@@ -179,6 +184,26 @@ func (g *OperationGenerator) extractFieldsFromStruct(fields []*codegen.SnippetFi
 	return fieldList
 }
 
+// isPrimarySource 多个来源的参数只在优先级最高的来源生成字段，字段的 in 标签包含所有来源
+func isPrimarySource(parameter *oas.Parameter) bool {
+	var primary string
+	switch sources := parameter.Extensions[openapi.XParamSources].(type) {
+	case []string:
+		if len(sources) > 0 {
+			primary = sources[0]
+		}
+	case []interface{}:
+		if len(sources) > 0 {
+			primary, _ = sources[0].(string)
+		}
+	}
+	if primary == "" {
+		return true
+	}
+	in, _ := ginx.ParamSourceIn(primary)
+	return in == string(parameter.In)
+}
+
 func (g *OperationGenerator) ParamField(ctx context.Context, parameter *oas.Parameter) *codegen.SnippetField {
 	field := NewTypeGenerator(g.ServiceName, g.File).FieldOf(ctx, parameter.Name, parameter.Schema, map[string]bool{
 		parameter.Name: parameter.Required,
@@ -202,18 +227,26 @@ func (g *OperationGenerator) RequestBodyField(ctx context.Context, requestBody *
 		return nil
 	}
 
-	if content != ginx.MineApplicationJson {
+	var field *codegen.SnippetField
+	switch content {
+	case ginx.MineApplicationJson, ginx.MineApplicationJsonPatch:
+		field = NewTypeGenerator(g.ServiceName, g.File).FieldOf(ctx, "Body", mediaType.Schema, map[string]bool{})
+	case ginx.MineApplicationMergePatch:
+		// Merge Patch 只发送设置的字段，使用 map 区分未设置与零值
+		field = codegen.Var(codegen.Map(codegen.String, codegen.Interface()), "Body")
+	default:
 		return nil
 	}
 
-	field := NewTypeGenerator(g.ServiceName, g.File).FieldOf(ctx, "Body", mediaType.Schema, map[string]bool{})
-
 	// 强制设置 in 和 json tag，确保 in:"body" json:"body"
 	tag := field.Tag
 	// 移除可能存在的 json tag，然后重新添加
 	tag = removeTagKey(tag, "json")
 	tag = addTagIfNotExists(tag, "in", "body")
 	tag = addTagIfNotExists(tag, "json", "body")
+	if content != ginx.MineApplicationJson {
+		tag = addTagIfNotExists(tag, "mime", content)
+	}
 	field.Tag = tag
 
 	return field
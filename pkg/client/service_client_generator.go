@@ -44,24 +44,29 @@ type OperationData struct {
 }
 
 func (g *ServiceClientGenerator) Scan(ctx context.Context, openapi *oas.OpenAPI) {
-	// 收集所有操作数据
+	g.render("service_client", TplServiceClient, g.templateData(ctx, openapi))
+}
+
+// templateData 收集所有操作数据
+func (g *ServiceClientGenerator) templateData(ctx context.Context, openapi *oas.OpenAPI) TemplateData {
 	operations := make([]OperationData, 0)
 	eachOperation(openapi, func(method string, path string, op *oas.Operation) {
 		operations = append(operations, g.buildOperationData(ctx, op))
 	})
 
-	// 准备模板数据
 	// 包名与创建 file 时使用的包名一致
 	pkgName := codegen.LowerSnakeCase("Client-" + g.ServiceName)
-	data := TemplateData{
+	return TemplateData{
 		Package:             pkgName,
 		ClientInterfaceName: g.ClientInterfaceName(),
 		ClientInstanceName:  g.ClientInstanceName(),
 		Operations:          operations,
 	}
+}
 
-	// 渲染模板
-	tmpl, err := template.New("service_client").Parse(TplServiceClient)
+// render 渲染模板并写入生成的代码
+func (g *ServiceClientGenerator) render(name string, tpl string, data TemplateData) {
+	tmpl, err := template.New(name).Parse(tpl)
 	if err != nil {
 		panic(err)
 	}
@@ -71,7 +76,6 @@ func (g *ServiceClientGenerator) Scan(ctx context.Context, openapi *oas.OpenAPI)
 		panic(err)
 	}
 
-	// 写入生成的代码
 	g.File.Write(buf.Bytes())
 }
 
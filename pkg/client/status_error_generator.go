@@ -0,0 +1,58 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"text/template"
+
+	"github.com/go-courier/codegen"
+	"github.com/go-courier/oas"
+)
+
+func NewStatusErrorGenerator(serviceName string, file *codegen.File) *StatusErrorGenerator {
+	return &StatusErrorGenerator{
+		ServiceName: serviceName,
+		File:        file,
+	}
+}
+
+// StatusErrorGenerator 生成服务声明的状态错误常量
+type StatusErrorGenerator struct {
+	ServiceName string
+	File        *codegen.File
+}
+
+// StatusErrorTemplateData 状态错误模板数据
+type StatusErrorTemplateData struct {
+	Package      string
+	StatusErrors []StatusErrorData
+}
+
+func (g *StatusErrorGenerator) Scan(ctx context.Context, openapi *oas.OpenAPI) {
+	summaries := make([]string, 0)
+	messages := map[string]string{}
+	eachOperation(openapi, func(method string, path string, op *oas.Operation) {
+		statusErrors, statusErrorMessages := statusErrorsOf(&op.Responses)
+		summaries = append(summaries, statusErrors...)
+		for summary, message := range statusErrorMessages {
+			messages[summary] = message
+		}
+	})
+
+	data := StatusErrorTemplateData{
+		Package:      codegen.LowerSnakeCase("Client-" + g.ServiceName),
+		StatusErrors: parseStatusErrors(summaries, messages),
+	}
+
+	tmpl, err := template.New("status_error").Parse(TplStatusError)
+	if err != nil {
+		panic(err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		panic(err)
+	}
+
+	g.File.Write(buf.Bytes())
+}
@@ -13,3 +13,8 @@ var TplOperation string
 //go:embed template/options.tpl
 var TplOptions string
 
+//go:embed template/mock.tpl
+var TplMock string
+
+//go:embed template/status_error.tpl
+var TplStatusError string
@@ -18,9 +18,10 @@ func (req *{{ .OperationId }}) Method() string {
 }
 
 {{if .StatusErrors}}
-{{range .StatusErrors}}
-// {{ . }}
-{{end}}
+// StatusErrors 返回接口声明的状态错误
+func (req *{{ .OperationId }}) StatusErrors() []StatusError {
+	return []StatusError{ {{- range $i, $e := .StatusErrors}}{{if $i}}, {{end}}{{ $e.Name }}{{end -}} }
+}
 {{end}}
 
 {{end}}
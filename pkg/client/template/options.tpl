@@ -54,3 +54,53 @@ func WithDefaultInvokeMode(mode ginx.InvokeMode) ClientOption {
 	}
 }
 
+// WithServiceName 通过服务发现调用指定服务，需先调用 ginx.RegisterResolver 注册服务解析器
+// 设置后构造函数中的 host/port 将被忽略，schema 为空时默认使用 http
+func WithServiceName(name string) ClientOption {
+	return func(c *{{ .ClientInstanceName }}) {
+		c.config.ServiceName = name
+	}
+}
+
+// WithDefaultRetry 设置默认重试策略，backoff/retryOn 为 nil 时使用 ginx.DefaultBackoff/ginx.DefaultRetryOn
+func WithDefaultRetry(max int, backoff ginx.BackoffFunc, retryOn ginx.RetryOnFunc) ClientOption {
+	return func(c *{{ .ClientInstanceName }}) {
+		c.config.Retry = &ginx.RetryPolicy{Max: max, Backoff: backoff, RetryOn: retryOn}
+	}
+}
+
+// WithDefaultCircuitBreaker 设置默认熔断器，熔断状态按目标主机维护
+func WithDefaultCircuitBreaker(cb *ginx.CircuitBreaker) ClientOption {
+	return func(c *{{ .ClientInstanceName }}) {
+		c.config.CircuitBreaker = cb
+	}
+}
+
+// WithInterceptors 设置客户端拦截器，在全局拦截器之后、单次请求拦截器之前执行
+func WithInterceptors(interceptors ...ginx.ClientInterceptor) ClientOption {
+	return func(c *{{ .ClientInstanceName }}) {
+		c.config.Interceptors = append(c.config.Interceptors, interceptors...)
+	}
+}
+
+// WithClientConfig 使用 ginx.ClientConfig 设置默认的地址、超时与 Transport，未设置的字段不生效
+func WithClientConfig(config ginx.ClientConfig) ClientOption {
+	return func(c *{{ .ClientInstanceName }}) {
+		ginx.WithClientConfig(config)(c.config)
+	}
+}
+
+// WithDefaultTransport 设置默认 Transport，支持 otelhttp 等包装过的 http.RoundTripper
+func WithDefaultTransport(transport http.RoundTripper) ClientOption {
+	return func(c *{{ .ClientInstanceName }}) {
+		c.config.Transport = transport
+	}
+}
+
+// WithDefaultMaxConnsPerHost 设置默认 Transport 的单主机最大连接数
+func WithDefaultMaxConnsPerHost(n int) ClientOption {
+	return func(c *{{ .ClientInstanceName }}) {
+		c.config.MaxConnsPerHost = n
+	}
+}
+
@@ -171,11 +171,8 @@ func (g *TypeGenerator) TypeIndirect(ctx context.Context, schema *oas.Schema) (c
 			}
 
 			for _, e := range schema.Enum {
-				o := enum.Value{}
-				value := e.(string)
-				o.Key = strings.ToUpper(value)
-				o.StringValue = &value
-				o.Label = enumLabels[value]
+				o := enumValue(schema.Type, e)
+				o.Label = enumLabels[fmt.Sprint(o.Type())]
 
 				enumValues = append(enumValues, o)
 			}
@@ -381,48 +378,115 @@ func mayComposedAllOf(schema *oas.Schema) *oas.Schema {
 	return schema
 }
 
+// enumValue 将文档中的枚举值转换为 enum.Value，JSON 中的数字统一解析为 float64
+func enumValue(schemaType oas.Type, v interface{}) enum.Value {
+	o := enum.Value{}
+	switch n := v.(type) {
+	case float64:
+		if schemaType == oas.TypeInteger {
+			i := int64(n)
+			o.IntValue = &i
+			o.Key = strconv.FormatInt(i, 10)
+		} else {
+			o.FloatValue = &n
+			o.Key = strings.Replace(strconv.FormatFloat(n, 'f', -1, 64), ".", "_", 1)
+		}
+	case int64:
+		o.IntValue = &n
+		o.Key = strconv.FormatInt(n, 10)
+	default:
+		value := fmt.Sprint(v)
+		o.StringValue = &value
+		o.Key = strings.ToUpper(value)
+	}
+	return o
+}
+
+// writeEnumDefines 生成枚举类型、常量及 enum.Enum 接口的实现
 func writeEnumDefines(file *codegen.File, name string, enumValues enum.Values) {
 	if len(enumValues) == 0 {
 		return
 	}
 
+	var basicType codegen.SnippetType
 	switch enumValues[0].Type().(type) {
 	case int64:
-		file.WriteBlock(
-			codegen.DeclType(codegen.Var(codegen.Int64, name)),
-		)
+		basicType = codegen.Int64
 	case float64:
-		file.WriteBlock(
-			codegen.DeclType(codegen.Var(codegen.Float64, name)),
-		)
-	case string:
-		file.WriteBlock(
-			codegen.DeclType(codegen.Var(codegen.String, name)),
-		)
+		basicType = codegen.Float64
+	default:
+		basicType = codegen.String
 	}
 
+	file.WriteBlock(
+		codegen.DeclType(codegen.Var(basicType, name)),
+	)
+
 	file.WriteString(`
 const (
 `)
 
 	sort.Sort(enumValues)
 
+	keys := make([]string, 0, len(enumValues))
 	for _, item := range enumValues {
-		v := item.Type()
-		value := v
-
-		switch n := v.(type) {
-		case string:
-			value = strconv.Quote(n)
-		case float64:
-			vf := v.(float64)
-			v = strings.Replace(strconv.FormatFloat(vf, 'f', -1, 64), ".", "_", 1)
+		key := codegen.UpperSnakeCase(name) + "__" + item.Key
+		keys = append(keys, key)
+
+		value := item.Type()
+		if s, ok := value.(string); ok {
+			value = strconv.Quote(s)
 		}
 
-		_, _ = fmt.Fprintf(file, `%s__%v %s = %v // %s
-`, codegen.UpperSnakeCase(name), strings.ToUpper(v.(string)), name, value, item.Label)
+		_, _ = fmt.Fprintf(file, `%s %s = %v // %s
+`, key, name, value, item.Label)
 	}
 
 	file.WriteString(`)
 `)
+
+	enumType := file.Use("github.com/shrewx/ginx/pkg/enum", "Enum")
+
+	intValue, stringValue := "0", "string(v)"
+	switch basicType {
+	case codegen.Int64:
+		intValue, stringValue = "int(v)", file.Use("strconv", "FormatInt")+"(int64(v), 10)"
+	case codegen.Float64:
+		intValue, stringValue = "int(v)", file.Use("strconv", "FormatFloat")+"(float64(v), 'f', -1, 64)"
+	}
+
+	_, _ = fmt.Fprintf(file, `
+func (v %[1]s) Int() int {
+	return %[2]s
+}
+
+func (v %[1]s) String() string {
+	return %[3]s
+}
+
+// Label 返回枚举值的描述
+func (v %[1]s) Label() string {
+	switch v {
+`, name, intValue, stringValue)
+
+	for i, item := range enumValues {
+		_, _ = fmt.Fprintf(file, `	case %s:
+		return %s
+`, keys[i], strconv.Quote(item.Label))
+	}
+
+	_, _ = fmt.Fprintf(file, `	}
+	return ""
+}
+
+// Values 返回所有枚举值
+func (v %[1]s) Values() []%[2]s {
+	return []%[2]s{%[3]s}
+}
+
+func (v %[1]s) Type() string {
+	return %[4]q
+}
+
+`, name, enumType, strings.Join(keys, ", "), name)
 }
@@ -1,11 +1,16 @@
 package client
 
 import (
-	"github.com/shrewx/ginx/pkg/openapi"
+	"fmt"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 
+	"github.com/go-courier/codegen"
 	"github.com/go-courier/oas"
+	"github.com/shrewx/ginx"
+	"github.com/shrewx/ginx/pkg/openapi"
 )
 
 func mayPrefixDeprecated(desc string, deprecated bool) []string {
@@ -64,11 +69,19 @@ func requestBodyMediaType(requestBody *oas.RequestBody) (string, *oas.MediaType)
 		return "", nil
 	}
 
+	// 声明了多种类型时优先使用 JSON，其次按名称排序的第一个类型
+	if mediaType, ok := requestBody.Content[ginx.MineApplicationJson]; ok {
+		return ginx.MineApplicationJson, mediaType
+	}
+	contentTypes := make([]string, 0, len(requestBody.Content))
 	for contentType := range requestBody.Content {
-		mediaType := requestBody.Content[contentType]
-		return contentType, mediaType
+		contentTypes = append(contentTypes, contentType)
+	}
+	if len(contentTypes) == 0 {
+		return "", nil
 	}
-	return "", nil
+	sort.Strings(contentTypes)
+	return contentTypes[0], requestBody.Content[contentTypes[0]]
 }
 
 func mediaTypeAndStatusErrors(responses *oas.Responses) (*oas.MediaType, []string) {
@@ -111,3 +124,95 @@ func mediaTypeAndStatusErrors(responses *oas.Responses) (*oas.MediaType, []strin
 
 	return nil, statusErrors
 }
+
+var reStatusErrorSummary = regexp.MustCompile(`^\[([^\]]+)\]\[(\d+)\]`)
+
+// StatusErrorData 状态错误数据
+type StatusErrorData struct {
+	Name    string
+	Key     string
+	Code    int64
+	Summary string
+	Message string
+}
+
+// statusErrorsOf 返回响应中声明的状态错误及其多语言消息，消息按语言排序后拼接
+func statusErrorsOf(responses *oas.Responses) ([]string, map[string]string) {
+	statusErrors := make([]string, 0)
+	messages := map[string]string{}
+	if responses == nil {
+		return statusErrors, messages
+	}
+
+	for code, resp := range responses.Responses {
+		if isOk(code) || resp == nil || resp.Extensions == nil {
+			continue
+		}
+		if errs, ok := resp.Extensions[openapi.XStatusErrs].([]interface{}); ok {
+			for _, err := range errs {
+				statusErrors = append(statusErrors, err.(string))
+			}
+		}
+		if m, ok := resp.Extensions[openapi.XStatusErrMessages].(map[string]interface{}); ok {
+			for summary, langs := range m {
+				if langs, ok := langs.(map[string]interface{}); ok {
+					list := make([]string, 0, len(langs))
+					for _, lang := range sortedKeys(langs) {
+						list = append(list, fmt.Sprint(langs[lang]))
+					}
+					messages[summary] = strings.Join(list, " / ")
+				}
+			}
+		}
+	}
+
+	sort.Strings(statusErrors)
+	return statusErrors, messages
+}
+
+// parseStatusErrors 解析 [Key][Code] 格式的状态错误，按错误码去重，常量名为 Err 加上 key
+func parseStatusErrors(summaries []string, messages map[string]string) []StatusErrorData {
+	statusErrors := make([]StatusErrorData, 0)
+	codes := map[int64]bool{}
+	names := map[string]bool{}
+
+	for _, summary := range summaries {
+		matched := reStatusErrorSummary.FindStringSubmatch(summary)
+		if matched == nil {
+			continue
+		}
+		code, _ := strconv.ParseInt(matched[2], 10, 64)
+		if codes[code] {
+			continue
+		}
+		codes[code] = true
+
+		name := "Err" + codegen.UpperCamelCase(matched[1])
+		if names[name] {
+			name = name + matched[2]
+		}
+		names[name] = true
+
+		statusErrors = append(statusErrors, StatusErrorData{
+			Name:    name,
+			Key:     matched[1],
+			Code:    code,
+			Summary: matched[0],
+			Message: messages[summary],
+		})
+	}
+
+	sort.Slice(statusErrors, func(i, j int) bool {
+		return statusErrors[i].Name < statusErrors[j].Name
+	})
+	return statusErrors
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -17,10 +17,22 @@ type Server struct {
 	Https bool `yaml:"https" env:"SERVER_HTTPS"`
 	// 退出等待超时时间(秒)
 	ExitWaitTimeout int `yaml:"exit_wait_timeout" env:"SERVER_EXIT_WAIT_TIMEOUT"`
+	// 摘除流量后等待负载均衡感知的时间(秒)，之后才开始排空请求
+	ShutdownDelay int `yaml:"shutdown_delay" env:"SERVER_SHUTDOWN_DELAY"`
 
 	// 是否打印请求参数
 	ShowParams bool `yaml:"show_params" env:"SERVER_SHOW_PARAMS"`
 
+	// 请求体大小上限(字节)，0 表示不限制，操作符可通过 BodyLimit() 单独设置
+	MaxBodySize int64 `yaml:"max_body_size" env:"SERVER_MAX_BODY_SIZE"`
+	// multipart 表单保存在内存中的大小(字节)，超出部分写入临时文件，0 表示使用默认值 32MB
+	MultipartMemory int64 `yaml:"multipart_memory" env:"SERVER_MULTIPART_MEMORY"`
+	// multipart 临时文件目录，为空时使用系统临时目录
+	MultipartTempDir string `yaml:"multipart_temp_dir" env:"SERVER_MULTIPART_TEMP_DIR"`
+
+	// 响应压缩，为空时不压缩
+	Compression *Compression `yaml:"compression"`
+
 	Log *Log `yaml:"log" env:"SERVER_LOG"`
 
 	I18N *I18N `yaml:"i18n" env:"SERVER_I18N"`
@@ -47,13 +59,42 @@ type Trace struct {
 }
 
 type Discovery struct {
+	// 注册中心类型(consul/etcd/nacos/file)，未通过 ginx.Register 注册服务发现时按此创建
+	Type string `yaml:"type"`
+	// 注册中心地址，file 类型为注册文件路径
+	Endpoints []string `yaml:"endpoints"`
+	Username  string   `yaml:"username"`
+	Password  string   `yaml:"password"`
+	Token     string   `yaml:"token"`
+	// etcd 为 key 前缀，nacos 为命名空间 ID
+	Namespace string `yaml:"namespace"`
+	// nacos 服务分组
+	Group string `yaml:"group"`
+
 	Address   string   `yaml:"address"`
 	Tags      []string `yaml:"tags"`
 	HeathPath string   `yaml:"heath_path"`
 
-	Timeout        int `yaml:"timeout"`
-	Interval       int `yaml:"interval"`
-	DeregisterTime int `yaml:"deregister_time"`
+	// 服务元数据，客户端发现时可用于路由
+	Meta map[string]string `yaml:"meta"`
+	// 服务权重，用于客户端加权负载均衡
+	Weight int `yaml:"weight"`
+
+	// 健康检查方式(http/ttl)，ttl 方式下服务按 Interval 主动上报心跳
+	CheckType      string `yaml:"check_type"`
+	Timeout        int    `yaml:"timeout"`
+	Interval       int    `yaml:"interval"`
+	TTL            int    `yaml:"ttl"`
+	DeregisterTime int    `yaml:"deregister_time"`
+}
+
+type Compression struct {
+	// 压缩级别，0 表示使用各压缩算法的默认级别，gzip 与 deflate 支持 -2 到 9，不支持时启动失败
+	Level int `yaml:"level"`
+	// 最小压缩大小(字节)，响应体小于该值时不压缩，0 表示使用默认值 1KB
+	MinSize int `yaml:"min_size"`
+	// 可压缩的响应类型，支持 text/* 与 application/*+json 形式，为空时使用默认列表
+	Types []string `yaml:"types"`
 }
 
 type I18N struct {
@@ -130,6 +171,35 @@ func WithGraceExitTime(timeout int) Option {
 	}
 }
 
+func WithShutdownDelay(delay int) Option {
+	return func(s *Server) {
+		s.ShutdownDelay = delay
+	}
+}
+
+func WithBodyLimit(maxBodySize int64) Option {
+	return func(s *Server) {
+		s.MaxBodySize = maxBodySize
+	}
+}
+
+func WithMultipart(memory int64, tempDir string) Option {
+	return func(s *Server) {
+		s.MultipartMemory = memory
+		s.MultipartTempDir = tempDir
+	}
+}
+
+func WithCompression(level, minSize int, types ...string) Option {
+	return func(s *Server) {
+		s.Compression = &Compression{
+			Level:   level,
+			MinSize: minSize,
+			Types:   types,
+		}
+	}
+}
+
 func WithTrace(endpoint, exporter string) Option {
 	return func(s *Server) {
 		s.TraceEndpoint = endpoint
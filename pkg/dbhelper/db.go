@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"github.com/glebarez/sqlite"
 	"github.com/shrewx/ginx/pkg/conf"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/pkg/errors"
+	e2 "github.com/shrewx/ginx/internal/errors"
 	"github.com/shrewx/ginx/pkg/logx"
 	"golang.org/x/exp/maps"
 	"gorm.io/driver/mysql"
@@ -20,6 +22,8 @@ import (
 
 var (
 	tables = make(map[string]interface{}, 0)
+
+	reColumnName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
 )
 
 const DBKey = "db-instance-key"
@@ -140,11 +144,20 @@ func GetCtxDB(ctx context.Context, defaultDB *gorm.DB) *gorm.DB {
 	}
 }
 
+// DBConditionWithSearchAttrs 根据查询属性构建条件，key 为列名，多个列以 | 分隔表示 OR 条件
+// 列名不是合法的标识符时查询返回参数错误；接口参数建议使用 Filtering 并通过 Columns 白名单映射列名
 func DBConditionWithSearchAttrs(db *gorm.DB, searchAttrs map[string]interface{}, eqAttrs map[string]struct{}) *gorm.DB {
 	if eqAttrs == nil {
 		eqAttrs = map[string]struct{}{}
 	}
 	for k, v := range searchAttrs {
+		for _, field := range strings.Split(k, "|") {
+			if !reColumnName.MatchString(field) {
+				_ = db.AddError(e2.BadRequest.WithField("search", k))
+				return db
+			}
+		}
+
 		if strings.Contains(k, "|") {
 			fields := strings.Split(k, "|")
 
@@ -0,0 +1,41 @@
+package dbhelper
+
+import (
+	"gorm.io/gorm"
+)
+
+// Patch PATCH 请求的补丁，ginx.MergePatch 与 ginx.JSONPatch 均实现了该接口
+type Patch interface {
+	// Apply 将补丁应用到结构体指针中
+	Apply(target interface{}) error
+	// Fields 补丁修改的 JSON 字段名
+	Fields() []string
+}
+
+// ApplyPatch 将补丁应用到已查询的模型中，并只更新补丁修改的列，值为零值的列同样会被更新
+// columns 为允许修改的字段白名单，key 为 JSON 字段名，补丁修改不在白名单中的字段时返回参数错误
+//
+//	var user User
+//	if err := db.First(&user, id).Error; err != nil {
+//		return err
+//	}
+//	err := dbhelper.ApplyPatch(db, &user, op.Data, dbhelper.Columns{"name": "name", "email": "email"})
+func ApplyPatch(db *gorm.DB, model interface{}, patch Patch, columns Columns) error {
+	fields := patch.Fields()
+	selects := make([]string, 0, len(fields))
+	for _, field := range fields {
+		column, err := columns.Column("body", field)
+		if err != nil {
+			return err
+		}
+		selects = append(selects, column)
+	}
+	if len(selects) == 0 {
+		return nil
+	}
+
+	if err := patch.Apply(model); err != nil {
+		return err
+	}
+	return db.Model(model).Select(selects).Updates(model).Error
+}
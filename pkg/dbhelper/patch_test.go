@@ -0,0 +1,89 @@
+package dbhelper
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shrewx/ginx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testProduct struct {
+	ID     int64  `gorm:"primaryKey" json:"id"`
+	Name   string `json:"name"`
+	Stock  int    `json:"stock"`
+	Active bool   `json:"active"`
+	Owner  string `json:"owner"`
+}
+
+type testProductPatch struct {
+	Name   string `json:"name"`
+	Stock  int    `json:"stock"`
+	Active bool   `json:"active"`
+	Owner  string `json:"owner"`
+}
+
+func TestApplyPatch(t *testing.T) {
+	db := newTestDB(t)
+	require.NoError(t, db.AutoMigrate(&testProduct{}))
+	columns := NewColumns("name", "stock", "active")
+
+	load := func(id int64) *testProduct {
+		product := &testProduct{}
+		require.NoError(t, db.First(product, id).Error)
+		return product
+	}
+
+	tests := []struct {
+		name    string
+		patch   func(t *testing.T) Patch
+		want    testProduct
+		wantErr bool
+	}{
+		{
+			name: "merge patch updates zero values",
+			patch: func(t *testing.T) Patch {
+				var patch ginx.MergePatch[testProductPatch]
+				require.NoError(t, json.Unmarshal([]byte(`{"stock":0,"active":false}`), &patch))
+				return patch
+			},
+			want: testProduct{Name: "phone", Stock: 0, Active: false, Owner: "tom"},
+		},
+		{
+			name: "json patch updates zero values",
+			patch: func(t *testing.T) Patch {
+				var patch ginx.JSONPatch
+				require.NoError(t, json.Unmarshal([]byte(`[{"op":"replace","path":"/name","value":""},{"op":"replace","path":"/stock","value":0}]`), &patch))
+				return patch
+			},
+			want: testProduct{Name: "", Stock: 0, Active: true, Owner: "tom"},
+		},
+		{
+			name: "field not in whitelist",
+			patch: func(t *testing.T) Patch {
+				var patch ginx.MergePatch[testProductPatch]
+				require.NoError(t, json.Unmarshal([]byte(`{"name":"tablet","owner":"jerry"}`), &patch))
+				return patch
+			},
+			want:    testProduct{Name: "phone", Stock: 10, Active: true, Owner: "tom"},
+			wantErr: true,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := int64(i + 1)
+			require.NoError(t, db.Create(&testProduct{ID: id, Name: "phone", Stock: 10, Active: true, Owner: "tom"}).Error)
+
+			err := ApplyPatch(db, load(id), tt.patch(t), columns)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			tt.want.ID = id
+			assert.Equal(t, &tt.want, load(id))
+		})
+	}
+}
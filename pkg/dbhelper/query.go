@@ -0,0 +1,271 @@
+package dbhelper
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	e2 "github.com/shrewx/ginx/internal/errors"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 1000
+)
+
+// Pagination 偏移分页参数，嵌入列表接口的请求结构体后由 ParameterBinding 绑定
+type Pagination struct {
+	// 页码，从 1 开始
+	Page int `in:"query" name:"page,default=1" validate:"gte=1"`
+	// 每页数量
+	Size int `in:"query" name:"size,default=20" validate:"gte=1,lte=1000"`
+}
+
+// Offset 返回查询的偏移量
+func (p Pagination) Offset() int {
+	if p.Page <= 1 {
+		return 0
+	}
+	return (p.Page - 1) * p.Limit()
+}
+
+// Limit 返回查询的数量，未设置时使用默认值
+func (p Pagination) Limit() int {
+	switch {
+	case p.Size <= 0:
+		return DefaultPageSize
+	case p.Size > MaxPageSize:
+		return MaxPageSize
+	}
+	return p.Size
+}
+
+// CursorPagination 游标分页参数，游标为上一页最后一条记录排序列与主键的值
+type CursorPagination struct {
+	// 游标，为空时从第一条记录开始
+	Cursor string `in:"query" name:"cursor"`
+	// 每页数量
+	Size int `in:"query" name:"size,default=20" validate:"gte=1,lte=1000"`
+}
+
+// Limit 返回查询的数量，未设置时使用默认值
+func (p CursorPagination) Limit() int {
+	return Pagination{Size: p.Size}.Limit()
+}
+
+// cursorData 游标的内容，值按 JSON 编码以保留类型，时间按 RFC3339Nano 编码并在解码时还原为 time.Time
+type cursorData struct {
+	Value  interface{} `json:"v"`
+	ID     interface{} `json:"id"`
+	IsTime bool        `json:"t,omitempty"`
+}
+
+// EncodeCursor 将排序列与主键的值编码为游标，主键用于排序列的值相同时确定记录的先后
+func EncodeCursor(value, id interface{}) string {
+	data := cursorData{Value: value, ID: id}
+	if t, ok := value.(*time.Time); ok && t != nil {
+		value = *t
+	}
+	if t, ok := value.(time.Time); ok {
+		data.Value, data.IsTime = t.Format(time.RFC3339Nano), true
+	}
+	b, _ := json.Marshal(data)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor 解码游标，返回排序列与主键的值
+// 整数解码为 int64，其他数字为 float64，编码时为时间的值解码为 time.Time
+func DecodeCursor(cursor string) (value interface{}, id interface{}, err error) {
+	invalid := e2.BadRequest.WithField("cursor", cursor)
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, nil, invalid
+	}
+
+	var data cursorData
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	if err := decoder.Decode(&data); err != nil || data.Value == nil || data.ID == nil {
+		return nil, nil, invalid
+	}
+
+	if data.IsTime {
+		s, _ := data.Value.(string)
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return nil, nil, invalid
+		}
+		return t, cursorNumber(data.ID), nil
+	}
+	return cursorNumber(data.Value), cursorNumber(data.ID), nil
+}
+
+func cursorNumber(v interface{}) interface{} {
+	n, ok := v.(json.Number)
+	if !ok {
+		return v
+	}
+	if i, err := n.Int64(); err == nil {
+		return i
+	}
+	f, _ := n.Float64()
+	return f
+}
+
+// Order 排序条件
+type Order struct {
+	Field string
+	Desc  bool
+}
+
+// Sorting 排序参数，多个字段以逗号分隔，字段前加 - 表示降序，如 sort=-created_at,name
+type Sorting struct {
+	// 排序字段，多个字段以逗号分隔，字段前加 - 表示降序
+	Sort string `in:"query" name:"sort"`
+}
+
+// Orders 解析排序条件
+func (s Sorting) Orders() []Order {
+	orders := make([]Order, 0)
+	for _, field := range strings.Split(s.Sort, ",") {
+		field = strings.TrimSpace(field)
+		desc := strings.HasPrefix(field, "-")
+		field = strings.TrimLeft(field, "+-")
+		if field == "" {
+			continue
+		}
+		orders = append(orders, Order{Field: field, Desc: desc})
+	}
+	return orders
+}
+
+// 过滤操作符
+const (
+	FilterEq   = "eq"
+	FilterNe   = "ne"
+	FilterGt   = "gt"
+	FilterGte  = "gte"
+	FilterLt   = "lt"
+	FilterLte  = "lte"
+	FilterLike = "like"
+	FilterIn   = "in"
+)
+
+var filterOps = map[string]bool{
+	FilterEq: true, FilterNe: true, FilterGt: true, FilterGte: true,
+	FilterLt: true, FilterLte: true, FilterLike: true, FilterIn: true,
+}
+
+// Filter 过滤条件
+type Filter struct {
+	Field  string
+	Op     string
+	Values []string
+}
+
+// Filtering 过滤参数，格式为 field:op:value，可重复传递，如 filter=status:in:active|locked&filter=name:like:tom
+// op 支持 eq、ne、gt、gte、lt、lte、like、in，省略时为 eq；in 的多个值以 | 分隔
+type Filtering struct {
+	// 过滤条件，格式为 field:op:value
+	Filter []string `in:"query" name:"filter"`
+}
+
+// Filters 解析过滤条件
+func (f Filtering) Filters() ([]Filter, error) {
+	filters := make([]Filter, 0, len(f.Filter))
+	for _, expr := range f.Filter {
+		parts := strings.SplitN(expr, ":", 3)
+		if len(parts) < 2 || parts[0] == "" {
+			return nil, e2.BadRequest.WithField("filter", expr)
+		}
+
+		filter := Filter{Field: parts[0], Op: FilterEq}
+		value := parts[1]
+		if len(parts) == 3 {
+			filter.Op = strings.ToLower(parts[1])
+			value = parts[2]
+		}
+		if !filterOps[filter.Op] {
+			return nil, e2.BadRequest.WithField("filter", expr)
+		}
+
+		if filter.Op == FilterIn {
+			filter.Values = strings.Split(value, "|")
+		} else {
+			filter.Values = []string{value}
+		}
+		filters = append(filters, filter)
+	}
+	return filters, nil
+}
+
+// Columns 允许排序与过滤的字段，key 为接口中的字段名，value 为数据库列名
+type Columns map[string]string
+
+// NewColumns 创建字段名与列名相同的白名单
+func NewColumns(fields ...string) Columns {
+	columns := make(Columns, len(fields))
+	for _, field := range fields {
+		columns[field] = field
+	}
+	return columns
+}
+
+// Column 返回字段对应的列名，不在白名单中时返回参数错误
+func (c Columns) Column(param string, field string) (string, error) {
+	if column, ok := c[field]; ok && column != "" {
+		return column, nil
+	}
+	return "", e2.BadRequest.WithField(param, field)
+}
+
+// PageResult 列表接口的标准分页响应
+type PageResult[T any] struct {
+	// 数据列表
+	Items []T `json:"items"`
+	// 总数，偏移分页时总是返回，游标分页时为空
+	Total *int64 `json:"total,omitempty"`
+	// 页码，偏移分页时总是返回，游标分页时为空
+	Page *int `json:"page,omitempty"`
+	// 每页数量
+	Size int `json:"size"`
+	// 下一页的游标，为空时表示没有更多数据
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// NewPageResult 创建偏移分页的响应
+func NewPageResult[T any](items []T, total int64, p Pagination) *PageResult[T] {
+	if items == nil {
+		items = make([]T, 0)
+	}
+	page := p.Page
+	if page < 1 {
+		page = 1
+	}
+	return &PageResult[T]{
+		Items: items,
+		Total: &total,
+		Page:  &page,
+		Size:  p.Limit(),
+	}
+}
+
+// NewCursorPageResult 创建游标分页的响应，items 需通过 CursorPagination.Scope 多查询一条用于判断是否有下一页
+// cursorOf 返回记录排序列与主键的值
+func NewCursorPageResult[T any](items []T, p CursorPagination, cursorOf func(item T) (value, id interface{})) *PageResult[T] {
+	result := &PageResult[T]{
+		Items: items,
+		Size:  p.Limit(),
+	}
+	if len(items) > p.Limit() {
+		result.Items = items[:p.Limit()]
+		value, id := cursorOf(result.Items[len(result.Items)-1])
+		result.NextCursor = EncodeCursor(value, id)
+	}
+	if result.Items == nil {
+		result.Items = make([]T, 0)
+	}
+	return result
+}
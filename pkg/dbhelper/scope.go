@@ -0,0 +1,136 @@
+package dbhelper
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Scope 返回偏移分页的 GORM scope
+func (p Pagination) Scope() func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Offset(p.Offset()).Limit(p.Limit())
+	}
+}
+
+// Scope 返回游标分页的 GORM scope，按 column 与主键 primaryKey 排序并多查询一条用于判断是否有下一页
+// column 前加 - 表示降序，主键按相同方向排序，保证排序列的值重复时翻页不会遗漏或重复记录
+func (p CursorPagination) Scope(column string, primaryKey string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		desc := strings.HasPrefix(column, "-")
+		col := clause.Column{Name: strings.TrimPrefix(column, "-")}
+		pk := clause.Column{Name: primaryKey}
+
+		if p.Cursor != "" {
+			value, id, err := DecodeCursor(p.Cursor)
+			if err != nil {
+				_ = db.AddError(err)
+				return db
+			}
+			after := func(col clause.Column, value interface{}) clause.Expression {
+				if desc {
+					return clause.Lt{Column: col, Value: value}
+				}
+				return clause.Gt{Column: col, Value: value}
+			}
+			if col.Name == pk.Name {
+				db = db.Where(after(pk, id))
+			} else {
+				db = db.Where(clause.Or(after(col, value), clause.And(clause.Eq{Column: col, Value: value}, after(pk, id))))
+			}
+		}
+
+		db = db.Order(clause.OrderByColumn{Column: col, Desc: desc})
+		if col.Name != pk.Name {
+			db = db.Order(clause.OrderByColumn{Column: pk, Desc: desc})
+		}
+		return db.Limit(p.Limit() + 1)
+	}
+}
+
+// Scope 返回排序的 GORM scope，字段不在白名单中时查询返回参数错误
+func (s Sorting) Scope(columns Columns) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		for _, order := range s.Orders() {
+			column, err := columns.Column("sort", order.Field)
+			if err != nil {
+				_ = db.AddError(err)
+				return db
+			}
+			db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: column}, Desc: order.Desc})
+		}
+		return db
+	}
+}
+
+// Scope 返回过滤的 GORM scope，字段不在白名单中或表达式错误时查询返回参数错误
+func (f Filtering) Scope(columns Columns) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		filters, err := f.Filters()
+		if err != nil {
+			_ = db.AddError(err)
+			return db
+		}
+
+		for _, filter := range filters {
+			column, err := columns.Column("filter", filter.Field)
+			if err != nil {
+				_ = db.AddError(err)
+				return db
+			}
+			db = db.Where(filterExpression(clause.Column{Name: column}, filter))
+		}
+		return db
+	}
+}
+
+func filterExpression(col clause.Column, filter Filter) clause.Expression {
+	value := filter.Values[0]
+	switch filter.Op {
+	case FilterNe:
+		return clause.Neq{Column: col, Value: value}
+	case FilterGt:
+		return clause.Gt{Column: col, Value: value}
+	case FilterGte:
+		return clause.Gte{Column: col, Value: value}
+	case FilterLt:
+		return clause.Lt{Column: col, Value: value}
+	case FilterLte:
+		return clause.Lte{Column: col, Value: value}
+	case FilterLike:
+		// SQLite 的 LIKE 默认没有转义字符，需显式指定
+		return clause.Expr{SQL: "? LIKE ? ESCAPE ?", Vars: []interface{}{col, "%" + escapeLike(value) + "%", `\`}}
+	case FilterIn:
+		values := make([]interface{}, 0, len(filter.Values))
+		for _, v := range filter.Values {
+			values = append(values, v)
+		}
+		return clause.IN{Column: col, Values: values}
+	}
+	return clause.Eq{Column: col, Value: value}
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// FindPage 查询总数与当前页的数据，scopes 同时作用于计数与数据查询，计数时 GORM 会忽略排序
+//
+//	result, err := dbhelper.FindPage[User](db.Model(&User{}), req.Pagination, req.Filtering.Scope(columns), req.Sorting.Scope(columns))
+func FindPage[T any](db *gorm.DB, p Pagination, scopes ...func(*gorm.DB) *gorm.DB) (*PageResult[T], error) {
+	var total int64
+	if err := db.Session(&gorm.Session{}).Scopes(scopes...).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	items := make([]T, 0)
+	if total > int64(p.Offset()) {
+		if err := db.Session(&gorm.Session{}).Scopes(scopes...).Scopes(p.Scope()).Find(&items).Error; err != nil {
+			return nil, err
+		}
+	}
+	return NewPageResult(items, total, p), nil
+}
@@ -0,0 +1,272 @@
+package dbhelper
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type testUser struct {
+	ID        int64 `gorm:"primaryKey"`
+	Name      string
+	Status    string
+	Age       int
+	CreatedAt time.Time
+}
+
+func newTestDB(t *testing.T, users ...testUser) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&testUser{}))
+	if len(users) > 0 {
+		require.NoError(t, db.Create(&users).Error)
+	}
+	return db
+}
+
+var testCreatedAt = time.Date(2024, 1, 1, 1, 0, 0, 123456789, time.UTC)
+
+var testUsers = []testUser{
+	{ID: 1, Name: "tom", Status: "active", Age: 20, CreatedAt: testCreatedAt},
+	{ID: 2, Name: "jerry", Status: "locked", Age: 30, CreatedAt: testCreatedAt.Add(time.Hour)},
+	{ID: 3, Name: "100%_off", Status: "active", Age: 30, CreatedAt: testCreatedAt.Add(time.Hour)},
+	{ID: 4, Name: `a\b`, Status: "deleted", Age: 40, CreatedAt: testCreatedAt.Add(2 * time.Hour)},
+	{ID: 5, Name: "tommy", Status: "active", Age: 30, CreatedAt: testCreatedAt.Add(time.Hour)},
+}
+
+func names(users []testUser) []string {
+	list := make([]string, 0, len(users))
+	for _, u := range users {
+		list = append(list, u.Name)
+	}
+	return list
+}
+
+func TestFiltering_Filters(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  []string
+		want    []Filter
+		wantErr bool
+	}{
+		{name: "default eq", filter: []string{"name:tom"}, want: []Filter{{Field: "name", Op: FilterEq, Values: []string{"tom"}}}},
+		{name: "op", filter: []string{"age:GTE:18"}, want: []Filter{{Field: "age", Op: FilterGte, Values: []string{"18"}}}},
+		{name: "in", filter: []string{"status:in:active|locked"}, want: []Filter{{Field: "status", Op: FilterIn, Values: []string{"active", "locked"}}}},
+		{name: "value contains colon", filter: []string{"name:eq:a:b"}, want: []Filter{{Field: "name", Op: FilterEq, Values: []string{"a:b"}}}},
+		{name: "unknown op", filter: []string{"name:regex:t.*"}, wantErr: true},
+		{name: "missing value", filter: []string{"name"}, wantErr: true},
+		{name: "missing field", filter: []string{":tom"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters, err := Filtering{Filter: tt.filter}.Filters()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, filters)
+		})
+	}
+}
+
+func TestFiltering_Scope(t *testing.T) {
+	db := newTestDB(t, testUsers...)
+	columns := NewColumns("name", "status", "age")
+
+	tests := []struct {
+		name    string
+		filter  []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "eq", filter: []string{"name:tom"}, want: []string{"tom"}},
+		{name: "ne", filter: []string{"status:ne:active"}, want: []string{"jerry", `a\b`}},
+		{name: "gt", filter: []string{"age:gt:30"}, want: []string{`a\b`}},
+		{name: "lte", filter: []string{"age:lte:20"}, want: []string{"tom"}},
+		{name: "in", filter: []string{"status:in:locked|deleted"}, want: []string{"jerry", `a\b`}},
+		{name: "like", filter: []string{"name:like:tom"}, want: []string{"tom", "tommy"}},
+		{name: "like escapes percent and underscore", filter: []string{"name:like:%_"}, want: []string{"100%_off"}},
+		{name: "like escapes backslash", filter: []string{`name:like:\`}, want: []string{`a\b`}},
+		{name: "multiple", filter: []string{"status:active", "age:30"}, want: []string{"100%_off", "tommy"}},
+		{name: "not in whitelist", filter: []string{"id:1"}, wantErr: true},
+		{name: "invalid expression", filter: []string{"name"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var users []testUser
+			err := db.Scopes(Filtering{Filter: tt.filter}.Scope(columns)).Order("id").Find(&users).Error
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, names(users))
+		})
+	}
+}
+
+func TestSorting_Scope(t *testing.T) {
+	db := newTestDB(t, testUsers...)
+	columns := Columns{"name": "name", "userAge": "age"}
+
+	tests := []struct {
+		name    string
+		sort    string
+		want    []string
+		wantErr bool
+	}{
+		{name: "asc", sort: "name", want: []string{"100%_off", `a\b`, "jerry", "tom", "tommy"}},
+		{name: "desc", sort: "-name", want: []string{"tommy", "tom", "jerry", `a\b`, "100%_off"}},
+		{name: "multiple", sort: "-userAge, +name", want: []string{`a\b`, "100%_off", "jerry", "tommy", "tom"}},
+		{name: "column name is not a field", sort: "age", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var users []testUser
+			err := db.Scopes(Sorting{Sort: tt.sort}.Scope(columns)).Find(&users).Error
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, names(users))
+		})
+	}
+}
+
+func TestFindPage(t *testing.T) {
+	db := newTestDB(t, testUsers...)
+	columns := NewColumns("status", "id")
+
+	tests := []struct {
+		name       string
+		pagination Pagination
+		filter     []string
+		wantTotal  int64
+		want       []string
+	}{
+		{name: "first page", pagination: Pagination{Page: 1, Size: 2}, wantTotal: 5, want: []string{"tom", "jerry"}},
+		{name: "last page", pagination: Pagination{Page: 3, Size: 2}, wantTotal: 5, want: []string{"tommy"}},
+		{name: "out of range", pagination: Pagination{Page: 4, Size: 2}, wantTotal: 5, want: []string{}},
+		{name: "filtered", pagination: Pagination{Page: 1, Size: 2}, filter: []string{"status:active"}, wantTotal: 3, want: []string{"tom", "100%_off"}},
+		{name: "no match", pagination: Pagination{Page: 1, Size: 2}, filter: []string{"status:unknown"}, wantTotal: 0, want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := FindPage[testUser](db.Model(&testUser{}), tt.pagination,
+				Filtering{Filter: tt.filter}.Scope(columns), Sorting{Sort: "id"}.Scope(columns))
+			require.NoError(t, err)
+			require.NotNil(t, result.Total)
+			assert.Equal(t, tt.wantTotal, *result.Total)
+			require.NotNil(t, result.Page)
+			assert.Equal(t, tt.pagination.Page, *result.Page)
+			assert.Equal(t, tt.pagination.Size, result.Size)
+			assert.Equal(t, tt.want, names(result.Items))
+		})
+	}
+
+	_, err := FindPage[testUser](db.Model(&testUser{}), Pagination{}, Sorting{Sort: "name"}.Scope(columns))
+	assert.Error(t, err)
+
+	// 没有数据时同样返回总数与页码，与游标分页区分
+	data, err := json.Marshal(NewPageResult[testUser](nil, 0, Pagination{}))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"items":[],"total":0,"page":1,"size":20}`, string(data))
+}
+
+func TestCursorPagination_Scope(t *testing.T) {
+	db := newTestDB(t, testUsers...)
+
+	byAge := func(u testUser) (interface{}, interface{}) { return u.Age, u.ID }
+	byCreatedAt := func(u testUser) (interface{}, interface{}) { return u.CreatedAt, u.ID }
+	byID := func(u testUser) (interface{}, interface{}) { return u.ID, u.ID }
+
+	tests := []struct {
+		name     string
+		column   string
+		cursorOf func(u testUser) (interface{}, interface{})
+		want     []string
+	}{
+		{name: "asc with duplicate values", column: "age", cursorOf: byAge, want: []string{"tom", "jerry", "100%_off", "tommy", `a\b`}},
+		{name: "desc with duplicate values", column: "-age", cursorOf: byAge, want: []string{`a\b`, "tommy", "100%_off", "jerry", "tom"}},
+		{name: "asc with duplicate times", column: "created_at", cursorOf: byCreatedAt, want: []string{"tom", "jerry", "100%_off", "tommy", `a\b`}},
+		{name: "desc with duplicate times", column: "-created_at", cursorOf: byCreatedAt, want: []string{`a\b`, "tommy", "100%_off", "jerry", "tom"}},
+		{name: "primary key", column: "-id", cursorOf: byID, want: []string{"tommy", `a\b`, "100%_off", "jerry", "tom"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := CursorPagination{Size: 2}
+			got := make([]string, 0)
+			for pages := 0; pages < 5; pages++ {
+				var users []testUser
+				require.NoError(t, db.Scopes(p.Scope(tt.column, "id")).Find(&users).Error)
+				result := NewCursorPageResult(users, p, tt.cursorOf)
+				assert.Nil(t, result.Total)
+				assert.Nil(t, result.Page)
+				got = append(got, names(result.Items)...)
+				if result.NextCursor == "" {
+					break
+				}
+				p.Cursor = result.NextCursor
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	var users []testUser
+	err := db.Scopes(CursorPagination{Cursor: "not a cursor"}.Scope("age", "id")).Find(&users).Error
+	assert.Error(t, err)
+}
+
+func TestCursor(t *testing.T) {
+	tests := []struct {
+		name      string
+		value, id interface{}
+		wantValue interface{}
+		wantID    interface{}
+	}{
+		{name: "string", value: "2024-01-01", id: 42, wantValue: "2024-01-01", wantID: int64(42)},
+		{name: "float", value: 1.5, id: "a1", wantValue: 1.5, wantID: "a1"},
+		{name: "time", value: testCreatedAt.In(time.FixedZone("", 8*3600)), id: uint64(7), wantValue: testCreatedAt, wantID: int64(7)},
+		{name: "time pointer", value: &testCreatedAt, id: 7, wantValue: testCreatedAt, wantID: int64(7)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, id, err := DecodeCursor(EncodeCursor(tt.value, tt.id))
+			require.NoError(t, err)
+			if want, ok := tt.wantValue.(time.Time); ok {
+				require.IsType(t, time.Time{}, value)
+				assert.True(t, want.Equal(value.(time.Time)), value)
+			} else {
+				assert.Equal(t, tt.wantValue, value)
+			}
+			assert.Equal(t, tt.wantID, id)
+		})
+	}
+
+	for _, cursor := range []string{"!", EncodeCursor("a", 1)[:4], "WyJhIl0", "eyJ2IjoiYSIsInQiOnRydWUsImlkIjoxfQ"} {
+		_, _, err := DecodeCursor(cursor)
+		assert.Error(t, err, cursor)
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	tests := map[string]string{
+		"tom":  "tom",
+		"100%": `100\%`,
+		"a_b":  `a\_b`,
+		`a\b`:  `a\\b`,
+		`%\_`:  `\%\\\_`,
+		"":     "",
+	}
+	for input, want := range tests {
+		assert.Equal(t, want, escapeLike(input), input)
+	}
+}
@@ -4,6 +4,7 @@ const I18nTemplate = `// Code generated by tools. DO NOT EDIT!
 package {{ .Package }}
 
 import (
+	"fmt"
 	"github.com/shrewx/ginx/pkg/i18nx"
 	"github.com/nicksnyder/go-i18n/v2/i18n"
 )
@@ -54,7 +55,7 @@ func RegisterI18nMessages() {
 	for lang, messages := range messageMap {
 		var i18nMessages []*i18n.Message
 		for key, message := range messages {
-			i18nMessages = append(i18nMessages, &i18n.Message{ID: key.ID(), Other: message})
+			i18nMessages = append(i18nMessages, &i18n.Message{ID: fmt.Sprintf("%s.%s", lang, key.ID()), Other: message})
 		}
 		i18nx.AddMessages(lang, i18nMessages)
 	}
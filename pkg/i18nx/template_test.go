@@ -81,7 +81,8 @@ func TestI18nTemplate_RegisterFunction(t *testing.T) {
 	assert.Contains(t, I18nTemplate, "for lang, messages := range messageMap {")
 	assert.Contains(t, I18nTemplate, "var i18nMessages []*i18n.Message")
 	assert.Contains(t, I18nTemplate, "for key, message := range messages {")
-	assert.Contains(t, I18nTemplate, "i18nMessages = append(i18nMessages, &i18n.Message{ID: key.ID(), Other: message})")
+	// 消息 ID 需包含语言前缀，与 Localize 查找的 ID 一致
+	assert.Contains(t, I18nTemplate, "i18nMessages = append(i18nMessages, &i18n.Message{ID: fmt.Sprintf(\"%s.%s\", lang, key.ID()), Other: message})")
 	assert.Contains(t, I18nTemplate, "i18nx.AddMessages(lang, i18nMessages)")
 }
 
@@ -4,6 +4,7 @@ import (
 	"github.com/natefinch/lumberjack"
 	"github.com/shrewx/ginx/pkg/conf"
 	"github.com/sirupsen/logrus"
+	"io"
 	"os"
 	"path"
 )
@@ -92,3 +93,18 @@ func defaultConfig() *conf.Log {
 		LogLevel: "info",
 	}
 }
+
+// Close 刷新并关闭所有日志输出，服务退出前调用以确保日志落盘
+func Close() error {
+	for _, logger := range logManager.logs {
+		switch out := logger.Out.(type) {
+		case *os.File:
+			_ = out.Sync()
+		case io.Closer:
+			if err := out.Close(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
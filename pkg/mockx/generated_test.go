@@ -0,0 +1,94 @@
+package mockx_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/shrewx/ginx/pkg/client"
+	"github.com/shrewx/ginx/pkg/openapi"
+	"github.com/stretchr/testify/require"
+)
+
+const generatedSpec = `{
+  "openapi": "3.0.3",
+  "info": {"title": "", "version": ""},
+  "paths": {
+    "/users/{id}": {
+      "get": {
+        "operationId": "GetUser",
+        "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "integer", "format": "int64"}}],
+        "responses": {
+          "200": {"description": "", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}},
+          "404": {"description": "", "x-status-errors": ["[UserNotFound][404001]"]}
+        }
+      }
+    },
+    "/ping": {
+      "post": {"operationId": "Ping", "responses": {"204": {"description": ""}}}
+    }
+  },
+  "components": {
+    "schemas": {
+      "User": {"type": "object", "properties": {"id": {"type": "integer", "format": "int64"}, "name": {"type": "string"}}}
+    }
+  }
+}`
+
+const generatedUsage = `package client_user
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestMockClientUser(t *testing.T) {
+	m := NewMockClientUser()
+	m.ExpectGetUser(&User{Name: "tom"}, nil).Once()
+	m.ExpectPing(errors.New("unavailable"))
+
+	var c ClientUser = m
+	user, err := c.GetUser(context.Background(), &GetUser{ID: 1})
+	if err != nil || user.Name != "tom" {
+		t.Fatalf("unexpected result: %v, %v", user, err)
+	}
+	if err := c.Ping(context.Background()); err == nil {
+		t.Fatal("expected error")
+	}
+	if calls := m.GetUserCalls(); len(calls) != 1 || calls[0].ID != 1 {
+		t.Fatalf("unexpected calls: %v", calls)
+	}
+	m.AssertExpectations(t)
+}
+`
+
+// 生成的客户端与 mock 需要能够编译，且 mock 可以替代客户端接口
+func TestGeneratedMock_Compiles(t *testing.T) {
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+
+	// 以 _ 开头的目录不会被 ./... 匹配，但仍处于当前模块中
+	dir, err := os.MkdirTemp(".", "_generated")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	specFile := filepath.Join(dir, "openapi.json")
+	require.NoError(t, os.WriteFile(specFile, []byte(generatedSpec), 0o644))
+	spec, err := openapi.LoadSpec(specFile)
+	require.NoError(t, err)
+
+	abs, err := filepath.Abs(dir)
+	require.NoError(t, err)
+	client.NewClientGeneratorWithOpenAPI("user", spec).Output(abs)
+
+	pkgDir := filepath.Join(dir, "client_user")
+	require.NoError(t, os.WriteFile(filepath.Join(pkgDir, "usage_test.go"), []byte(generatedUsage), 0o644))
+
+	cmd := exec.Command(goBin, "test", "./"+filepath.ToSlash(pkgDir))
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(output))
+}
@@ -0,0 +1,161 @@
+package mockx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/shrewx/ginx"
+)
+
+// ErrUnexpectedCall 调用没有匹配的预期
+var ErrUnexpectedCall = errors.New("mock: unexpected call")
+
+// HandlerFunc 根据请求返回模拟结果
+type HandlerFunc func(ctx context.Context, req interface{}) (interface{}, error)
+
+// Call 调用记录
+type Call struct {
+	Operation string
+	Req       interface{}
+	Opts      []ginx.RequestOption
+}
+
+// Expectation 对某个操作的预期
+type Expectation struct {
+	operation string
+	matcher   func(req interface{}) bool
+	handler   HandlerFunc
+	times     int // 0 表示不限次数
+	calls     int
+}
+
+// Match 只匹配满足条件的请求
+func (e *Expectation) Match(matcher func(req interface{}) bool) *Expectation {
+	e.matcher = matcher
+	return e
+}
+
+// Return 设置固定的返回值
+func (e *Expectation) Return(resp interface{}, err error) *Expectation {
+	e.handler = func(ctx context.Context, req interface{}) (interface{}, error) {
+		return resp, err
+	}
+	return e
+}
+
+// Do 设置处理函数，用于根据请求动态返回结果
+func (e *Expectation) Do(handler HandlerFunc) *Expectation {
+	e.handler = handler
+	return e
+}
+
+// Times 设置预期的调用次数，超过次数后该预期不再匹配
+func (e *Expectation) Times(n int) *Expectation {
+	e.times = n
+	return e
+}
+
+// Once 预期只调用一次
+func (e *Expectation) Once() *Expectation {
+	return e.Times(1)
+}
+
+func (e *Expectation) String() string {
+	if e.times > 0 {
+		return fmt.Sprintf("%s (called %d/%d times)", e.operation, e.calls, e.times)
+	}
+	return fmt.Sprintf("%s (called %d times)", e.operation, e.calls)
+}
+
+// Mock 生成的客户端 mock 的公共实现，记录调用并按注册顺序匹配预期
+type Mock struct {
+	mu           sync.Mutex
+	expectations []*Expectation
+	calls        []Call
+}
+
+// On 为操作添加预期，operation 为生成客户端的方法名
+func (m *Mock) On(operation string) *Expectation {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e := &Expectation{operation: operation}
+	m.expectations = append(m.expectations, e)
+	return e
+}
+
+// Called 记录调用并返回第一个匹配预期的结果，没有匹配的预期时返回 ErrUnexpectedCall
+func (m *Mock) Called(ctx context.Context, operation string, req interface{}, opts []ginx.RequestOption) (interface{}, error) {
+	m.mu.Lock()
+	m.calls = append(m.calls, Call{Operation: operation, Req: req, Opts: opts})
+
+	var matched *Expectation
+	for _, e := range m.expectations {
+		if e.operation != operation || (e.times > 0 && e.calls >= e.times) {
+			continue
+		}
+		if e.matcher != nil && !e.matcher(req) {
+			continue
+		}
+		e.calls++
+		matched = e
+		break
+	}
+	m.mu.Unlock()
+
+	if matched == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnexpectedCall, operation)
+	}
+	if matched.handler == nil {
+		return nil, nil
+	}
+	return matched.handler(ctx, req)
+}
+
+// Calls 返回操作的调用记录，operation 为空时返回全部调用
+func (m *Mock) Calls(operation string) []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	calls := make([]Call, 0, len(m.calls))
+	for _, call := range m.calls {
+		if operation == "" || call.Operation == operation {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Reset 清空预期与调用记录
+func (m *Mock) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.expectations = nil
+	m.calls = nil
+}
+
+// TestingT testing.T 的子集
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// AssertExpectations 检查所有预期是否都按次数被调用
+func (m *Mock) AssertExpectations(t TestingT) bool {
+	t.Helper()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ok := true
+	for _, e := range m.expectations {
+		if (e.times == 0 && e.calls == 0) || e.calls < e.times {
+			t.Errorf("mock: expectation not met: %s", e)
+			ok = false
+		}
+	}
+	return ok
+}
@@ -0,0 +1,68 @@
+package mockx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type getUserReq struct {
+	ID int
+}
+
+type recordT struct {
+	errors []string
+}
+
+func (t *recordT) Helper() {}
+
+func (t *recordT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestMock_Expectations(t *testing.T) {
+	m := &Mock{}
+	m.On("GetUser").Match(func(req interface{}) bool {
+		return req.(*getUserReq).ID == 1
+	}).Return("tom", nil).Once()
+	m.On("GetUser").Do(func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, fmt.Errorf("user %d not found", req.(*getUserReq).ID)
+	})
+
+	resp, err := m.Called(context.Background(), "GetUser", &getUserReq{ID: 1}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "tom", resp)
+
+	// 超过次数后匹配下一个预期
+	_, err = m.Called(context.Background(), "GetUser", &getUserReq{ID: 1}, nil)
+	assert.EqualError(t, err, "user 1 not found")
+
+	_, err = m.Called(context.Background(), "DeleteUser", &getUserReq{ID: 1}, nil)
+	assert.True(t, errors.Is(err, ErrUnexpectedCall))
+
+	assert.Len(t, m.Calls("GetUser"), 2)
+	assert.Len(t, m.Calls(""), 3)
+	assert.True(t, m.AssertExpectations(t))
+}
+
+func TestMock_AssertExpectations(t *testing.T) {
+	m := &Mock{}
+	m.On("GetUser").Return(nil, nil).Times(2)
+	m.On("Ping")
+
+	_, _ = m.Called(context.Background(), "GetUser", nil, nil)
+
+	rt := &recordT{}
+	assert.False(t, m.AssertExpectations(rt))
+	assert.Equal(t, []string{
+		"mock: expectation not met: GetUser (called 1/2 times)",
+		"mock: expectation not met: Ping (called 0 times)",
+	}, rt.errors)
+
+	m.Reset()
+	assert.True(t, m.AssertExpectations(t))
+	assert.Empty(t, m.Calls(""))
+}
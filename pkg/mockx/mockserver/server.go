@@ -0,0 +1,447 @@
+package mockserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-courier/oas"
+	"github.com/shrewx/ginx"
+	"github.com/shrewx/ginx/pkg/openapi"
+)
+
+const (
+	// HeaderMockStatus 指定返回的响应状态码，需在文档中声明
+	HeaderMockStatus = "X-Mock-Status"
+	// HeaderMockError 指定返回的状态错误，值为错误的 key 或 code，需在文档中声明
+	HeaderMockError = "X-Mock-Error"
+)
+
+var reStatusErrorSummary = regexp.MustCompile(`^\[([^\]]+)\]\[(\d+)\]`)
+
+// StatusError 文档中声明的状态错误
+type StatusError struct {
+	Key        string `json:"key"`
+	Code       int64  `json:"code"`
+	Message    string `json:"message"`
+	StatusCode int    `json:"-"`
+	// Messages 文档中声明的多语言消息
+	Messages map[string]string `json:"-"`
+}
+
+// localize 返回指定语言的错误，未声明该语言时使用默认语言的消息
+func (e *StatusError) localize(lang string) *StatusError {
+	if message, ok := e.Messages[strings.ToLower(lang)]; ok {
+		localized := *e
+		localized.Message = message
+		return &localized
+	}
+	return e
+}
+
+type route struct {
+	method    string
+	segments  []string
+	operation *oas.Operation
+}
+
+// match 匹配请求路径，{name} 形式的段匹配任意值
+func (r *route) match(method string, segments []string) bool {
+	if r.method != method || len(r.segments) != len(segments) {
+		return false
+	}
+	for i, s := range r.segments {
+		if !isPathParam(s) && s != segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// literals 固定段的数量，用于优先匹配更具体的路径
+func (r *route) literals() int {
+	n := 0
+	for _, s := range r.segments {
+		if !isPathParam(s) {
+			n++
+		}
+	}
+	return n
+}
+
+func isPathParam(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+func splitPath(p string) []string {
+	return strings.Split(strings.Trim(p, "/"), "/")
+}
+
+// Server 根据 OpenAPI 文档返回模拟响应的 HTTP 服务
+// 默认返回文档中的 2xx 响应，响应体优先使用 example，否则根据 schema 生成；
+// 请求头 X-Mock-Error 可指定返回声明的状态错误，X-Mock-Status 可指定返回声明的状态码
+type Server struct {
+	openapi *oas.OpenAPI
+	routes  []*route
+}
+
+// NewServer 创建 mock 服务
+func NewServer(spec *oas.OpenAPI) *Server {
+	s := &Server{openapi: spec}
+	for p, item := range spec.Paths.Paths {
+		for method, op := range item.Operations.Operations {
+			s.routes = append(s.routes, &route{
+				method:    strings.ToUpper(string(method)),
+				segments:  splitPath(p),
+				operation: op,
+			})
+		}
+	}
+	sort.SliceStable(s.routes, func(i, j int) bool {
+		return s.routes[i].literals() > s.routes[j].literals()
+	})
+	return s
+}
+
+// NewTestServer 启动 httptest 服务，测试结束后需调用 Close
+func NewTestServer(spec *oas.OpenAPI) *httptest.Server {
+	return httptest.NewServer(NewServer(spec))
+}
+
+// LoadOpenAPI 从文件路径或 http(s) 地址加载 JSON 或 YAML 格式的 OpenAPI 文档
+func LoadOpenAPI(location string) (*oas.OpenAPI, error) {
+	return openapi.LoadSpec(location)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	segments := splitPath(r.URL.Path)
+	for _, rt := range s.routes {
+		if rt.match(r.Method, segments) {
+			s.serveOperation(w, r, rt.operation)
+			return
+		}
+	}
+	writeJSON(w, http.StatusNotFound, map[string]string{
+		"message": fmt.Sprintf("no operation for %s %s", r.Method, r.URL.Path),
+	})
+}
+
+func (s *Server) serveOperation(w http.ResponseWriter, r *http.Request, op *oas.Operation) {
+	if v := r.Header.Get(HeaderMockError); v != "" {
+		for _, statusErr := range StatusErrors(op) {
+			if statusErr.Key == v || strconv.FormatInt(statusErr.Code, 10) == v {
+				writeJSON(w, statusErr.StatusCode, statusErr.localize(r.Header.Get(ginx.CurrentLangHeader())))
+				return
+			}
+		}
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"message": fmt.Sprintf("status error %s is not declared by %s", v, op.OperationId),
+		})
+		return
+	}
+
+	code := successCode(op)
+	if v := r.Header.Get(HeaderMockStatus); v != "" {
+		c, err := strconv.Atoi(v)
+		if err != nil || op.Responses.Responses[c] == nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{
+				"message": fmt.Sprintf("status %s is not declared by %s", v, op.OperationId),
+			})
+			return
+		}
+		code = c
+	}
+
+	// 指定的状态码为错误响应时返回该状态码下声明的第一个状态错误
+	if code >= http.StatusBadRequest {
+		for _, statusErr := range StatusErrors(op) {
+			if statusErr.StatusCode == code {
+				writeJSON(w, code, statusErr.localize(r.Header.Get(ginx.CurrentLangHeader())))
+				return
+			}
+		}
+	}
+
+	resp := op.Responses.Responses[code]
+	if resp == nil || len(resp.Content) == 0 {
+		w.WriteHeader(code)
+		return
+	}
+
+	contentType, mediaType := firstMediaType(resp.Content)
+	body := s.Example(mediaType)
+	if !strings.Contains(contentType, "json") {
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(code)
+		if body != nil {
+			_, _ = fmt.Fprint(w, body)
+		}
+		return
+	}
+	writeJSON(w, code, body)
+}
+
+// Example 返回媒体类型的示例数据，未声明 example 时根据 schema 生成
+func (s *Server) Example(mediaType *oas.MediaType) interface{} {
+	if mediaType == nil {
+		return nil
+	}
+	if mediaType.Example != nil {
+		return mediaType.Example
+	}
+	for _, name := range sortedKeys(mediaType.Examples) {
+		if example := mediaType.Examples[name]; example != nil && example.Value != nil {
+			return example.Value
+		}
+	}
+	return s.Generate(mediaType.Schema)
+}
+
+// Generate 根据 schema 生成示例数据，结果是确定的
+func (s *Server) Generate(schema *oas.Schema) interface{} {
+	return s.generate(schema, map[string]bool{})
+}
+
+func (s *Server) generate(schema *oas.Schema, visiting map[string]bool) interface{} {
+	if schema == nil {
+		return nil
+	}
+
+	if schema.Refer != nil {
+		id := refID(schema.Refer)
+		target := s.openapi.Components.Schemas[id]
+		// 递归引用时返回空值
+		if target == nil || visiting[id] {
+			return nil
+		}
+		visiting[id] = true
+		defer delete(visiting, id)
+		return s.generate(target, visiting)
+	}
+
+	switch {
+	case schema.Example != nil:
+		return schema.Example
+	case schema.Default != nil:
+		return schema.Default
+	case len(schema.Enum) > 0:
+		return schema.Enum[0]
+	case len(schema.AllOf) > 0:
+		merged := map[string]interface{}{}
+		for _, sub := range schema.AllOf {
+			if m, ok := s.generate(sub, visiting).(map[string]interface{}); ok {
+				for k, v := range m {
+					merged[k] = v
+				}
+			}
+		}
+		for k, v := range s.generateProperties(schema, visiting) {
+			merged[k] = v
+		}
+		return merged
+	case len(schema.OneOf) > 0:
+		return s.generate(schema.OneOf[0], visiting)
+	case len(schema.AnyOf) > 0:
+		return s.generate(schema.AnyOf[0], visiting)
+	}
+
+	switch schema.Type {
+	case oas.TypeObject, "":
+		if schema.Type == "" && len(schema.Properties) == 0 && schema.AdditionalProperties == nil {
+			return nil
+		}
+		obj := s.generateProperties(schema, visiting)
+		if len(schema.Properties) == 0 && schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil {
+			obj["key"] = s.generate(schema.AdditionalProperties.Schema, visiting)
+		}
+		return obj
+	case oas.TypeArray:
+		n := 1
+		if schema.MinItems != nil && *schema.MinItems > 1 {
+			n = int(*schema.MinItems)
+		}
+		items := make([]interface{}, 0, n)
+		if item := s.generate(schema.Items, visiting); item != nil {
+			for i := 0; i < n; i++ {
+				items = append(items, item)
+			}
+		}
+		return items
+	case oas.TypeString:
+		return exampleString(schema)
+	case oas.TypeInteger:
+		if schema.Minimum != nil {
+			return int64(*schema.Minimum)
+		}
+		return 0
+	case oas.TypeNumber:
+		if schema.Minimum != nil {
+			return *schema.Minimum
+		}
+		return 0.0
+	case oas.TypeBoolean:
+		return true
+	}
+	return nil
+}
+
+func (s *Server) generateProperties(schema *oas.Schema, visiting map[string]bool) map[string]interface{} {
+	obj := make(map[string]interface{}, len(schema.Properties))
+	for name, prop := range schema.Properties {
+		obj[name] = s.generate(prop, visiting)
+	}
+	return obj
+}
+
+func exampleString(schema *oas.Schema) string {
+	switch schema.Format {
+	case "date-time":
+		return "2006-01-02T15:04:05Z"
+	case "date":
+		return "2006-01-02"
+	case "time":
+		return "15:04:05"
+	case "uuid":
+		return "00000000-0000-0000-0000-000000000000"
+	case "email":
+		return "user@example.com"
+	case "uri", "url":
+		return "https://example.com"
+	case "ipv4":
+		return "127.0.0.1"
+	case "ipv6":
+		return "::1"
+	case "binary", "byte":
+		return ""
+	}
+	if schema.MinLength != nil && *schema.MinLength > uint64(len("string")) {
+		return strings.Repeat("s", int(*schema.MinLength))
+	}
+	return "string"
+}
+
+// StatusErrors 返回操作声明的状态错误，按 key 排序
+// Message 为文档中声明的中文消息，未声明中文时取第一个语言的消息，均未声明时为 key
+func StatusErrors(op *oas.Operation) []*StatusError {
+	statusErrors := make([]*StatusError, 0)
+	for code, resp := range op.Responses.Responses {
+		if resp == nil || resp.Extensions == nil {
+			continue
+		}
+		messages := summaryMessages(resp.Extensions[openapi.XStatusErrMessages])
+		var summaries []string
+		switch list := resp.Extensions[openapi.XStatusErrs].(type) {
+		case []string:
+			summaries = list
+		case []interface{}:
+			for _, item := range list {
+				if summary, ok := item.(string); ok {
+					summaries = append(summaries, summary)
+				}
+			}
+		}
+		for _, summary := range summaries {
+			matched := reStatusErrorSummary.FindStringSubmatch(summary)
+			if matched == nil {
+				continue
+			}
+			errCode, _ := strconv.ParseInt(matched[2], 10, 64)
+			statusErrors = append(statusErrors, &StatusError{
+				Key:        matched[1],
+				Code:       errCode,
+				Message:    defaultMessage(matched[1], messages[summary]),
+				StatusCode: code,
+				Messages:   messages[summary],
+			})
+		}
+	}
+	sort.Slice(statusErrors, func(i, j int) bool {
+		return statusErrors[i].Key < statusErrors[j].Key
+	})
+	return statusErrors
+}
+
+// summaryMessages 解析 x-status-error-messages，兼容扫描结果与从文件加载的文档
+func summaryMessages(v interface{}) map[string]map[string]string {
+	messages := make(map[string]map[string]string)
+	switch m := v.(type) {
+	case map[string]map[string]string:
+		return m
+	case map[string]interface{}:
+		for summary, langs := range m {
+			if langMessages, ok := langs.(map[string]interface{}); ok {
+				messages[summary] = make(map[string]string, len(langMessages))
+				for lang, message := range langMessages {
+					messages[summary][lang] = fmt.Sprint(message)
+				}
+			}
+		}
+	}
+	return messages
+}
+
+func defaultMessage(key string, messages map[string]string) string {
+	if message, ok := messages[ginx.I18nZH]; ok {
+		return message
+	}
+	if keys := sortedKeys(messages); len(keys) > 0 {
+		return messages[keys[0]]
+	}
+	return key
+}
+
+// successCode 返回最小的 2xx 状态码，未声明时返回 200
+func successCode(op *oas.Operation) int {
+	code := 0
+	for c := range op.Responses.Responses {
+		if c >= 200 && c < 300 && (code == 0 || c < code) {
+			code = c
+		}
+	}
+	if code == 0 {
+		return http.StatusOK
+	}
+	return code
+}
+
+func firstMediaType(content map[string]*oas.MediaType) (string, *oas.MediaType) {
+	keys := sortedKeys(content)
+	for _, k := range keys {
+		if strings.Contains(k, "json") {
+			return k, content[k]
+		}
+	}
+	return keys[0], content[keys[0]]
+}
+
+func refID(refer oas.Refer) string {
+	switch ref := refer.(type) {
+	case *oas.ComponentRefer:
+		return ref.ID
+	case oas.ComponentRefer:
+		return ref.ID
+	}
+	return refer.RefString()[strings.LastIndex(refer.RefString(), "/")+1:]
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(code)
+	if v != nil {
+		_ = json.NewEncoder(w).Encode(v)
+	}
+}
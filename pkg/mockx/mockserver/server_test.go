@@ -0,0 +1,204 @@
+package mockserver
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/shrewx/ginx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSpec = `{
+  "openapi": "3.0.3",
+  "info": {"title": "", "version": ""},
+  "paths": {
+    "/users/{id}": {
+      "get": {
+        "operationId": "GetUser",
+        "responses": {
+          "200": {"description": "", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}},
+          "404": {"description": "", "x-status-errors": ["[UserNotFound][404001]"], "x-status-error-messages": {"[UserNotFound][404001]": {"zh": "用户不存在", "en": "user not found"}}},
+          "409": {"description": "", "x-status-errors": ["[UserConflict][409001]", "[UserLocked][409002]"]}
+        }
+      }
+    },
+    "/users/me": {
+      "get": {
+        "operationId": "GetMe",
+        "responses": {
+          "200": {"description": "", "content": {"application/json": {"example": {"id": 7}}}}
+        }
+      }
+    },
+    "/ping": {
+      "post": {
+        "operationId": "Ping",
+        "responses": {"204": {"description": ""}}
+      }
+    },
+    "/version": {
+      "get": {
+        "operationId": "Version",
+        "responses": {"200": {"description": "", "content": {"text/plain": {"schema": {"type": "string", "example": "v1.0.0"}}}}}
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "Role": {"type": "string", "enum": ["ADMIN", "GUEST"]},
+      "User": {
+        "allOf": [
+          {"$ref": "#/components/schemas/Base"},
+          {
+            "type": "object",
+            "properties": {
+              "name": {"type": "string", "example": "tom"},
+              "email": {"type": "string", "format": "email"},
+              "role": {"$ref": "#/components/schemas/Role"},
+              "tags": {"type": "array", "items": {"type": "string"}},
+              "friends": {"type": "array", "items": {"$ref": "#/components/schemas/User"}},
+              "attrs": {"type": "object", "additionalProperties": {"type": "integer"}}
+            }
+          }
+        ]
+      },
+      "Base": {
+        "type": "object",
+        "properties": {
+          "id": {"type": "integer", "format": "uint64", "minimum": 1},
+          "createdAt": {"type": "string", "format": "date-time"},
+          "active": {"type": "boolean", "default": false}
+        }
+      }
+    }
+  }
+}`
+
+func newTestSpecServer(t *testing.T) string {
+	path := t.TempDir() + "/openapi.json"
+	require.NoError(t, os.WriteFile(path, []byte(testSpec), 0644))
+	spec, err := LoadOpenAPI(path)
+	require.NoError(t, err)
+
+	server := NewTestServer(spec)
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func doRequest(t *testing.T, method, url string, header map[string]string) (*http.Response, string) {
+	req, err := http.NewRequest(method, url, nil)
+	require.NoError(t, err)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(data)
+}
+
+func TestServer_GenerateFromSchema(t *testing.T) {
+	url := newTestSpecServer(t)
+
+	resp, body := doRequest(t, http.MethodGet, url+"/users/1", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.JSONEq(t, `{
+		"id": 1,
+		"createdAt": "2006-01-02T15:04:05Z",
+		"active": false,
+		"name": "tom",
+		"email": "user@example.com",
+		"role": "ADMIN",
+		"tags": ["string"],
+		"friends": [],
+		"attrs": {"key": 0}
+	}`, body)
+
+	// 固定路径优先于路径参数
+	_, body = doRequest(t, http.MethodGet, url+"/users/me", nil)
+	assert.JSONEq(t, `{"id": 7}`, body)
+
+	resp, body = doRequest(t, http.MethodGet, url+"/version", nil)
+	assert.Equal(t, "text/plain", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "v1.0.0", body)
+
+	resp, body = doRequest(t, http.MethodPost, url+"/ping", nil)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Empty(t, body)
+
+	resp, _ = doRequest(t, http.MethodDelete, url+"/ping", nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestServer_StatusErrors(t *testing.T) {
+	url := newTestSpecServer(t)
+
+	resp, body := doRequest(t, http.MethodGet, url+"/users/1", map[string]string{HeaderMockError: "UserNotFound"})
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.JSONEq(t, `{"key": "UserNotFound", "code": 404001, "message": "用户不存在"}`, body)
+
+	resp, body = doRequest(t, http.MethodGet, url+"/users/1", map[string]string{HeaderMockError: "UserNotFound", ginx.LangHeader: "en"})
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.JSONEq(t, `{"key": "UserNotFound", "code": 404001, "message": "user not found"}`, body)
+
+	resp, body = doRequest(t, http.MethodGet, url+"/users/1", map[string]string{HeaderMockError: "409002"})
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	var statusErr StatusError
+	require.NoError(t, json.Unmarshal([]byte(body), &statusErr))
+	assert.Equal(t, "UserLocked", statusErr.Key)
+
+	resp, body = doRequest(t, http.MethodGet, url+"/users/1", map[string]string{HeaderMockStatus: "409"})
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Contains(t, body, "UserConflict")
+
+	// 未声明的错误与状态码
+	resp, _ = doRequest(t, http.MethodGet, url+"/users/1", map[string]string{HeaderMockError: "Unknown"})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp, _ = doRequest(t, http.MethodGet, url+"/users/1", map[string]string{HeaderMockStatus: "500"})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestLoadOpenAPI_YAML(t *testing.T) {
+	path := t.TempDir() + "/openapi.yaml"
+	require.NoError(t, os.WriteFile(path, []byte(`
+openapi: 3.0.3
+info:
+  title: ""
+  version: ""
+paths:
+  /ping:
+    get:
+      operationId: Ping
+      responses:
+        200:
+          description: ""
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  pong:
+                    type: boolean
+        500:
+          description: ""
+          x-status-errors:
+            - "[PingFailed][500001]"
+`), 0644))
+
+	spec, err := LoadOpenAPI(path)
+	require.NoError(t, err)
+	server := NewTestServer(spec)
+	defer server.Close()
+
+	_, body := doRequest(t, http.MethodGet, server.URL+"/ping", nil)
+	assert.JSONEq(t, `{"pong": true}`, body)
+
+	resp, _ := doRequest(t, http.MethodGet, server.URL+"/ping", map[string]string{HeaderMockError: "PingFailed"})
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+}
@@ -22,8 +22,9 @@ import (
 
 func NewDefinitionScanner(pkg *packagesx.Package) *DefinitionScanner {
 	definitionScanner := &DefinitionScanner{
-		pkg:   pkg,
-		enums: make(map[*packagesx.Package]*enum.EnumScanner, 0),
+		pkg:       pkg,
+		enums:     make(map[*packagesx.Package]*enum.EnumScanner, 0),
+		instances: make(map[string]*types.TypeName, 0),
 	}
 	writer := packagesx.NewPackage(pkg.Pkg("io")).TypeName("Writer")
 	if writer != nil {
@@ -37,6 +38,7 @@ type DefinitionScanner struct {
 	pkg               *packagesx.Package
 	enums             map[*packagesx.Package]*enum.EnumScanner
 	definitions       map[*types.TypeName]*oas.Schema
+	instances         map[string]*types.TypeName
 	schemas           map[string]*oas.Schema
 	ioWriterInterface *types.Interface
 }
@@ -75,6 +77,10 @@ func setMetaFromDoc(s *oas.Schema, doc string) {
 }
 
 func fullTypeName(typeName *types.TypeName) string {
+	// 泛型实例使用实例化后的完整类型名，如 pkg.PageResult[pkg.User]
+	if named, ok := typeName.Type().(*types.Named); ok && named.TypeArgs().Len() > 0 {
+		return named.String()
+	}
 	pkg := typeName.Pkg()
 	if pkg != nil {
 		return pkg.Path() + "." + typeName.Name()
@@ -93,7 +99,8 @@ func (scanner *DefinitionScanner) Def(ctx context.Context, typeName *types.TypeN
 
 	logrus.Debugf("scanning Type `%s.%s`", typeName.Pkg().Path(), typeName.Name())
 
-	if typeName.IsAlias() {
+	// 泛型实例的类型名由 instanceTypeName 创建，不按别名处理
+	if typeName.IsAlias() && typeName.Type().(*types.Named).TypeArgs().Len() == 0 {
 		typeName = typeName.Type().(*types.Named).Obj()
 	}
 
@@ -200,6 +207,46 @@ func (scanner *DefinitionScanner) Def(ctx context.Context, typeName *types.TypeN
 	return scanner.setDef(typeName, s)
 }
 
+// instanceTypeName 为泛型类型的实例创建类型名，名称由泛型类型名与类型参数名拼接，如 PageResult[User] 为 PageResultUser
+func (scanner *DefinitionScanner) instanceTypeName(named *types.Named) *types.TypeName {
+	key := named.String()
+	if typeName, ok := scanner.instances[key]; ok {
+		return typeName
+	}
+
+	obj := named.Obj()
+	name := obj.Name()
+	for i := 0; i < named.TypeArgs().Len(); i++ {
+		name += typeArgName(named.TypeArgs().At(i))
+	}
+
+	typeName := types.NewTypeName(obj.Pos(), obj.Pkg(), name, named)
+	scanner.instances[key] = typeName
+	return typeName
+}
+
+func typeArgName(typ types.Type) string {
+	switch t := typ.(type) {
+	case *types.Named:
+		name := t.Obj().Name()
+		for i := 0; i < t.TypeArgs().Len(); i++ {
+			name += typeArgName(t.TypeArgs().At(i))
+		}
+		return name
+	case *types.Basic:
+		return codegen.UpperCamelCase(t.Name())
+	case *types.Pointer:
+		return typeArgName(t.Elem())
+	case *types.Slice:
+		return typeArgName(t.Elem()) + "List"
+	case *types.Array:
+		return typeArgName(t.Elem()) + "List"
+	case *types.Map:
+		return typeArgName(t.Elem()) + "Map"
+	}
+	return "Any"
+}
+
 func (scanner *DefinitionScanner) isInternal(typeName *types.TypeName) bool {
 	return strings.HasPrefix(typeName.Pkg().Path(), scanner.pkg.PkgPath)
 }
@@ -282,10 +329,22 @@ func (r SchemaRefer) RefString() string {
 func (scanner *DefinitionScanner) GetSchemaByType(ctx context.Context, typ types.Type) *oas.Schema {
 	switch t := typ.(type) {
 	case *types.Named:
-		if t.String() == "mime/multipart.FileHeader" {
+		if t.String() == "mime/multipart.FileHeader" || t.String() == "github.com/shrewx/ginx.MultipartStream" {
 			return oas.Binary()
 		}
+		if t.String() == "encoding/json.RawMessage" {
+			return &oas.Schema{}
+		}
+		if t.TypeArgs().Len() > 0 {
+			return oas.RefSchemaByRefer(NewSchemaRefer(scanner.Def(ctx, scanner.instanceTypeName(t))))
+		}
 		return oas.RefSchemaByRefer(NewSchemaRefer(scanner.Def(ctx, t.Obj())))
+	case *types.Alias:
+		// 任意 JSON 值，部分 Go 版本中为类型别名
+		if t.String() == "encoding/json.RawMessage" {
+			return &oas.Schema{}
+		}
+		return scanner.GetSchemaByType(ctx, types.Unalias(t))
 	case *types.Interface:
 		return &oas.Schema{}
 	case *types.Basic:
@@ -378,7 +437,8 @@ func (scanner *DefinitionScanner) GetSchemaByType(ctx context.Context, typ types
 				required = true
 			}
 
-			ident := scanner.pkg.IdentOf(field)
+			// 泛型实例化后的字段与声明中的字段不是同一对象，需通过 Origin 查找注释
+			ident := scanner.pkg.IdentOf(field.Origin())
 			if ident == nil {
 				logrus.Error("ident is nil, maybe response body is not declared structure")
 			}
@@ -439,6 +499,7 @@ func (scanner *DefinitionScanner) propSchemaByField(
 	}
 
 	setMetaFromDoc(propSchema, desc)
+	setValidateConstraints(propSchema, tags.Get("validate"))
 
 	// 保存字段名和所有 tag 信息到扩展中，以便客户端生成时能还原原始结构体
 	addExtension(propSchema, XGoFieldName, fieldName)
@@ -30,6 +30,12 @@ func NewOpenAPIGenerator(pkg *packagesx.Package) *OpenAPIGenerator {
 		routerScanner: NewRouterScanner(pkg),
 	}
 }
+
+// OpenAPI 返回扫描得到的文档
+func (g *OpenAPIGenerator) OpenAPI() *oas.OpenAPI {
+	return g.openapi
+}
+
 func (g *OpenAPIGenerator) SetServer(url string) {
 	g.openapi.Servers = append(g.openapi.Servers, oas.NewServer(url))
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/go-courier/reflectx/typesutil"
 	"github.com/pkg/errors"
 	"github.com/shrewx/ginx"
+	e2 "github.com/shrewx/ginx/internal/errors"
 	"github.com/shrewx/ginx/pkg/statuserror"
 	"github.com/sirupsen/logrus"
 	"go/ast"
@@ -76,6 +77,8 @@ func (scanner *OperatorScanner) Operator(ctx context.Context, typeName *types.Ty
 
 		scanner.scanParameterOrRequestBody(ctx, operator, typeStruct)
 
+		scanner.scanConsumes(operator, typeName)
+
 		scanner.scanReturns(ctx, operator, typeName)
 
 		if scanner.operators == nil {
@@ -119,6 +122,66 @@ func (scanner *OperatorScanner) singleReturnOf(typeName *types.TypeName, name st
 	return "", false
 }
 
+// stringsReturnOf 方法返回的字符串切片，只支持直接返回切片字面量，元素为常量
+func (scanner *OperatorScanner) stringsReturnOf(typeName *types.TypeName, name string) []string {
+	for _, typ := range []types.Type{
+		typeName.Type(),
+		types.NewPointer(typeName.Type()),
+	} {
+		method, ok := typesutil.FromTType(typ).MethodByName(name)
+		if !ok {
+			continue
+		}
+		results, n := scanner.pkg.FuncResultsOf(method.(*typesutil.TMethod).Func)
+		if n != 1 {
+			continue
+		}
+		for _, v := range results[0] {
+			compositeLit, ok := v.Expr.(*ast.CompositeLit)
+			if !ok {
+				continue
+			}
+			var values []string
+			for _, elt := range compositeLit.Elts {
+				tv, err := scanner.pkg.Eval(elt)
+				if err != nil || tv.Value == nil || tv.Value.Kind() != constant.String {
+					logrus.Warnf("%s of %s should return constant strings", name, typeName.Name())
+					return nil
+				}
+				values = append(values, constant.StringVal(tv.Value))
+			}
+			return values
+		}
+	}
+	return nil
+}
+
+// scanConsumes 操作符通过 Consumes 声明请求体类型时，按声明的类型生成 requestBody.content
+func (scanner *OperatorScanner) scanConsumes(op *Operator, typeName *types.TypeName) {
+	mediaTypes := scanner.stringsReturnOf(typeName, "Consumes")
+	if len(mediaTypes) == 0 {
+		return
+	}
+
+	// body 或表单参数只生成一种类型的请求体，按声明的类型替换
+	var mediaType *oas.MediaType
+	if op.RequestBody != nil {
+		for _, m := range op.RequestBody.Content {
+			mediaType = m
+		}
+	}
+	if mediaType == nil {
+		// 没有 body 参数时请求体为任意内容
+		mediaType = oas.NewMediaTypeWithSchema(oas.Binary())
+	}
+
+	reqBody := oas.NewRequestBody("", true)
+	for _, contentType := range mediaTypes {
+		reqBody.AddContent(contentType, mediaType)
+	}
+	op.SetRequestBody(reqBody)
+}
+
 func (scanner *OperatorScanner) tagFrom(pkgPath string) string {
 	tag := strings.TrimPrefix(pkgPath, scanner.pkg.PkgPath)
 	return strings.TrimPrefix(tag, "/")
@@ -216,6 +279,9 @@ func (scanner *OperatorScanner) scanReturns(ctx context.Context, op *Operator, t
 				// 回退到默认的 StatusErr
 				schema := scanner.DefinitionScanner.GetSchemaByType(ctx, scanner.StatusErrScanner.StatusErrType)
 				op.StatusErrorSchema = schema
+				if scanner.StatusErrScanner.ValidationErrType != nil {
+					op.ValidationErrorSchema = scanner.DefinitionScanner.GetSchemaByType(ctx, scanner.StatusErrScanner.ValidationErrType)
+				}
 			}
 		}
 	}
@@ -554,14 +620,21 @@ func (scanner *OperatorScanner) scanParameterOrRequestBody(ctx context.Context,
 	var needScanForm bool
 	typesutil.EachField(typesutil.FromTType(typeStruct), "name", func(field typesutil.StructField, fieldDisplayName string, omitempty bool) bool {
 
-		location, _ := tagValueAndFlagsByTagString(field.Tag().Get("in"))
+		// 多个来源以逗号分隔，第一个来源优先
+		sources := strings.Split(field.Tag().Get("in"), ",")
+		location := strings.TrimSpace(sources[0])
 
 		if location == "" {
+			// 没有 in 标签的嵌套结构体为参数组，展开其中的参数
+			if group, ok := parameterGroup(field.Type().(*typesutil.TType).Type); ok {
+				scanner.scanParameterOrRequestBody(ctx, op, group)
+				return true
+			}
 			logrus.Warnf("missing tag `in` for %s of %s", field.Name(), op.ID)
 			return true
 		}
 
-		if location == "urlencoded" || location == "form" {
+		if location == "urlencoded" || location == "form" || location == "multipart" {
 			needScanForm = true
 			return true
 		}
@@ -576,29 +649,41 @@ func (scanner *OperatorScanner) scanParameterOrRequestBody(ctx context.Context,
 			name, flags = tagValueAndFlagsByTagString(field.Tag().Get("json"))
 		}
 
+		fieldType, contentType := field.Type().(*typesutil.TType).Type, ""
+		if location == "body" {
+			fieldType, contentType = requestBodyType(fieldType)
+		}
+
 		schema := scanner.DefinitionScanner.propSchemaByField(
 			ctx,
 			field.Name(),
-			field.Type().(*typesutil.TType).Type,
+			fieldType,
 			field.Tag(),
 			name,
 			flags,
 			scanner.pkg.CommentsOf(scanner.pkg.IdentOf(field.(*typesutil.TStructField).Var)),
 		)
 
+		if len(sources) > 1 {
+			// 任一来源传入即可，每个来源作为可选的参数，按优先级记录所有来源
+			for i := range sources {
+				sources[i] = strings.TrimSpace(sources[i])
+			}
+			for _, in := range sources {
+				if parameter := nonBodyParameter(in, fieldDisplayName, schema, false); parameter != nil {
+					parameter.AddExtension(XParamSources, sources)
+					op.AddNonBodyParameter(parameter)
+				}
+			}
+			return true
+		}
+
 		switch location {
-		case "query":
-			op.AddNonBodyParameter(oas.QueryParameter(fieldDisplayName, schema, !omitempty))
-		case "path":
-			op.AddNonBodyParameter(oas.PathParameter(fieldDisplayName, schema))
-		case "cookie":
-			op.AddNonBodyParameter(oas.CookieParameter(fieldDisplayName, schema, !omitempty))
-		case "header":
-			// https://swagger.io/docs/specification/authentication/basic-authentication/
-			op.AddNonBodyParameter(oas.HeaderParameter(fieldDisplayName, schema, false))
+		case "query", "path", "cookie", "cookies", "header":
+			op.AddNonBodyParameter(nonBodyParameter(location, fieldDisplayName, schema, !omitempty))
 		case "body":
 			reqBody := oas.NewRequestBody("", true)
-			reqBody.AddContent("application/json", oas.NewMediaTypeWithSchema(schema))
+			reqBody.AddContent(contentType, oas.NewMediaTypeWithSchema(schema))
 			op.SetRequestBody(reqBody)
 		}
 
@@ -610,9 +695,63 @@ func (scanner *OperatorScanner) scanParameterOrRequestBody(ctx context.Context,
 	}
 }
 
+// requestBodyType 请求体的类型与媒体类型，Merge Patch 的请求体为其中的值
+func requestBodyType(typ types.Type) (types.Type, string) {
+	if named, ok := typ.(*types.Named); ok && named.Obj().Pkg() != nil {
+		switch named.Obj().Pkg().Path() + "." + named.Obj().Name() {
+		case "github.com/shrewx/ginx.MergePatch":
+			return named.TypeArgs().At(0), "application/merge-patch+json"
+		case "github.com/shrewx/ginx.JSONPatch":
+			return typ, "application/json-patch+json"
+		}
+	}
+	return typ, "application/json"
+}
+
+// parameterGroup 判断字段类型是否为参数组，即包含 in 标签字段的结构体或结构体指针
+func parameterGroup(typ types.Type) (*types.Struct, bool) {
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+	s, ok := typ.Underlying().(*types.Struct)
+	if !ok {
+		return nil, false
+	}
+	for i := 0; i < s.NumFields(); i++ {
+		if _, ok := reflect.StructTag(s.Tag(i)).Lookup("in"); ok {
+			return s, true
+		}
+		// 嵌入的参数组
+		if s.Field(i).Anonymous() {
+			if _, ok := parameterGroup(s.Field(i).Type()); ok {
+				return s, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// nonBodyParameter 创建请求体之外的参数
+func nonBodyParameter(in string, name string, schema *oas.Schema, required bool) *oas.Parameter {
+	switch in {
+	case "query":
+		return oas.QueryParameter(name, schema, required)
+	case "path":
+		return oas.PathParameter(name, schema)
+	case "cookie", "cookies":
+		return oas.CookieParameter(name, schema, required)
+	case "header":
+		// https://swagger.io/docs/specification/authentication/basic-authentication/
+		return oas.HeaderParameter(name, schema, false)
+	}
+	return nil
+}
+
 func (scanner *OperatorScanner) scanForm(ctx context.Context, op *Operator, t *types.Struct) {
 	structSchema := oas.ObjectOf(nil)
 	schemas := make([]*oas.Schema, 0)
+	// 文件字段通过 mime 标签声明的类型
+	encodings := make(map[string]*oas.Encoding)
 	var location = "form"
 
 	for i := 0; i < t.NumFields(); i++ {
@@ -676,6 +815,12 @@ func (scanner *OperatorScanner) scanForm(ctx context.Context, op *Operator, t *t
 			scanner.propSchemaByField(ctx, field.Name(), structFieldType, tags, name, flags, scanner.pkg.CommentsOf(scanner.pkg.IdentOf(field))),
 			required,
 		)
+
+		if mimeTag := tags.Get("mime"); mimeTag != "" && tagValueForLocation == "multipart" {
+			encoding := oas.NewEncoding()
+			encoding.ContentType = mimeTag
+			encodings[name] = encoding
+		}
 	}
 
 	if len(schemas) > 0 {
@@ -688,8 +833,12 @@ func (scanner *OperatorScanner) scanForm(ctx context.Context, op *Operator, t *t
 		reqBody.AddContent("application/x-www-form-urlencoded", oas.NewMediaTypeWithSchema(structSchema))
 		op.SetRequestBody(reqBody)
 	case "form", "multipart":
+		mediaType := oas.NewMediaTypeWithSchema(structSchema)
+		for name, encoding := range encodings {
+			mediaType.AddEncoding(name, encoding)
+		}
 		reqBody := oas.NewRequestBody("", true)
-		reqBody.AddContent("multipart/form-data", oas.NewMediaTypeWithSchema(structSchema))
+		reqBody.AddContent("multipart/form-data", mediaType)
 		op.SetRequestBody(reqBody)
 	}
 }
@@ -710,6 +859,8 @@ type Operator struct {
 
 	StatusErrors      []*statuserror.StatusErr
 	StatusErrorSchema *oas.Schema
+	// 有参数的接口在参数校验失败时返回的 400 响应
+	ValidationErrorSchema *oas.Schema
 
 	StatusCodeMap map[int64]int
 
@@ -723,7 +874,12 @@ func (operator *Operator) AddNonBodyParameter(parameter *oas.Parameter) {
 		operator.NonBodyParameters = map[string]*oas.Parameter{}
 	}
 	parameter.Description = parameter.Schema.Description
-	operator.NonBodyParameters[parameter.Name] = parameter
+	operator.NonBodyParameters[parameterKey(parameter)] = parameter
+}
+
+// parameterKey 参数以位置和名称区分，同名参数可以出现在不同位置
+func parameterKey(parameter *oas.Parameter) string {
+	return string(parameter.In) + "." + parameter.Name
 }
 
 func (operator *Operator) SetRequestBody(requestBody *oas.RequestBody) {
@@ -754,14 +910,29 @@ func (operator *Operator) getStatusCodeForError(statusError *statuserror.StatusE
 	return statusError.StatusCode()
 }
 
+// validatable 接口有参数时，参数绑定或校验失败会返回包含失败字段的 400 响应
+func (operator *Operator) validatable() bool {
+	return operator.ValidationErrorSchema != nil && (len(operator.NonBodyParameters) > 0 || operator.RequestBody != nil)
+}
+
+// badRequestError 参数绑定或校验失败时返回的错误
+func badRequestError() *statuserror.StatusErr {
+	statusErr := statuserror.NewStatusErr(e2.BadRequest.Key(), e2.BadRequest.Code())
+	statusErr.Messages = map[string]string{}
+	for lang, messages := range e2.GetStatusErrorMap() {
+		statusErr.Messages[lang] = messages[e2.BadRequest]
+	}
+	return statusErr
+}
+
 func (operator *Operator) BindOperation(method string, operation *oas.Operation, last bool) {
-	parameterNames := map[string]bool{}
+	parameterKeys := map[string]bool{}
 	for _, parameter := range operation.Parameters {
-		parameterNames[parameter.Name] = true
+		parameterKeys[parameterKey(parameter)] = true
 	}
 
 	for _, parameter := range operator.NonBodyParameters {
-		if !parameterNames[parameter.Name] {
+		if !parameterKeys[parameterKey(parameter)] {
 			operation.Parameters = append(operation.Parameters, parameter)
 		}
 	}
@@ -772,8 +943,14 @@ func (operator *Operator) BindOperation(method string, operation *oas.Operation,
 
 	sortParameters(operation.Parameters)
 
-	for _, statusError := range operator.StatusErrors {
+	statusErrors := operator.StatusErrors
+	if operator.validatable() {
+		statusErrors = append(statusErrors, badRequestError())
+	}
+
+	for _, statusError := range statusErrors {
 		statusErrorList := make([]string, 0)
+		statusErrorMessages := make(map[string]map[string]string)
 
 		// 获取状态码：优先使用自定义映射，否则使用默认逻辑
 		code := operator.getStatusCodeForError(statusError)
@@ -789,6 +966,13 @@ func (operator *Operator) BindOperation(method string, operation *oas.Operation,
 							statusErrorList = append(statusErrorList, list...)
 						}
 					}
+					if v, ok := resp.Extensions[XStatusErrMessages]; ok {
+						if messages, ok := v.(map[string]map[string]string); ok {
+							for k, m := range messages {
+								statusErrorMessages[k] = m
+							}
+						}
+					}
 				}
 			}
 		}
@@ -804,8 +988,15 @@ func (operator *Operator) BindOperation(method string, operation *oas.Operation,
 
 		sort.Strings(statusErrorList)
 
+		if len(statusError.Messages) > 0 {
+			statusErrorMessages[statusError.Summary()] = statusError.Messages
+		}
+
 		resp := oas.NewResponse("")
 		resp.AddExtension(XStatusErrs, statusErrorList)
+		if len(statusErrorMessages) > 0 {
+			resp.AddExtension(XStatusErrMessages, statusErrorMessages)
+		}
 		if len(statusErrorList) > 0 {
 			var description = new(bytes.Buffer)
 			fmt.Fprintln(description, ">")
@@ -814,7 +1005,11 @@ func (operator *Operator) BindOperation(method string, operation *oas.Operation,
 			}
 			resp.Description = description.String()
 		}
-		resp.AddContent("application/json", oas.NewMediaTypeWithSchema(operator.StatusErrorSchema))
+		schema := operator.StatusErrorSchema
+		if code == http.StatusBadRequest && operator.validatable() {
+			schema = operator.ValidationErrorSchema
+		}
+		resp.AddContent("application/json", oas.NewMediaTypeWithSchema(schema))
 		operation.AddResponse(int(code), resp)
 	}
 
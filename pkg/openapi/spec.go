@@ -0,0 +1,95 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/go-courier/oas"
+	"gopkg.in/yaml.v3"
+)
+
+// LoadSpec 从文件路径或 http(s) 地址加载 JSON 或 YAML 格式的 OpenAPI 文档
+func LoadSpec(location string) (*oas.OpenAPI, error) {
+	u, err := url.Parse(location)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		data, err := os.ReadFile(strings.TrimPrefix(location, "file://"))
+		if err != nil {
+			return nil, err
+		}
+		return UnmarshalSpec(data)
+	}
+
+	resp, err := http.Get(location)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("load openapi from %s: %s", location, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return UnmarshalSpec(data)
+}
+
+// UnmarshalSpec 解析 JSON 或 YAML 格式的 OpenAPI 文档
+func UnmarshalSpec(data []byte) (*oas.OpenAPI, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] != '{' {
+		var v interface{}
+		if err := yaml.Unmarshal(data, &v); err != nil {
+			return nil, err
+		}
+		jsonData, err := json.Marshal(yamlToJSONValue(v))
+		if err != nil {
+			return nil, err
+		}
+		data = jsonData
+	}
+
+	spec := &oas.OpenAPI{}
+	if err := json.Unmarshal(data, spec); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// NormalizeSpec 将扫描源码得到的文档序列化后重新解析，保证与从文件加载的文档生成的代码一致
+func NormalizeSpec(spec *oas.OpenAPI) (*oas.OpenAPI, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	return UnmarshalSpec(data)
+}
+
+// yamlToJSONValue 将 YAML 中的非字符串键（如响应状态码）转换为字符串
+func yamlToJSONValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for k, item := range value {
+			value[k] = yamlToJSONValue(item)
+		}
+		return value
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			m[fmt.Sprint(k)] = yamlToJSONValue(item)
+		}
+		return m
+	case []interface{}:
+		for i, item := range value {
+			value[i] = yamlToJSONValue(item)
+		}
+		return value
+	}
+	return v
+}
@@ -27,10 +27,11 @@ func NewStatusErrScanner(pkg *packagesx.Package) *StatusErrScanner {
 }
 
 type StatusErrScanner struct {
-	StatusErrType    *types.Named
-	pkg              *packagesx.Package
-	statusErrorTypes map[*types.Named][]*statuserror.StatusErr
-	errorsUsed       map[*types.Func][]*statuserror.StatusErr
+	StatusErrType     *types.Named
+	ValidationErrType *types.Named
+	pkg               *packagesx.Package
+	statusErrorTypes  map[*types.Named][]*statuserror.StatusErr
+	errorsUsed        map[*types.Func][]*statuserror.StatusErr
 }
 
 var statusErrPkgPath = reflect.TypeOf(statuserror.StatusErr{}).PkgPath()
@@ -179,6 +180,9 @@ func (scanner *StatusErrScanner) init() {
 	}
 
 	scanner.StatusErrType = packagesx.NewPackage(pkg).TypeName("StatusErr").Type().(*types.Named)
+	if typeName := packagesx.NewPackage(pkg).TypeName("ValidationError"); typeName != nil {
+		scanner.ValidationErrType = typeName.Type().(*types.Named)
+	}
 	ttypeStatusError := packagesx.NewPackage(pkg).TypeName("CommonError").Type().Underlying().(*types.Interface)
 
 	isStatusError := func(typ *types.TypeName) bool {
@@ -22,8 +22,13 @@ const (
 	XTagName     = `x-tag-name`
 	XTagIn       = `x-tag-in`
 
+	// XParamSources 参数的所有来源，按优先级排列，任一来源传入即可
+	XParamSources = `x-param-sources`
+
 	XEnumLabels = `x-enum-labels`
 	XStatusErrs = `x-status-errors`
+	// XStatusErrMessages 状态错误的多语言消息，格式为 {"[Key][Code]": {"zh": "...", "en": "..."}}
+	XStatusErrMessages = `x-status-error-messages`
 )
 
 var (
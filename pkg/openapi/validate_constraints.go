@@ -0,0 +1,186 @@
+package openapi
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-courier/oas"
+	"github.com/shrewx/ginx/internal/binding"
+)
+
+// validateFormats validate 规则对应的 format
+var validateFormats = map[string]string{
+	"email":    "email",
+	"url":      "uri",
+	"http_url": "uri",
+	"uri":      "uri",
+	"uuid":     "uuid",
+	"uuid4":    "uuid",
+	"ip":       "ip",
+	"ipv4":     "ipv4",
+	"ipv6":     "ipv6",
+	"cidr":     "cidr",
+	"cidrv4":   "cidr",
+	"cidrv6":   "cidr",
+	"hostname": "hostname",
+	"fqdn":     "hostname",
+	"mac":      "mac",
+	"base64":   "byte",
+}
+
+// validatePatterns validate 规则对应的正则
+var validatePatterns = map[string]string{
+	"alpha":    `^[a-zA-Z]+$`,
+	"alphanum": `^[a-zA-Z0-9]+$`,
+	"numeric":  `^[-+]?[0-9]+(?:\.[0-9]+)?$`,
+	"number":   `^[0-9]+$`,
+	"phone":    binding.PhonePattern,
+	"semver":   `^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`,
+}
+
+var reOneOfValue = regexp.MustCompile(`'[^']*'|\S+`)
+
+// setValidateConstraints 将 validate 标签中的规则转换为 Schema 的约束，dive 之后的规则作用于数组元素
+// 引用其他定义的 Schema 不做修改，避免影响共享的定义
+func setValidateConstraints(schema *oas.Schema, validate string) {
+	if schema == nil || validate == "" {
+		return
+	}
+
+	rules := strings.Split(validate, ",")
+	for i, rule := range rules {
+		if rule == "dive" {
+			if schema.Type == oas.TypeArray && schema.Items != nil && schema.Items.Refer == nil {
+				setValidateConstraints(schema.Items, strings.Join(rules[i+1:], ","))
+			}
+			return
+		}
+		setValidateConstraint(schema, rule)
+	}
+}
+
+func setValidateConstraint(schema *oas.Schema, rule string) {
+	// 或规则无法表示为单一约束
+	if strings.Contains(rule, "|") {
+		return
+	}
+
+	tag, param, _ := strings.Cut(rule, "=")
+
+	if format, ok := validateFormats[tag]; ok && schema.Format == "" {
+		schema.Format = format
+		return
+	}
+	if pattern, ok := validatePatterns[tag]; ok && schema.Pattern == "" {
+		schema.Pattern = pattern
+		return
+	}
+
+	switch tag {
+	case "oneof":
+		schema.Enum = oneOfValues(schema.Type, param)
+	case "unique":
+		if schema.Type == oas.TypeArray {
+			schema.UniqueItems = true
+		}
+	case "startswith":
+		setPattern(schema, "^"+regexp.QuoteMeta(param))
+	case "endswith":
+		setPattern(schema, regexp.QuoteMeta(param)+"$")
+	case "contains":
+		setPattern(schema, regexp.QuoteMeta(param))
+	case "len":
+		setMin(schema, param, false)
+		setMax(schema, param, false)
+	case "min", "gte":
+		setMin(schema, param, false)
+	case "gt":
+		setMin(schema, param, true)
+	case "max", "lte":
+		setMax(schema, param, false)
+	case "lt":
+		setMax(schema, param, true)
+	}
+}
+
+func setPattern(schema *oas.Schema, pattern string) {
+	if schema.Pattern == "" {
+		schema.Pattern = pattern
+	}
+}
+
+// setMin 按类型设置最小值、最小长度或最少元素个数，exclusive 表示不包含边界
+func setMin(schema *oas.Schema, param string, exclusive bool) {
+	switch schema.Type {
+	case oas.TypeInteger, oas.TypeNumber:
+		if v, err := strconv.ParseFloat(param, 64); err == nil {
+			schema.Minimum = &v
+			schema.ExclusiveMinimum = exclusive
+		}
+	case oas.TypeString:
+		if v, ok := lengthOf(param, exclusive, 1); ok {
+			schema.MinLength = &v
+		}
+	case oas.TypeArray:
+		if v, ok := lengthOf(param, exclusive, 1); ok {
+			schema.MinItems = &v
+		}
+	}
+}
+
+// setMax 按类型设置最大值、最大长度或最多元素个数，exclusive 表示不包含边界
+func setMax(schema *oas.Schema, param string, exclusive bool) {
+	switch schema.Type {
+	case oas.TypeInteger, oas.TypeNumber:
+		if v, err := strconv.ParseFloat(param, 64); err == nil {
+			schema.Maximum = &v
+			schema.ExclusiveMaximum = exclusive
+		}
+	case oas.TypeString:
+		if v, ok := lengthOf(param, exclusive, -1); ok {
+			schema.MaxLength = &v
+		}
+	case oas.TypeArray:
+		if v, ok := lengthOf(param, exclusive, -1); ok {
+			schema.MaxItems = &v
+		}
+	}
+}
+
+// lengthOf 解析长度参数，不包含边界时按 delta 调整
+func lengthOf(param string, exclusive bool, delta int64) (uint64, bool) {
+	v, err := strconv.ParseInt(param, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	if exclusive {
+		v += delta
+	}
+	if v < 0 {
+		return 0, false
+	}
+	return uint64(v), true
+}
+
+// oneOfValues 解析 oneof 的候选值，值之间以空格分隔，包含空格的值使用单引号
+func oneOfValues(typ oas.Type, param string) []interface{} {
+	values := make([]interface{}, 0)
+	for _, v := range reOneOfValue.FindAllString(param, -1) {
+		v = strings.Trim(v, "'")
+		switch typ {
+		case oas.TypeInteger:
+			if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+				values = append(values, i)
+				continue
+			}
+		case oas.TypeNumber:
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				values = append(values, f)
+				continue
+			}
+		}
+		values = append(values, v)
+	}
+	return values
+}
@@ -0,0 +1,117 @@
+package service_discovery
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// DoneFunc 请求结束时调用，用于释放实例占用
+type DoneFunc func()
+
+// Balancer 负载均衡器，从非空实例列表中选择一个实例
+type Balancer interface {
+	Pick(instances []Instance) (Instance, DoneFunc)
+}
+
+// BalancerFactory 创建负载均衡器，每个服务独立持有一个负载均衡器
+type BalancerFactory func() Balancer
+
+func noopDone() {}
+
+// roundRobinBalancer 轮询
+type roundRobinBalancer struct {
+	next atomic.Uint64
+}
+
+func NewRoundRobinBalancer() Balancer {
+	return &roundRobinBalancer{}
+}
+
+func (b *roundRobinBalancer) Pick(instances []Instance) (Instance, DoneFunc) {
+	n := b.next.Add(1) - 1
+	return instances[n%uint64(len(instances))], noopDone
+}
+
+// weightedBalancer 平滑加权轮询
+type weightedBalancer struct {
+	mu      sync.Mutex
+	current map[string]int
+}
+
+func NewWeightedBalancer() Balancer {
+	return &weightedBalancer{current: make(map[string]int)}
+}
+
+func (b *weightedBalancer) Pick(instances []Instance) (Instance, DoneFunc) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var (
+		total int
+		best  = -1
+		seen  = make(map[string]struct{}, len(instances))
+	)
+	for i, instance := range instances {
+		weight := instance.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		total += weight
+		b.current[instance.ID] += weight
+		seen[instance.ID] = struct{}{}
+		if best < 0 || b.current[instance.ID] > b.current[instances[best].ID] {
+			best = i
+		}
+	}
+	b.current[instances[best].ID] -= total
+
+	// 清理已下线实例的状态
+	for id := range b.current {
+		if _, ok := seen[id]; !ok {
+			delete(b.current, id)
+		}
+	}
+
+	return instances[best], noopDone
+}
+
+// leastInflightBalancer 选择进行中请求数最少的实例
+type leastInflightBalancer struct {
+	mu       sync.Mutex
+	inflight map[string]int
+	next     int
+}
+
+func NewLeastInflightBalancer() Balancer {
+	return &leastInflightBalancer{inflight: make(map[string]int)}
+}
+
+func (b *leastInflightBalancer) Pick(instances []Instance) (Instance, DoneFunc) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// 从轮转的起点开始比较，避免并发数相同时总是选中第一个实例
+	start := b.next % len(instances)
+	b.next++
+	best := start
+	for i := 1; i < len(instances); i++ {
+		idx := (start + i) % len(instances)
+		if b.inflight[instances[idx].ID] < b.inflight[instances[best].ID] {
+			best = idx
+		}
+	}
+
+	id := instances[best].ID
+	b.inflight[id]++
+
+	var once sync.Once
+	return instances[best], func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			if b.inflight[id]--; b.inflight[id] <= 0 {
+				delete(b.inflight, id)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package service_discovery
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testInstances() []Instance {
+	return []Instance{
+		{ID: "a", Address: "10.0.0.1", Port: 80, Weight: 5, Healthy: true},
+		{ID: "b", Address: "10.0.0.2", Port: 80, Weight: 1, Healthy: true},
+		{ID: "c", Address: "10.0.0.3", Port: 80, Weight: 1, Healthy: true},
+	}
+}
+
+func TestRoundRobinBalancer(t *testing.T) {
+	b := NewRoundRobinBalancer()
+	instances := testInstances()
+
+	var picked []string
+	for i := 0; i < 6; i++ {
+		instance, done := b.Pick(instances)
+		done()
+		picked = append(picked, instance.ID)
+	}
+	assert.Equal(t, []string{"a", "b", "c", "a", "b", "c"}, picked)
+}
+
+func TestWeightedBalancer(t *testing.T) {
+	b := NewWeightedBalancer()
+	instances := testInstances()
+
+	counts := make(map[string]int)
+	var picked []string
+	for i := 0; i < 7; i++ {
+		instance, _ := b.Pick(instances)
+		counts[instance.ID]++
+		picked = append(picked, instance.ID)
+	}
+	assert.Equal(t, map[string]int{"a": 5, "b": 1, "c": 1}, counts)
+	// 平滑加权轮询不会连续选中低权重实例
+	assert.Equal(t, "a", picked[0])
+	assert.NotEqual(t, picked[1], picked[2])
+}
+
+func TestLeastInflightBalancer(t *testing.T) {
+	b := NewLeastInflightBalancer()
+	instances := testInstances()
+
+	first, doneFirst := b.Pick(instances)
+	second, doneSecond := b.Pick(instances)
+	third, _ := b.Pick(instances)
+	assert.ElementsMatch(t, []string{"a", "b", "c"}, []string{first.ID, second.ID, third.ID})
+
+	// 释放 first 后它是唯一空闲的实例
+	doneFirst()
+	doneFirst()
+	next, _ := b.Pick(instances)
+	assert.Equal(t, first.ID, next.ID)
+
+	doneSecond()
+	next, _ = b.Pick(instances)
+	assert.Equal(t, second.ID, next.ID)
+}
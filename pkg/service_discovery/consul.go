@@ -1,11 +1,11 @@
 package service_discovery
 
 import (
+	"context"
 	"fmt"
 	"github.com/hashicorp/consul/api"
-	"net/url"
-	"strconv"
-	"strings"
+	"sync"
+	"time"
 )
 
 type Consul struct {
@@ -17,6 +17,10 @@ type Consul struct {
 	username string
 	password string
 	token    string
+
+	mu         sync.Mutex
+	client     *api.Client
+	heartbeats heartbeats
 }
 
 type ConsulOption func(c *Consul)
@@ -69,6 +73,74 @@ func (c *Consul) Default() {
 }
 
 func (c *Consul) Watch(service ServiceInfo) error {
+	client, err := c.getClient()
+	if err != nil {
+		return err
+	}
+
+	service.Default()
+	registration := new(api.AgentServiceRegistration)
+	registration.ID = service.ID
+	registration.Name = service.Name
+	registration.Address, registration.Port, err = service.hostPort()
+	if err != nil {
+		return err
+	}
+	registration.Tags = service.Tags
+	registration.Meta = service.Meta
+	registration.Weights = &api.AgentWeights{
+		Passing: service.Weight,
+		Warning: 1,
+	}
+
+	check := &api.AgentServiceCheck{
+		CheckID:                        service.CheckID(),
+		DeregisterCriticalServiceAfter: fmt.Sprintf("%ds", service.DeregisterTime),
+	}
+	switch service.CheckType {
+	case CheckTypeTTL:
+		check.TTL = fmt.Sprintf("%ds", service.TTL)
+	default:
+		check.HTTP = fmt.Sprintf("%s%s", service.Address, service.HealthPath)
+		check.TLSSkipVerify = true
+		check.Timeout = fmt.Sprintf("%ds", service.Timeout)
+		check.Interval = fmt.Sprintf("%ds", service.Interval)
+	}
+	registration.Check = check
+
+	if err = client.Agent().ServiceRegister(registration); err != nil {
+		return err
+	}
+
+	if service.CheckType == CheckTypeTTL {
+		checkID := service.CheckID()
+		c.heartbeats.start(service.ID, time.Duration(service.Interval)*time.Second, func() error {
+			return client.Agent().UpdateTTL(checkID, "", api.HealthPassing)
+		})
+	}
+
+	return nil
+}
+
+func (c *Consul) Deregister(service ServiceInfo) error {
+	c.heartbeats.stop(service.ID)
+
+	client, err := c.getClient()
+	if err != nil {
+		return err
+	}
+
+	return client.Agent().ServiceDeregister(service.ID)
+}
+
+func (c *Consul) getClient() (*api.Client, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.client != nil {
+		return c.client, nil
+	}
+
 	c.Default()
 	config := api.DefaultConfig()
 	config.Address = c.address
@@ -84,34 +156,56 @@ func (c *Consul) Watch(service ServiceInfo) error {
 	config.Token = c.token
 	client, err := api.NewClient(config)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	c.client = client
 
-	service.Default()
-	registration := new(api.AgentServiceRegistration)
-	registration.Name = service.Name
-	addr, err := url.Parse(service.Address)
+	return client, nil
+}
+
+// Resolve 从 Consul 健康检查接口查询服务实例，passing 与 warning 状态的实例视为健康
+func (c *Consul) Resolve(ctx context.Context, name string) ([]Instance, error) {
+	client, err := c.getClient()
 	if err != nil {
-		return err
-	}
-	hostWithPort := strings.Split(addr.Host, ":")
-	registration.Address = hostWithPort[0]
-	if len(hostWithPort) == 2 {
-		port, _ := strconv.ParseInt(hostWithPort[1], 10, 64)
-		registration.Port = int(port)
-	}
-	registration.Tags = service.Tags
-	registration.Check = &api.AgentServiceCheck{
-		HTTP:                           fmt.Sprintf("%s%s", service.Address, service.HealthPath),
-		TLSSkipVerify:                  true,
-		Timeout:                        fmt.Sprintf("%ds", service.Timeout),
-		Interval:                       fmt.Sprintf("%ds", service.Interval),
-		DeregisterCriticalServiceAfter: fmt.Sprintf("%ds", service.DeregisterTime),
+		return nil, err
 	}
 
-	if err = client.Agent().ServiceRegister(registration); err != nil {
-		return err
+	entries, _, err := client.Health().Service(name, "", false, (&api.QueryOptions{}).WithContext(ctx))
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	instances := make([]Instance, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Service == nil {
+			continue
+		}
+		instance := Instance{
+			ID:      entry.Service.ID,
+			Name:    entry.Service.Service,
+			Address: entry.Service.Address,
+			Port:    entry.Service.Port,
+			Tags:    entry.Service.Tags,
+			Meta:    entry.Service.Meta,
+			Weight:  1,
+		}
+		if instance.Address == "" && entry.Node != nil {
+			instance.Address = entry.Node.Address
+		}
+
+		switch entry.Checks.AggregatedStatus() {
+		case api.HealthPassing:
+			instance.Healthy = true
+			if entry.Service.Weights.Passing > 0 {
+				instance.Weight = entry.Service.Weights.Passing
+			}
+		case api.HealthWarning:
+			instance.Healthy = true
+			if entry.Service.Weights.Warning > 0 {
+				instance.Weight = entry.Service.Weights.Warning
+			}
+		}
+		instances = append(instances, instance)
+	}
+	return instances, nil
 }
@@ -0,0 +1,113 @@
+package service_discovery
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeConsulAgent struct {
+	mu           sync.Mutex
+	registered   *api.AgentServiceRegistration
+	ttlUpdates   map[string]int
+	deregistered []string
+}
+
+func (f *fakeConsulAgent) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case r.URL.Path == "/v1/agent/service/register":
+		reg := new(api.AgentServiceRegistration)
+		if err := json.NewDecoder(r.Body).Decode(reg); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.registered = reg
+	case strings.HasPrefix(r.URL.Path, "/v1/agent/check/update/"):
+		if f.ttlUpdates == nil {
+			f.ttlUpdates = make(map[string]int)
+		}
+		f.ttlUpdates[strings.TrimPrefix(r.URL.Path, "/v1/agent/check/update/")]++
+	case strings.HasPrefix(r.URL.Path, "/v1/agent/service/deregister/"):
+		f.deregistered = append(f.deregistered, strings.TrimPrefix(r.URL.Path, "/v1/agent/service/deregister/"))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeConsulAgent) updates(checkID string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.ttlUpdates[checkID]
+}
+
+func TestConsul_HTTPCheck(t *testing.T) {
+	agent := &fakeConsulAgent{}
+	server := httptest.NewServer(agent)
+	defer server.Close()
+
+	consul := NewConsul(WithAddress(strings.TrimPrefix(server.URL, "http://")))
+	service := ServiceInfo{
+		ID:      "user-svc-1",
+		Name:    "user-svc",
+		Address: "http://10.0.0.1:8080",
+		Meta:    map[string]string{"version": "v1"},
+		Weight:  5,
+	}
+	require.NoError(t, consul.Watch(service))
+
+	reg := agent.registered
+	require.NotNil(t, reg)
+	assert.Equal(t, "10.0.0.1", reg.Address)
+	assert.Equal(t, 8080, reg.Port)
+	assert.Equal(t, "v1", reg.Meta["version"])
+	assert.Equal(t, 5, reg.Weights.Passing)
+	assert.Equal(t, "service:user-svc-1", reg.Check.CheckID)
+	assert.Equal(t, "http://10.0.0.1:8080/health", reg.Check.HTTP)
+	assert.Empty(t, reg.Check.TTL)
+}
+
+func TestConsul_TTLHeartbeatAndDeregister(t *testing.T) {
+	agent := &fakeConsulAgent{}
+	server := httptest.NewServer(agent)
+	defer server.Close()
+
+	consul := NewConsul(WithAddress(strings.TrimPrefix(server.URL, "http://")))
+	service := ServiceInfo{
+		ID:        "user-svc-1",
+		Name:      "user-svc",
+		Address:   "http://10.0.0.1",
+		Port:      8080,
+		CheckType: CheckTypeTTL,
+		Interval:  1,
+	}
+	require.NoError(t, consul.Watch(service))
+
+	reg := agent.registered
+	require.NotNil(t, reg)
+	assert.Equal(t, 8080, reg.Port)
+	assert.Equal(t, 1, reg.Weights.Passing)
+	assert.Equal(t, "3s", reg.Check.TTL)
+	assert.Empty(t, reg.Check.HTTP)
+
+	assert.Eventually(t, func() bool {
+		return agent.updates("service:user-svc-1") > 0
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, consul.Deregister(service))
+	assert.Equal(t, []string{"user-svc-1"}, agent.deregistered)
+
+	beats := agent.updates("service:user-svc-1")
+	time.Sleep(1200 * time.Millisecond)
+	assert.Equal(t, beats, agent.updates("service:user-svc-1"), "heartbeat should stop after deregister")
+}
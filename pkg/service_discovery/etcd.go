@@ -0,0 +1,365 @@
+package service_discovery
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const DefaultEtcdPrefix = "/services"
+
+// Etcd 基于 etcd v3 JSON 网关的注册中心
+// 服务实例写入 <prefix>/<name>/<id>，并绑定 TTL 租约，心跳即租约续期
+type Etcd struct {
+	endpoints []string
+	prefix    string
+	username  string
+	password  string
+	client    *http.Client
+
+	mu         sync.Mutex
+	token      string
+	leases     map[string]string
+	heartbeats heartbeats
+}
+
+type EtcdOption func(e *Etcd)
+
+func NewEtcd(options ...EtcdOption) *Etcd {
+	e := &Etcd{}
+	for _, option := range options {
+		option(e)
+	}
+	return e
+}
+
+// WithEtcdEndpoints 设置 etcd 地址，例如 http://127.0.0.1:2379
+func WithEtcdEndpoints(endpoints ...string) EtcdOption {
+	return func(e *Etcd) {
+		e.endpoints = endpoints
+	}
+}
+
+// WithEtcdPrefix 设置服务注册的 key 前缀
+func WithEtcdPrefix(prefix string) EtcdOption {
+	return func(e *Etcd) {
+		e.prefix = prefix
+	}
+}
+
+func WithEtcdAuth(username, password string) EtcdOption {
+	return func(e *Etcd) {
+		e.username = username
+		e.password = password
+	}
+}
+
+func (e *Etcd) Default() {
+	if len(e.endpoints) == 0 {
+		e.endpoints = []string{"http://127.0.0.1:2379"}
+	}
+	if e.prefix == "" {
+		e.prefix = DefaultEtcdPrefix
+	}
+	if e.client == nil {
+		e.client = &http.Client{Timeout: 5 * time.Second}
+	}
+	if e.leases == nil {
+		e.leases = make(map[string]string)
+	}
+}
+
+// Watch 注册服务并启动租约续期，etcd 只支持心跳方式的健康检查
+func (e *Etcd) Watch(service ServiceInfo) error {
+	e.mu.Lock()
+	e.Default()
+	e.mu.Unlock()
+
+	service.Default()
+	if err := e.register(service); err != nil {
+		return err
+	}
+
+	e.heartbeats.start(service.ID, time.Duration(service.Interval)*time.Second, func() error {
+		return e.keepalive(service)
+	})
+	return nil
+}
+
+func (e *Etcd) Deregister(service ServiceInfo) error {
+	e.heartbeats.stop(service.ID)
+
+	e.mu.Lock()
+	e.Default()
+	lease := e.leases[service.ID]
+	delete(e.leases, service.ID)
+	e.mu.Unlock()
+
+	if err := e.call("/v3/kv/deleterange", map[string]interface{}{
+		"key": encodeKey(e.key(service.Name, service.ID)),
+	}, nil); err != nil {
+		return err
+	}
+	if lease != "" {
+		return e.call("/v3/lease/revoke", map[string]interface{}{"ID": lease}, nil)
+	}
+	return nil
+}
+
+func (e *Etcd) Resolve(ctx context.Context, name string) ([]Instance, error) {
+	e.mu.Lock()
+	e.Default()
+	e.mu.Unlock()
+
+	prefix := e.key(name, "")
+	resp := struct {
+		Kvs []struct {
+			Value string `json:"value"`
+		} `json:"kvs"`
+	}{}
+	if err := e.callContext(ctx, "/v3/kv/range", map[string]interface{}{
+		"key":       encodeKey(prefix),
+		"range_end": encodeKey(prefixEnd(prefix)),
+	}, &resp); err != nil {
+		return nil, err
+	}
+
+	instances := make([]Instance, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		data, err := base64.StdEncoding.DecodeString(kv.Value)
+		if err != nil {
+			return nil, err
+		}
+		var instance Instance
+		if err := json.Unmarshal(data, &instance); err != nil {
+			return nil, err
+		}
+		// 租约过期的 key 会被 etcd 删除，存在即健康
+		instance.Healthy = true
+		instances = append(instances, instance)
+	}
+	return instances, nil
+}
+
+// register 申请租约并写入服务实例
+func (e *Etcd) register(service ServiceInfo) error {
+	host, port, err := service.hostPort()
+	if err != nil {
+		return err
+	}
+	value, err := json.Marshal(Instance{
+		ID:      service.ID,
+		Name:    service.Name,
+		Address: host,
+		Port:    port,
+		Tags:    service.Tags,
+		Meta:    service.Meta,
+		Weight:  service.Weight,
+	})
+	if err != nil {
+		return err
+	}
+
+	grant := struct {
+		ID string `json:"ID"`
+	}{}
+	if err := e.call("/v3/lease/grant", map[string]interface{}{"TTL": service.TTL}, &grant); err != nil {
+		return err
+	}
+
+	if err := e.call("/v3/kv/put", map[string]interface{}{
+		"key":   encodeKey(e.key(service.Name, service.ID)),
+		"value": base64.StdEncoding.EncodeToString(value),
+		"lease": grant.ID,
+	}, nil); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	e.leases[service.ID] = grant.ID
+	e.mu.Unlock()
+	return nil
+}
+
+// keepalive 续期租约，租约已过期时重新注册
+func (e *Etcd) keepalive(service ServiceInfo) error {
+	e.mu.Lock()
+	lease := e.leases[service.ID]
+	e.mu.Unlock()
+
+	resp := struct {
+		Result struct {
+			TTL string `json:"TTL"`
+		} `json:"result"`
+	}{}
+	if err := e.call("/v3/lease/keepalive", map[string]interface{}{"ID": lease}, &resp); err != nil {
+		return err
+	}
+	if resp.Result.TTL == "" || resp.Result.TTL == "0" {
+		return e.register(service)
+	}
+	return nil
+}
+
+func (e *Etcd) key(name, id string) string {
+	return strings.TrimSuffix(e.prefix, "/") + "/" + name + "/" + id
+}
+
+func (e *Etcd) call(path string, body interface{}, out interface{}) error {
+	return e.callContext(context.Background(), path, body, out)
+}
+
+// callContext 依次尝试各个 endpoint 调用 etcd 网关接口
+func (e *Etcd) callContext(ctx context.Context, path string, body interface{}, out interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	// token 过期时重新认证并重试一次
+	for retried := false; ; retried = true {
+		token, err := e.authenticate(ctx)
+		if err != nil {
+			return err
+		}
+		err = e.post(ctx, path, data, token, out)
+		if retried || token == "" || !isUnauthorized(err) {
+			return err
+		}
+		e.resetToken(token)
+	}
+}
+
+// post 依次尝试各个 endpoint 发送请求
+func (e *Etcd) post(ctx context.Context, path string, data []byte, token string, out interface{}) error {
+	var lastErr error
+	for _, endpoint := range e.endpoints {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(endpoint, "/")+path, bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", token)
+		}
+
+		resp, err := e.client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return decodeResponse(resp, out)
+	}
+	return lastErr
+}
+
+// resetToken 清除已失效的 token，其他请求已刷新时不清除
+func (e *Etcd) resetToken(token string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.token == token {
+		e.token = ""
+	}
+}
+
+// authenticate 使用用户名密码换取 token，token 会被缓存，失效后由 resetToken 清除
+func (e *Etcd) authenticate(ctx context.Context) (string, error) {
+	if e.username == "" {
+		return "", nil
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.token != "" {
+		return e.token, nil
+	}
+
+	data, _ := json.Marshal(map[string]string{"name": e.username, "password": e.password})
+	var lastErr error
+	for _, endpoint := range e.endpoints {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(endpoint, "/")+"/v3/auth/authenticate", bytes.NewReader(data))
+		if err != nil {
+			return "", err
+		}
+		resp, err := e.client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		auth := struct {
+			Token string `json:"token"`
+		}{}
+		if err := decodeResponse(resp, &auth); err != nil {
+			return "", err
+		}
+		e.token = auth.Token
+		return e.token, nil
+	}
+	return "", lastErr
+}
+
+func decodeResponse(resp *http.Response, out interface{}) error {
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return &responseError{
+			method:     resp.Request.Method,
+			path:       resp.Request.URL.Path,
+			statusCode: resp.StatusCode,
+			body:       strings.TrimSpace(string(data)),
+		}
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
+
+// responseError 注册中心返回的非 2xx 响应
+type responseError struct {
+	method     string
+	path       string
+	statusCode int
+	body       string
+}
+
+func (e *responseError) Error() string {
+	return fmt.Sprintf("%s %s: %d %s", e.method, e.path, e.statusCode, e.body)
+}
+
+// isUnauthorized token 失效时注册中心返回 401 或 403
+func isUnauthorized(err error) bool {
+	var respErr *responseError
+	if !errors.As(err, &respErr) {
+		return false
+	}
+	return respErr.statusCode == http.StatusUnauthorized || respErr.statusCode == http.StatusForbidden
+}
+
+func encodeKey(key string) string {
+	return base64.StdEncoding.EncodeToString([]byte(key))
+}
+
+// prefixEnd 返回前缀查询的 range_end，即前缀最后一个字节加一
+func prefixEnd(prefix string) string {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return string(end[:i+1])
+		}
+	}
+	return "\x00"
+}
@@ -0,0 +1,202 @@
+package service_discovery
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEtcd 模拟 etcd v3 JSON 网关的 kv 与 lease 接口
+type fakeEtcd struct {
+	mu         sync.Mutex
+	nextLease  int
+	leases     map[string]bool
+	kvs        map[string]string
+	keyLease   map[string]string
+	keepalives int
+	// token 不为空时开启认证，修改 token 模拟过期
+	token  string
+	logins int
+}
+
+func newFakeEtcd() *fakeEtcd {
+	return &fakeEtcd{
+		leases:   make(map[string]bool),
+		kvs:      make(map[string]string),
+		keyLease: make(map[string]string),
+	}
+}
+
+func (f *fakeEtcd) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	body := map[string]interface{}{}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	str := func(key string) string { return fmt.Sprint(body[key]) }
+	decode := func(key string) string {
+		data, _ := base64.StdEncoding.DecodeString(str(key))
+		return string(data)
+	}
+
+	if r.URL.Path == "/v3/auth/authenticate" {
+		f.logins++
+		_ = json.NewEncoder(w).Encode(map[string]string{"token": f.token})
+		return
+	}
+	if f.token != "" && r.Header.Get("Authorization") != f.token {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":"etcdserver: invalid auth token","code":16}`))
+		return
+	}
+
+	var resp interface{} = map[string]interface{}{}
+	switch r.URL.Path {
+	case "/v3/lease/grant":
+		f.nextLease++
+		id := fmt.Sprint(f.nextLease)
+		f.leases[id] = true
+		resp = map[string]string{"ID": id, "TTL": str("TTL")}
+	case "/v3/kv/put":
+		key := decode("key")
+		f.kvs[key] = str("value")
+		f.keyLease[key] = str("lease")
+	case "/v3/lease/keepalive":
+		f.keepalives++
+		result := map[string]string{"ID": str("ID")}
+		if f.leases[str("ID")] {
+			result["TTL"] = "3"
+		}
+		resp = map[string]interface{}{"result": result}
+	case "/v3/lease/revoke":
+		f.revoke(str("ID"))
+	case "/v3/kv/deleterange":
+		delete(f.kvs, decode("key"))
+	case "/v3/kv/range":
+		start, end := decode("key"), decode("range_end")
+		var kvs []map[string]string
+		for key, value := range f.kvs {
+			if key >= start && key < end {
+				kvs = append(kvs, map[string]string{"key": base64.StdEncoding.EncodeToString([]byte(key)), "value": value})
+			}
+		}
+		resp = map[string]interface{}{"kvs": kvs}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// revoke 撤销租约并删除绑定的 key，模拟租约过期
+func (f *fakeEtcd) revoke(lease string) {
+	delete(f.leases, lease)
+	for key, l := range f.keyLease {
+		if l == lease {
+			delete(f.kvs, key)
+			delete(f.keyLease, key)
+		}
+	}
+}
+
+func (f *fakeEtcd) keepaliveCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.keepalives
+}
+
+func TestEtcd_WatchResolveDeregister(t *testing.T) {
+	fake := newFakeEtcd()
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	etcd := NewEtcd(WithEtcdEndpoints("http://127.0.0.1:1", server.URL))
+	service := ServiceInfo{
+		ID:       "user-svc-1",
+		Name:     "user-svc",
+		Address:  "http://10.0.0.1:8080",
+		Meta:     map[string]string{"version": "v1"},
+		Weight:   3,
+		Interval: 1,
+	}
+	require.NoError(t, etcd.Watch(service))
+	assert.Contains(t, fake.kvs, "/services/user-svc/user-svc-1")
+
+	instances, err := etcd.Resolve(context.Background(), "user-svc")
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+	assert.Equal(t, "10.0.0.1", instances[0].Address)
+	assert.Equal(t, 8080, instances[0].Port)
+	assert.Equal(t, 3, instances[0].Weight)
+	assert.Equal(t, "v1", instances[0].Meta["version"])
+	assert.True(t, instances[0].Healthy)
+
+	assert.Eventually(t, func() bool {
+		return fake.keepaliveCount() > 0
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, etcd.Deregister(service))
+	instances, err = etcd.Resolve(context.Background(), "user-svc")
+	require.NoError(t, err)
+	assert.Empty(t, instances)
+}
+
+func TestEtcd_ReregisterAfterLeaseExpired(t *testing.T) {
+	fake := newFakeEtcd()
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	etcd := NewEtcd(WithEtcdEndpoints(server.URL), WithEtcdPrefix("/ginx"))
+	service := ServiceInfo{ID: "user-svc-1", Name: "user-svc", Address: "http://10.0.0.1:8080", Interval: 1}
+	require.NoError(t, etcd.Watch(service))
+	defer etcd.Deregister(service)
+
+	fake.mu.Lock()
+	fake.revoke("1")
+	fake.mu.Unlock()
+
+	assert.Eventually(t, func() bool {
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		_, ok := fake.kvs["/ginx/user-svc/user-svc-1"]
+		return ok
+	}, 2*time.Second, 20*time.Millisecond)
+}
+
+func TestEtcd_ReauthenticateAfterTokenExpired(t *testing.T) {
+	fake := newFakeEtcd()
+	fake.token = "token-1"
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	etcd := NewEtcd(WithEtcdEndpoints(server.URL), WithEtcdAuth("root", "root"))
+	service := ServiceInfo{ID: "user-svc-1", Name: "user-svc", Address: "http://10.0.0.1:8080", Interval: 1}
+	require.NoError(t, etcd.Watch(service))
+	defer etcd.Deregister(service)
+
+	// token 过期后重新认证，心跳与查询不受影响
+	fake.mu.Lock()
+	fake.token = "token-2"
+	keepalives := fake.keepalives
+	fake.mu.Unlock()
+
+	instances, err := etcd.Resolve(context.Background(), "user-svc")
+	require.NoError(t, err)
+	assert.Len(t, instances, 1)
+	assert.Eventually(t, func() bool {
+		return fake.keepaliveCount() > keepalives
+	}, 2*time.Second, 20*time.Millisecond)
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	assert.Equal(t, 2, fake.logins)
+}
@@ -0,0 +1,213 @@
+package service_discovery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const DefaultFileRegistryPath = ".ginx/registry.json"
+
+// FileRegistry 基于本地 JSON 文件的注册中心，用于本地开发
+// 实例通过心跳刷新 updated_at，超过 TTL 未刷新视为不健康，超过 DeregisterTime 未刷新会被清理
+type FileRegistry struct {
+	path string
+
+	mu         sync.Mutex
+	heartbeats heartbeats
+}
+
+type fileRecord struct {
+	Instance
+	TTL            int       `json:"ttl"`
+	DeregisterTime int       `json:"deregister_time"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+func NewFileRegistry(path string) *FileRegistry {
+	if path == "" {
+		path = DefaultFileRegistryPath
+	}
+	return &FileRegistry{path: path}
+}
+
+// Watch 写入服务实例并启动心跳，文件注册中心只支持心跳方式的健康检查
+func (f *FileRegistry) Watch(service ServiceInfo) error {
+	service.Default()
+	host, port, err := service.hostPort()
+	if err != nil {
+		return err
+	}
+	record := fileRecord{
+		Instance: Instance{
+			ID:      service.ID,
+			Name:    service.Name,
+			Address: host,
+			Port:    port,
+			Tags:    service.Tags,
+			Meta:    service.Meta,
+			Weight:  service.Weight,
+		},
+		TTL:            service.TTL,
+		DeregisterTime: service.DeregisterTime,
+	}
+
+	if err := f.upsert(record); err != nil {
+		return err
+	}
+
+	f.heartbeats.start(service.ID, time.Duration(service.Interval)*time.Second, func() error {
+		return f.upsert(record)
+	})
+	return nil
+}
+
+func (f *FileRegistry) Deregister(service ServiceInfo) error {
+	f.heartbeats.stop(service.ID)
+
+	return f.update(func(services map[string][]fileRecord) {
+		services[service.Name] = removeRecord(services[service.Name], service.ID)
+	})
+}
+
+func (f *FileRegistry) Resolve(ctx context.Context, name string) ([]Instance, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	services, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	instances := make([]Instance, 0, len(services[name]))
+	for _, record := range services[name] {
+		instance := record.Instance
+		instance.Healthy = now.Sub(record.UpdatedAt) <= time.Duration(record.TTL)*time.Second
+		instances = append(instances, instance)
+	}
+	return instances, nil
+}
+
+func (f *FileRegistry) upsert(record fileRecord) error {
+	return f.update(func(services map[string][]fileRecord) {
+		record.UpdatedAt = time.Now()
+		services[record.Name] = append(removeRecord(services[record.Name], record.ID), record)
+	})
+}
+
+// update 读取注册文件、修改并原子写回，同时清理过期的实例
+// 多个进程共用同一个注册文件，读写期间持有注册文件旁的 .lock 文件锁，避免互相覆盖
+func (f *FileRegistry) update(fn func(services map[string][]fileRecord)) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	unlock, err := lockFile(f.path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	services, err := f.load()
+	if err != nil {
+		return err
+	}
+
+	fn(services)
+
+	now := time.Now()
+	for name, records := range services {
+		alive := records[:0]
+		for _, record := range records {
+			if now.Sub(record.UpdatedAt) <= time.Duration(record.DeregisterTime)*time.Second {
+				alive = append(alive, record)
+			}
+		}
+		if len(alive) == 0 {
+			delete(services, name)
+		} else {
+			services[name] = alive
+		}
+	}
+
+	return f.save(services)
+}
+
+func (f *FileRegistry) load() (map[string][]fileRecord, error) {
+	services := make(map[string][]fileRecord)
+
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return services, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return services, nil
+	}
+	if err := json.Unmarshal(data, &services); err != nil {
+		return nil, err
+	}
+	return services, nil
+}
+
+// save 先写临时文件再重命名，避免其他进程读到不完整的内容
+func (f *FileRegistry) save(services map[string][]fileRecord) error {
+	data, err := json.MarshalIndent(services, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(f.path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(f.path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path)
+}
+
+// lockFile 对 path 加排他锁，其他进程加锁时阻塞，返回释放锁的函数
+func lockFile(path string) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := flock(file); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return func() {
+		_ = funlock(file)
+		file.Close()
+	}, nil
+}
+
+func removeRecord(records []fileRecord, id string) []fileRecord {
+	list := make([]fileRecord, 0, len(records))
+	for _, record := range records {
+		if record.ID != id {
+			list = append(list, record)
+		}
+	}
+	return list
+}
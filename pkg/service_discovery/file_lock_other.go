@@ -0,0 +1,14 @@
+//go:build !unix && !windows
+
+package service_discovery
+
+import "os"
+
+// flock 不支持文件锁的平台只依赖进程内的互斥锁
+func flock(file *os.File) error {
+	return nil
+}
+
+func funlock(file *os.File) error {
+	return nil
+}
@@ -0,0 +1,22 @@
+//go:build unix
+
+package service_discovery
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+func flock(file *os.File) error {
+	for {
+		err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX)
+		if !errors.Is(err, syscall.EINTR) {
+			return err
+		}
+	}
+}
+
+func funlock(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
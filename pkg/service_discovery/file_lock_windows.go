@@ -0,0 +1,17 @@
+//go:build windows
+
+package service_discovery
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+func flock(file *os.File) error {
+	return windows.LockFileEx(windows.Handle(file.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK, 0, 1, 0, &windows.Overlapped{})
+}
+
+func funlock(file *os.File) error {
+	return windows.UnlockFileEx(windows.Handle(file.Fd()), 0, 1, 0, &windows.Overlapped{})
+}
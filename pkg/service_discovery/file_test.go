@@ -0,0 +1,120 @@
+package service_discovery
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileRegistry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "registry.json")
+	registry := NewFileRegistry(path)
+
+	service := ServiceInfo{
+		ID:      "user-svc-1",
+		Name:    "user-svc",
+		Address: "127.0.0.1:8080",
+		Meta:    map[string]string{"version": "v1"},
+	}
+	require.NoError(t, registry.Watch(service))
+
+	// 其他进程通过同一个文件解析服务
+	instances, err := NewFileRegistry(path).Resolve(context.Background(), "user-svc")
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+	assert.Equal(t, "127.0.0.1", instances[0].Address)
+	assert.Equal(t, 8080, instances[0].Port)
+	assert.Equal(t, "v1", instances[0].Meta["version"])
+	assert.True(t, instances[0].Healthy)
+
+	require.NoError(t, registry.Deregister(service))
+	instances, err = registry.Resolve(context.Background(), "user-svc")
+	require.NoError(t, err)
+	assert.Empty(t, instances)
+}
+
+// 多个进程同时修改注册文件时不会丢失其他进程写入的实例
+func TestFileRegistry_ConcurrentUpdate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "registry.json")
+
+	const n = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- NewFileRegistry(path).upsert(fileRecord{
+				Instance:       Instance{ID: fmt.Sprintf("user-svc-%d", i), Name: "user-svc", Address: "127.0.0.1", Port: 8080 + i},
+				TTL:            15,
+				DeregisterTime: 30,
+			})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	instances, err := NewFileRegistry(path).Resolve(context.Background(), "user-svc")
+	require.NoError(t, err)
+	assert.Len(t, instances, n)
+}
+
+func TestFileRegistry_Expire(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "registry.json")
+	registry := NewFileRegistry(path)
+
+	stale := fileRecord{
+		Instance:       Instance{ID: "stale", Name: "user-svc", Address: "127.0.0.1", Port: 8081},
+		TTL:            15,
+		DeregisterTime: 30,
+	}
+	require.NoError(t, registry.update(func(services map[string][]fileRecord) {
+		stale.UpdatedAt = time.Now().Add(-20 * time.Second)
+		services["user-svc"] = []fileRecord{stale}
+	}))
+
+	instances, err := registry.Resolve(context.Background(), "user-svc")
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+	assert.False(t, instances[0].Healthy, "instance without heartbeat beyond TTL should be unhealthy")
+
+	require.NoError(t, registry.update(func(services map[string][]fileRecord) {
+		services["user-svc"][0].UpdatedAt = time.Now().Add(-time.Minute)
+	}))
+	instances, err = registry.Resolve(context.Background(), "user-svc")
+	require.NoError(t, err)
+	assert.Empty(t, instances, "instance beyond DeregisterTime should be removed")
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		config   Config
+		expected interface{}
+	}{
+		{Config{Type: TypeConsul, Endpoints: []string{"https://consul:8500"}}, &Consul{}},
+		{Config{Type: TypeEtcd}, &Etcd{}},
+		{Config{Type: "Nacos"}, &Nacos{}},
+		{Config{Type: TypeFile}, &FileRegistry{}},
+	}
+	for _, tt := range tests {
+		discovery, err := New(tt.config)
+		require.NoError(t, err)
+		assert.IsType(t, tt.expected, discovery)
+	}
+
+	consul, _ := New(Config{Type: TypeConsul, Endpoints: []string{"https://consul:8500"}})
+	assert.Equal(t, "https", consul.(*Consul).schema)
+	assert.Equal(t, "consul:8500", consul.(*Consul).address)
+
+	_, err := New(Config{Type: "zookeeper"})
+	assert.Error(t, err)
+}
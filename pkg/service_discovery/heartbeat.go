@@ -0,0 +1,64 @@
+package service_discovery
+
+import (
+	"sync"
+	"time"
+
+	"github.com/shrewx/ginx/pkg/logx"
+)
+
+// heartbeats 管理每个服务实例的心跳协程
+type heartbeats struct {
+	mu    sync.Mutex
+	beats map[string]*heartbeat
+}
+
+type heartbeat struct {
+	stop chan struct{}
+	done chan struct{}
+}
+
+// start 以 interval 为间隔执行 beat，同一个 id 重复启动时会先停止旧的心跳
+func (h *heartbeats) start(id string, interval time.Duration, beat func() error) {
+	h.stop(id)
+
+	hb := &heartbeat{stop: make(chan struct{}), done: make(chan struct{})}
+	h.mu.Lock()
+	if h.beats == nil {
+		h.beats = make(map[string]*heartbeat)
+	}
+	h.beats[id] = hb
+	h.mu.Unlock()
+
+	go func() {
+		defer close(hb.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := beat(); err != nil {
+				logx.Errorf("service %s heartbeat failed: %s", id, err.Error())
+			}
+
+			select {
+			case <-hb.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// stop 停止心跳并等待正在执行的 beat 结束，避免摘除后又被心跳重新注册
+func (h *heartbeats) stop(id string) {
+	h.mu.Lock()
+	hb, ok := h.beats[id]
+	delete(h.beats, id)
+	h.mu.Unlock()
+
+	if ok {
+		close(hb.stop)
+		<-hb.done
+	}
+}
@@ -0,0 +1,122 @@
+package service_discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/shrewx/ginx/pkg/logx"
+)
+
+const DefaultRefreshInterval = 10 * time.Second
+
+// ErrNoAvailableInstance 服务没有可用的健康实例
+var ErrNoAvailableInstance = errors.New("no available instance")
+
+// LoadBalancer 客户端负载均衡，缓存解析结果并按策略选择健康实例
+type LoadBalancer struct {
+	resolver        Resolver
+	factory         BalancerFactory
+	refreshInterval time.Duration
+
+	mu       sync.Mutex
+	services map[string]*serviceEntry
+}
+
+type serviceEntry struct {
+	mu        sync.Mutex
+	instances []Instance
+	expireAt  time.Time
+	balancer  Balancer
+}
+
+type LoadBalancerOption func(l *LoadBalancer)
+
+// WithBalancer 设置负载均衡策略，默认轮询
+func WithBalancer(factory BalancerFactory) LoadBalancerOption {
+	return func(l *LoadBalancer) {
+		l.factory = factory
+	}
+}
+
+// WithRefreshInterval 设置实例缓存的刷新间隔
+func WithRefreshInterval(interval time.Duration) LoadBalancerOption {
+	return func(l *LoadBalancer) {
+		l.refreshInterval = interval
+	}
+}
+
+func NewLoadBalancer(resolver Resolver, options ...LoadBalancerOption) *LoadBalancer {
+	l := &LoadBalancer{
+		resolver:        resolver,
+		factory:         NewRoundRobinBalancer,
+		refreshInterval: DefaultRefreshInterval,
+		services:        make(map[string]*serviceEntry),
+	}
+	for _, option := range options {
+		option(l)
+	}
+	return l
+}
+
+// Pick 为一次请求选择服务实例，请求结束后需调用返回的 DoneFunc
+func (l *LoadBalancer) Pick(ctx context.Context, name string) (Instance, DoneFunc, error) {
+	entry := l.entry(name)
+
+	instances, err := l.instances(ctx, name, entry)
+	if err != nil {
+		return Instance{}, nil, err
+	}
+
+	instance, done := entry.balancer.Pick(instances)
+	return instance, done, nil
+}
+
+func (l *LoadBalancer) entry(name string) *serviceEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, ok := l.services[name]
+	if !ok {
+		entry = &serviceEntry{balancer: l.factory()}
+		l.services[name] = entry
+	}
+	return entry
+}
+
+// instances 返回缓存中的健康实例，缓存过期时重新解析，解析失败时继续使用旧的实例列表
+func (l *LoadBalancer) instances(ctx context.Context, name string, entry *serviceEntry) ([]Instance, error) {
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	if entry.instances == nil || time.Now().After(entry.expireAt) {
+		resolved, err := l.resolver.Resolve(ctx, name)
+		switch {
+		case err == nil:
+			entry.instances = healthy(resolved)
+			entry.expireAt = time.Now().Add(l.refreshInterval)
+		case entry.instances == nil:
+			return nil, fmt.Errorf("resolve service %s failed: %w", name, err)
+		default:
+			entry.expireAt = time.Now().Add(l.refreshInterval)
+			logx.Errorf("resolve service %s failed, use cached instances: %s", name, err.Error())
+		}
+	}
+
+	if len(entry.instances) == 0 {
+		return nil, fmt.Errorf("service %s: %w", name, ErrNoAvailableInstance)
+	}
+	return entry.instances, nil
+}
+
+func healthy(instances []Instance) []Instance {
+	list := make([]Instance, 0, len(instances))
+	for _, instance := range instances {
+		if instance.Healthy {
+			list = append(list, instance)
+		}
+	}
+	return list
+}
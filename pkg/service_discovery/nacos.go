@@ -0,0 +1,325 @@
+package service_discovery
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultNacosGroup = "DEFAULT_GROUP"
+
+	nacosInstancePath = "/nacos/v1/ns/instance"
+	nacosBeatPath     = "/nacos/v1/ns/instance/beat"
+	nacosListPath     = "/nacos/v1/ns/instance/list"
+	nacosLoginPath    = "/nacos/v1/auth/login"
+
+	// nacosResourceNotFound 心跳时实例已被摘除，需要重新注册
+	nacosResourceNotFound = 20404
+)
+
+// Nacos 基于 Nacos open API 的注册中心
+// 服务以临时实例注册，心跳间隔、超时与摘除时间通过 preserved.* 元数据传递给 Nacos
+type Nacos struct {
+	endpoints []string
+	namespace string
+	group     string
+	username  string
+	password  string
+	client    *http.Client
+
+	mu         sync.Mutex
+	token      string
+	heartbeats heartbeats
+}
+
+type NacosOption func(n *Nacos)
+
+func NewNacos(options ...NacosOption) *Nacos {
+	n := &Nacos{}
+	for _, option := range options {
+		option(n)
+	}
+	return n
+}
+
+// WithNacosEndpoints 设置 Nacos 地址，例如 http://127.0.0.1:8848
+func WithNacosEndpoints(endpoints ...string) NacosOption {
+	return func(n *Nacos) {
+		n.endpoints = endpoints
+	}
+}
+
+// WithNacosNamespace 设置命名空间 ID
+func WithNacosNamespace(namespace string) NacosOption {
+	return func(n *Nacos) {
+		n.namespace = namespace
+	}
+}
+
+// WithNacosGroup 设置服务分组
+func WithNacosGroup(group string) NacosOption {
+	return func(n *Nacos) {
+		n.group = group
+	}
+}
+
+func WithNacosAuth(username, password string) NacosOption {
+	return func(n *Nacos) {
+		n.username = username
+		n.password = password
+	}
+}
+
+func (n *Nacos) Default() {
+	if len(n.endpoints) == 0 {
+		n.endpoints = []string{"http://127.0.0.1:8848"}
+	}
+	if n.group == "" {
+		n.group = DefaultNacosGroup
+	}
+	if n.client == nil {
+		n.client = &http.Client{Timeout: 5 * time.Second}
+	}
+}
+
+// Watch 注册临时实例并启动心跳，Nacos 只支持心跳方式的健康检查
+func (n *Nacos) Watch(service ServiceInfo) error {
+	n.mu.Lock()
+	n.Default()
+	n.mu.Unlock()
+
+	service.Default()
+	if err := n.register(service); err != nil {
+		return err
+	}
+
+	n.heartbeats.start(service.ID, time.Duration(service.Interval)*time.Second, func() error {
+		return n.beat(service)
+	})
+	return nil
+}
+
+func (n *Nacos) Deregister(service ServiceInfo) error {
+	n.heartbeats.stop(service.ID)
+
+	n.mu.Lock()
+	n.Default()
+	n.mu.Unlock()
+
+	host, port, err := service.hostPort()
+	if err != nil {
+		return err
+	}
+	params := n.params(service.Name)
+	params.Set("ip", host)
+	params.Set("port", strconv.Itoa(port))
+	params.Set("ephemeral", "true")
+
+	return n.call(context.Background(), http.MethodDelete, nacosInstancePath, params, nil)
+}
+
+func (n *Nacos) Resolve(ctx context.Context, name string) ([]Instance, error) {
+	n.mu.Lock()
+	n.Default()
+	n.mu.Unlock()
+
+	params := n.params(name)
+	params.Set("healthyOnly", "false")
+
+	resp := struct {
+		Hosts []struct {
+			InstanceID string            `json:"instanceId"`
+			IP         string            `json:"ip"`
+			Port       int               `json:"port"`
+			Weight     float64           `json:"weight"`
+			Healthy    bool              `json:"healthy"`
+			Enabled    bool              `json:"enabled"`
+			Metadata   map[string]string `json:"metadata"`
+		} `json:"hosts"`
+	}{}
+	if err := n.call(ctx, http.MethodGet, nacosListPath, params, &resp); err != nil {
+		return nil, err
+	}
+
+	instances := make([]Instance, 0, len(resp.Hosts))
+	for _, host := range resp.Hosts {
+		instance := Instance{
+			ID:      host.InstanceID,
+			Name:    name,
+			Address: host.IP,
+			Port:    host.Port,
+			Meta:    host.Metadata,
+			Weight:  int(host.Weight),
+			Healthy: host.Healthy && host.Enabled,
+		}
+		if instance.Weight <= 0 {
+			instance.Weight = 1
+		}
+		instances = append(instances, instance)
+	}
+	return instances, nil
+}
+
+func (n *Nacos) register(service ServiceInfo) error {
+	host, port, err := service.hostPort()
+	if err != nil {
+		return err
+	}
+	metadata, err := json.Marshal(n.metadata(service))
+	if err != nil {
+		return err
+	}
+
+	params := n.params(service.Name)
+	params.Set("ip", host)
+	params.Set("port", strconv.Itoa(port))
+	params.Set("weight", strconv.Itoa(service.Weight))
+	params.Set("enabled", "true")
+	params.Set("healthy", "true")
+	params.Set("ephemeral", "true")
+	params.Set("metadata", string(metadata))
+
+	return n.call(context.Background(), http.MethodPost, nacosInstancePath, params, nil)
+}
+
+// beat 上报心跳，实例已被摘除时重新注册
+func (n *Nacos) beat(service ServiceInfo) error {
+	host, port, err := service.hostPort()
+	if err != nil {
+		return err
+	}
+	beat, err := json.Marshal(map[string]interface{}{
+		"serviceName": n.group + "@@" + service.Name,
+		"ip":          host,
+		"port":        port,
+		"weight":      service.Weight,
+		"metadata":    n.metadata(service),
+	})
+	if err != nil {
+		return err
+	}
+
+	params := n.params(service.Name)
+	params.Set("ephemeral", "true")
+	params.Set("beat", string(beat))
+
+	resp := struct {
+		Code int `json:"code"`
+	}{}
+	if err := n.call(context.Background(), http.MethodPut, nacosBeatPath, params, &resp); err != nil {
+		return err
+	}
+	if resp.Code == nacosResourceNotFound {
+		return n.register(service)
+	}
+	return nil
+}
+
+func (n *Nacos) metadata(service ServiceInfo) map[string]string {
+	metadata := make(map[string]string, len(service.Meta)+4)
+	for k, v := range service.Meta {
+		metadata[k] = v
+	}
+	metadata["id"] = service.ID
+	metadata["preserved.heart.beat.interval"] = strconv.Itoa(service.Interval * 1000)
+	metadata["preserved.heart.beat.timeout"] = strconv.Itoa(service.TTL * 1000)
+	metadata["preserved.ip.delete.timeout"] = strconv.Itoa(service.DeregisterTime * 1000)
+	return metadata
+}
+
+func (n *Nacos) params(name string) url.Values {
+	params := url.Values{}
+	params.Set("serviceName", name)
+	params.Set("groupName", n.group)
+	if n.namespace != "" {
+		params.Set("namespaceId", n.namespace)
+	}
+	return params
+}
+
+// call 依次尝试各个 endpoint 调用 Nacos 接口
+func (n *Nacos) call(ctx context.Context, method, path string, params url.Values, out interface{}) error {
+	// accessToken 过期时重新登录并重试一次
+	for retried := false; ; retried = true {
+		token, err := n.login(ctx)
+		if err != nil {
+			return err
+		}
+		if token != "" {
+			params.Set("accessToken", token)
+		}
+		err = n.do(ctx, method, path, params, out)
+		if retried || token == "" || !isUnauthorized(err) {
+			return err
+		}
+		n.resetToken(token)
+	}
+}
+
+// do 依次尝试各个 endpoint 发送请求
+func (n *Nacos) do(ctx context.Context, method, path string, params url.Values, out interface{}) error {
+	var lastErr error
+	for _, endpoint := range n.endpoints {
+		req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(endpoint, "/")+path+"?"+params.Encode(), nil)
+		if err != nil {
+			return err
+		}
+		resp, err := n.client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		// 写接口返回纯文本 ok，只有查询与心跳接口需要解析
+		return decodeResponse(resp, out)
+	}
+	return lastErr
+}
+
+// resetToken 清除已失效的 accessToken，其他请求已刷新时不清除
+func (n *Nacos) resetToken(token string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.token == token {
+		n.token = ""
+	}
+}
+
+// login 使用用户名密码换取 accessToken，token 会被缓存，失效后由 resetToken 清除
+func (n *Nacos) login(ctx context.Context) (string, error) {
+	if n.username == "" {
+		return "", nil
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.token != "" {
+		return n.token, nil
+	}
+
+	form := url.Values{}
+	form.Set("username", n.username)
+	form.Set("password", n.password)
+	var lastErr error
+	for _, endpoint := range n.endpoints {
+		resp, err := n.client.PostForm(strings.TrimSuffix(endpoint, "/")+nacosLoginPath, form)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		auth := struct {
+			AccessToken string `json:"accessToken"`
+		}{}
+		if err := decodeResponse(resp, &auth); err != nil {
+			return "", err
+		}
+		n.token = auth.AccessToken
+		return n.token, nil
+	}
+	return "", lastErr
+}
@@ -0,0 +1,158 @@
+package service_discovery
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNacos 模拟 Nacos 临时实例的注册、心跳、摘除与查询接口
+type fakeNacos struct {
+	mu        sync.Mutex
+	instances map[string]map[string]interface{}
+	beats     int
+	token     string
+}
+
+func (f *fakeNacos) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.URL.Path == nacosLoginPath {
+		_ = r.ParseForm()
+		if r.PostForm.Get("username") != "nacos" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(`{"accessToken":"` + f.token + `"}`))
+		return
+	}
+
+	query := r.URL.Query()
+	if f.token != "" && query.Get("accessToken") != f.token {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	if query.Get("groupName") != DefaultNacosGroup || query.Get("namespaceId") != "dev" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	key := query.Get("ip") + ":" + query.Get("port")
+
+	switch {
+	case r.URL.Path == nacosInstancePath && r.Method == http.MethodPost:
+		metadata := map[string]string{}
+		_ = json.Unmarshal([]byte(query.Get("metadata")), &metadata)
+		f.instances[key] = map[string]interface{}{
+			"instanceId": key,
+			"ip":         query.Get("ip"),
+			"port":       query.Get("port"),
+			"weight":     query.Get("weight"),
+			"metadata":   metadata,
+		}
+		_, _ = w.Write([]byte("ok"))
+	case r.URL.Path == nacosInstancePath && r.Method == http.MethodDelete:
+		delete(f.instances, key)
+		_, _ = w.Write([]byte("ok"))
+	case r.URL.Path == nacosBeatPath:
+		f.beats++
+		code := 10200
+		if len(f.instances) == 0 {
+			code = nacosResourceNotFound
+		}
+		_, _ = w.Write([]byte(`{"clientBeatInterval":5000,"code":` + jsonInt(code) + `}`))
+	case r.URL.Path == nacosListPath:
+		hosts := make([]map[string]interface{}, 0)
+		for _, instance := range f.instances {
+			hosts = append(hosts, map[string]interface{}{
+				"instanceId": instance["instanceId"],
+				"ip":         instance["ip"],
+				"port":       8080,
+				"weight":     2.0,
+				"healthy":    true,
+				"enabled":    true,
+				"metadata":   instance["metadata"],
+			})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"hosts": hosts})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func jsonInt(i int) string {
+	data, _ := json.Marshal(i)
+	return string(data)
+}
+
+func (f *fakeNacos) count() (int, int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.instances), f.beats
+}
+
+func TestNacos_WatchResolveDeregister(t *testing.T) {
+	fake := &fakeNacos{instances: make(map[string]map[string]interface{}), token: "secret"}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	nacos := NewNacos(WithNacosEndpoints(server.URL), WithNacosNamespace("dev"), WithNacosAuth("nacos", "nacos"))
+	service := ServiceInfo{
+		ID:       "user-svc-1",
+		Name:     "user-svc",
+		Address:  "http://10.0.0.1:8080",
+		Meta:     map[string]string{"version": "v1"},
+		Interval: 1,
+	}
+	require.NoError(t, nacos.Watch(service))
+
+	instances, err := nacos.Resolve(context.Background(), "user-svc")
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+	assert.Equal(t, "10.0.0.1", instances[0].Address)
+	assert.Equal(t, 2, instances[0].Weight)
+	assert.Equal(t, "v1", instances[0].Meta["version"])
+	assert.Equal(t, "user-svc-1", instances[0].Meta["id"])
+	assert.Equal(t, "3000", instances[0].Meta["preserved.heart.beat.timeout"])
+	assert.True(t, instances[0].Healthy)
+
+	// 实例被服务端摘除后，心跳会重新注册
+	fake.mu.Lock()
+	fake.instances = make(map[string]map[string]interface{})
+	fake.mu.Unlock()
+	assert.Eventually(t, func() bool {
+		n, beats := fake.count()
+		return n == 1 && beats > 0
+	}, 2*time.Second, 20*time.Millisecond)
+
+	require.NoError(t, nacos.Deregister(service))
+	n, _ := fake.count()
+	assert.Equal(t, 0, n)
+}
+
+func TestNacos_LoginAfterTokenExpired(t *testing.T) {
+	fake := &fakeNacos{instances: make(map[string]map[string]interface{}), token: "token-1"}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	nacos := NewNacos(WithNacosEndpoints(server.URL), WithNacosNamespace("dev"), WithNacosAuth("nacos", "nacos"))
+	service := ServiceInfo{ID: "user-svc-1", Name: "user-svc", Address: "http://10.0.0.1:8080", Interval: 1}
+	require.NoError(t, nacos.Watch(service))
+	defer nacos.Deregister(service)
+
+	// accessToken 过期后重新登录，查询不受影响
+	fake.mu.Lock()
+	fake.token = "token-2"
+	fake.mu.Unlock()
+
+	instances, err := nacos.Resolve(context.Background(), "user-svc")
+	require.NoError(t, err)
+	assert.Len(t, instances, 1)
+}
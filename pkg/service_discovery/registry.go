@@ -0,0 +1,72 @@
+package service_discovery
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	TypeConsul = "consul"
+	TypeEtcd   = "etcd"
+	TypeNacos  = "nacos"
+	TypeFile   = "file"
+)
+
+// Config 注册中心配置
+type Config struct {
+	// 注册中心类型(consul/etcd/nacos/file)
+	Type string
+	// 注册中心地址，file 类型为注册文件路径
+	Endpoints []string
+	Username  string
+	Password  string
+	Token     string
+	// etcd 为 key 前缀，nacos 为命名空间 ID
+	Namespace string
+	// nacos 服务分组
+	Group string
+}
+
+// New 根据配置的类型创建注册中心
+func New(config Config) (ServiceDiscovery, error) {
+	switch strings.ToLower(config.Type) {
+	case TypeConsul:
+		options := []ConsulOption{WithToken(config.Token)}
+		if len(config.Endpoints) > 0 {
+			endpoint := config.Endpoints[0]
+			if schema, address, ok := strings.Cut(endpoint, "://"); ok {
+				options = append(options, WithSchema(schema))
+				endpoint = address
+			}
+			options = append(options, WithAddress(endpoint))
+		}
+		if config.Username != "" {
+			options = append(options, WithBasicAuth(config.Username, config.Password))
+		}
+		return NewConsul(options...), nil
+	case TypeEtcd:
+		options := []EtcdOption{WithEtcdEndpoints(config.Endpoints...), WithEtcdPrefix(config.Namespace)}
+		if config.Username != "" {
+			options = append(options, WithEtcdAuth(config.Username, config.Password))
+		}
+		return NewEtcd(options...), nil
+	case TypeNacos:
+		options := []NacosOption{
+			WithNacosEndpoints(config.Endpoints...),
+			WithNacosNamespace(config.Namespace),
+			WithNacosGroup(config.Group),
+		}
+		if config.Username != "" {
+			options = append(options, WithNacosAuth(config.Username, config.Password))
+		}
+		return NewNacos(options...), nil
+	case TypeFile:
+		path := ""
+		if len(config.Endpoints) > 0 {
+			path = config.Endpoints[0]
+		}
+		return NewFileRegistry(path), nil
+	default:
+		return nil, fmt.Errorf("unsupported service discovery type %q", config.Type)
+	}
+}
@@ -0,0 +1,139 @@
+package service_discovery
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+)
+
+// Instance 服务实例
+type Instance struct {
+	ID      string            `json:"id"`
+	Name    string            `json:"name"`
+	Address string            `json:"address"`
+	Port    int               `json:"port"`
+	Tags    []string          `json:"tags,omitempty"`
+	Meta    map[string]string `json:"meta,omitempty"`
+	Weight  int               `json:"weight"`
+	Healthy bool              `json:"-"`
+}
+
+// Host 返回实例的 host:port
+func (i Instance) Host() string {
+	if i.Port == 0 {
+		return i.Address
+	}
+	return net.JoinHostPort(i.Address, fmt.Sprintf("%d", i.Port))
+}
+
+// Resolver 服务解析器，根据服务名查询可用实例
+type Resolver interface {
+	Resolve(ctx context.Context, name string) ([]Instance, error)
+}
+
+// StaticResolver 静态服务列表解析器
+type StaticResolver struct {
+	mu       sync.RWMutex
+	services map[string][]Instance
+}
+
+// NewStaticResolver 创建静态解析器，未设置 Weight 的实例权重为 1 且视为健康
+func NewStaticResolver(services map[string][]Instance) *StaticResolver {
+	r := &StaticResolver{services: make(map[string][]Instance)}
+	for name, instances := range services {
+		r.Set(name, instances...)
+	}
+	return r
+}
+
+// Set 替换服务的实例列表
+func (r *StaticResolver) Set(name string, instances ...Instance) {
+	list := make([]Instance, 0, len(instances))
+	for _, instance := range instances {
+		if instance.Name == "" {
+			instance.Name = name
+		}
+		if instance.ID == "" {
+			instance.ID = instance.Host()
+		}
+		if instance.Weight == 0 {
+			instance.Weight = 1
+		}
+		instance.Healthy = true
+		list = append(list, instance)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.services[name] = list
+}
+
+func (r *StaticResolver) Resolve(ctx context.Context, name string) ([]Instance, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	instances, ok := r.services[name]
+	if !ok {
+		return nil, fmt.Errorf("service %s not found", name)
+	}
+	return append([]Instance(nil), instances...), nil
+}
+
+// DNSResolver 基于 DNS SRV 记录的解析器
+type DNSResolver struct {
+	format    string
+	lookupSRV func(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+}
+
+type DNSResolverOption func(r *DNSResolver)
+
+// WithDNSNameFormat 设置服务名到 SRV 记录名的格式，例如 "_http._tcp.%s.service.consul"
+func WithDNSNameFormat(format string) DNSResolverOption {
+	return func(r *DNSResolver) {
+		r.format = format
+	}
+}
+
+// WithNetResolver 使用自定义的 net.Resolver 查询 SRV 记录
+func WithNetResolver(resolver *net.Resolver) DNSResolverOption {
+	return func(r *DNSResolver) {
+		r.lookupSRV = resolver.LookupSRV
+	}
+}
+
+func NewDNSResolver(options ...DNSResolverOption) *DNSResolver {
+	r := &DNSResolver{
+		format:    "%s",
+		lookupSRV: net.DefaultResolver.LookupSRV,
+	}
+	for _, option := range options {
+		option(r)
+	}
+	return r
+}
+
+func (r *DNSResolver) Resolve(ctx context.Context, name string) ([]Instance, error) {
+	_, records, err := r.lookupSRV(ctx, "", "", fmt.Sprintf(r.format, name))
+	if err != nil {
+		return nil, err
+	}
+
+	instances := make([]Instance, 0, len(records))
+	for _, record := range records {
+		instance := Instance{
+			Name:    name,
+			Address: strings.TrimSuffix(record.Target, "."),
+			Port:    int(record.Port),
+			Weight:  int(record.Weight),
+			Healthy: true,
+		}
+		if instance.Weight == 0 {
+			instance.Weight = 1
+		}
+		instance.ID = instance.Host()
+		instances = append(instances, instance)
+	}
+	return instances, nil
+}
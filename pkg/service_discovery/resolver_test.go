@@ -0,0 +1,127 @@
+package service_discovery
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaticResolver(t *testing.T) {
+	r := NewStaticResolver(map[string][]Instance{
+		"user-svc": {{Address: "10.0.0.1", Port: 8080}},
+	})
+
+	instances, err := r.Resolve(context.Background(), "user-svc")
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+	assert.Equal(t, "10.0.0.1:8080", instances[0].ID)
+	assert.Equal(t, "user-svc", instances[0].Name)
+	assert.Equal(t, 1, instances[0].Weight)
+	assert.True(t, instances[0].Healthy)
+
+	_, err = r.Resolve(context.Background(), "order-svc")
+	assert.Error(t, err)
+}
+
+func TestDNSResolver(t *testing.T) {
+	r := NewDNSResolver(WithDNSNameFormat("_http._tcp.%s.service.consul"))
+
+	var queried string
+	r.lookupSRV = func(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+		queried = name
+		return "", []*net.SRV{
+			{Target: "node1.node.dc1.consul.", Port: 8080, Weight: 10},
+			{Target: "node2.node.dc1.consul.", Port: 8081},
+		}, nil
+	}
+
+	instances, err := r.Resolve(context.Background(), "user-svc")
+	require.NoError(t, err)
+	assert.Equal(t, "_http._tcp.user-svc.service.consul", queried)
+	require.Len(t, instances, 2)
+	assert.Equal(t, "node1.node.dc1.consul", instances[0].Address)
+	assert.Equal(t, 10, instances[0].Weight)
+	assert.Equal(t, 1, instances[1].Weight)
+	assert.Equal(t, "node2.node.dc1.consul:8081", instances[1].Host())
+}
+
+func TestConsul_Resolve(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/health/service/user-svc" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`[
+			{"Node":{"Address":"192.168.1.1"},"Service":{"ID":"a","Service":"user-svc","Address":"10.0.0.1","Port":8080,"Weights":{"Passing":5,"Warning":1}},"Checks":[{"Status":"passing"}]},
+			{"Node":{"Address":"192.168.1.2"},"Service":{"ID":"b","Service":"user-svc","Port":8080,"Weights":{"Passing":5,"Warning":2}},"Checks":[{"Status":"warning"}]},
+			{"Node":{"Address":"192.168.1.3"},"Service":{"ID":"c","Service":"user-svc","Address":"10.0.0.3","Port":8080},"Checks":[{"Status":"critical"}]}
+		]`))
+	}))
+	defer server.Close()
+
+	consul := NewConsul(WithAddress(strings.TrimPrefix(server.URL, "http://")))
+	instances, err := consul.Resolve(context.Background(), "user-svc")
+	require.NoError(t, err)
+	require.Len(t, instances, 3)
+
+	assert.True(t, instances[0].Healthy)
+	assert.Equal(t, 5, instances[0].Weight)
+	assert.True(t, instances[1].Healthy)
+	assert.Equal(t, "192.168.1.2", instances[1].Address)
+	assert.Equal(t, 2, instances[1].Weight)
+	assert.False(t, instances[2].Healthy)
+}
+
+type countingResolver struct {
+	calls     int
+	err       error
+	instances []Instance
+}
+
+func (r *countingResolver) Resolve(ctx context.Context, name string) ([]Instance, error) {
+	r.calls++
+	return r.instances, r.err
+}
+
+func TestLoadBalancer_Cache(t *testing.T) {
+	resolver := &countingResolver{instances: []Instance{
+		{ID: "a", Address: "10.0.0.1", Port: 80, Healthy: true},
+		{ID: "b", Address: "10.0.0.2", Port: 80, Healthy: false},
+	}}
+	lb := NewLoadBalancer(resolver, WithRefreshInterval(50*time.Millisecond))
+
+	for i := 0; i < 3; i++ {
+		instance, done, err := lb.Pick(context.Background(), "user-svc")
+		require.NoError(t, err)
+		done()
+		assert.Equal(t, "a", instance.ID, "unhealthy instances should be skipped")
+	}
+	assert.Equal(t, 1, resolver.calls)
+
+	// 刷新失败时继续使用缓存的实例
+	resolver.err = errors.New("consul unavailable")
+	time.Sleep(60 * time.Millisecond)
+	instance, _, err := lb.Pick(context.Background(), "user-svc")
+	require.NoError(t, err)
+	assert.Equal(t, "a", instance.ID)
+	assert.Equal(t, 2, resolver.calls)
+
+	_, _, err = lb.Pick(context.Background(), "order-svc")
+	assert.Error(t, err)
+}
+
+func TestLoadBalancer_NoHealthyInstance(t *testing.T) {
+	resolver := &countingResolver{instances: []Instance{{ID: "a", Healthy: false}}}
+	lb := NewLoadBalancer(resolver)
+
+	_, _, err := lb.Pick(context.Background(), "user-svc")
+	assert.ErrorIs(t, err, ErrNoAvailableInstance)
+}
@@ -3,10 +3,23 @@ package service_discovery
 import (
 	"fmt"
 	"github.com/hashicorp/go-uuid"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	// CheckTypeHTTP 注册中心主动请求 HealthPath 进行健康检查
+	CheckTypeHTTP = "http"
+	// CheckTypeTTL 服务定期上报心跳，超过 TTL 未上报视为不健康
+	CheckTypeTTL = "ttl"
 )
 
 type ServiceDiscovery interface {
+	// Watch 注册服务，TTL 检查方式下同时启动心跳
 	Watch(service ServiceInfo) error
+	// Deregister 停止心跳并从注册中心摘除服务
+	Deregister(service ServiceInfo) error
 }
 
 type ServiceInfo struct {
@@ -16,11 +29,14 @@ type ServiceInfo struct {
 	Address string
 	Port    int
 
+	Meta   map[string]string
 	Weight int
 
+	CheckType      string
 	HealthPath     string
 	Timeout        int
 	Interval       int
+	TTL            int
 	DeregisterTime int
 }
 
@@ -37,8 +53,41 @@ func (s *ServiceInfo) Default() {
 	if s.HealthPath == "" {
 		s.HealthPath = "/health"
 	}
+	if s.CheckType == "" {
+		s.CheckType = CheckTypeHTTP
+	}
+	if s.TTL == 0 {
+		s.TTL = s.Interval * 3
+	}
+	if s.Weight == 0 {
+		s.Weight = 1
+	}
 	if s.ID == "" {
 		id, _ := uuid.GenerateUUID()
 		s.ID = fmt.Sprintf("%s-%s", s.Name, id)
 	}
 }
+
+// CheckID 返回服务健康检查的 ID
+func (s *ServiceInfo) CheckID() string {
+	return "service:" + s.ID
+}
+
+// hostPort 从 Address 中解析 host 与端口，Address 不带端口时使用 Port
+func (s *ServiceInfo) hostPort() (string, int, error) {
+	address := s.Address
+	if !strings.Contains(address, "://") {
+		address = "//" + address
+	}
+	addr, err := url.Parse(address)
+	if err != nil {
+		return "", 0, err
+	}
+	port := s.Port
+	if p := addr.Port(); p != "" {
+		if port, err = strconv.Atoi(p); err != nil {
+			return "", 0, err
+		}
+	}
+	return addr.Hostname(), port, nil
+}
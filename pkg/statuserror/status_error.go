@@ -162,6 +162,12 @@ func (v *StatusErr) Code() int64 {
 	return v.ErrorCode
 }
 
+// Is 支持 errors.Is，错误码相同即视为同一错误，target 可以是 *StatusErr、服务端或客户端生成的错误码常量
+func (v *StatusErr) Is(target error) bool {
+	t, ok := target.(interface{ Code() int64 })
+	return ok && t.Code() == v.ErrorCode
+}
+
 func StatusCodeFromCode(code int64) int {
 	strCode := fmt.Sprintf("%d", code)
 	if len(strCode) < 3 {
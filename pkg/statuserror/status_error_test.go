@@ -1,6 +1,7 @@
 package statuserror
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
@@ -234,6 +235,21 @@ func TestStatusErr_Key(t *testing.T) {
 	assert.Equal(t, "TestError", key)
 }
 
+type testErrorCode int64
+
+func (c testErrorCode) Code() int64   { return int64(c) }
+func (c testErrorCode) Error() string { return fmt.Sprintf("%d", int64(c)) }
+
+// Test StatusErr Is method
+func TestStatusErr_Is(t *testing.T) {
+	var err error = fmt.Errorf("invoke: %w", NewStatusErr("TestError", 40000000001))
+
+	assert.True(t, errors.Is(err, testErrorCode(40000000001)))
+	assert.True(t, errors.Is(err, NewStatusErr("Other", 40000000001)))
+	assert.False(t, errors.Is(err, testErrorCode(40000000002)))
+	assert.False(t, errors.Is(err, errors.New("[TestError][40000000001]")))
+}
+
 // Test StatusCodeFromCode function
 func TestStatusCodeFromCode(t *testing.T) {
 	tests := []struct {
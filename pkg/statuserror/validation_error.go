@@ -0,0 +1,124 @@
+package statuserror
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/shrewx/ginx/pkg/i18nx"
+)
+
+const (
+	// ValidationMessages 校验规则错误信息的 i18n 前缀，消息 ID 为 <lang>.validation.<rule>
+	ValidationMessages = "validation"
+
+	// RuleType 参数格式错误（类型转换或反序列化失败）时的规则名
+	RuleType = "type"
+	// RuleDefault 未配置错误信息的规则使用的消息
+	RuleDefault = "default"
+)
+
+func init() {
+	i18nx.RegisterHooks(RegisterValidationMessages)
+}
+
+// FieldError 参数校验失败的字段
+type FieldError struct {
+	// 参数名，body 中的字段为 JSON 路径，如 items[0].name
+	Field string `json:"field"`
+	// 参数位置
+	In string `json:"in,omitempty"`
+	// 校验失败的规则，参数格式错误时为 type
+	Rule string `json:"rule"`
+	// 规则参数
+	Param string `json:"param,omitempty"`
+	// 错误信息
+	Message string `json:"message"`
+}
+
+func (e FieldError) String() string {
+	s := e.Field
+	if e.In != "" {
+		s = e.In + "." + s
+	}
+	if e.Param != "" {
+		return fmt.Sprintf("%s: %s=%s", s, e.Rule, e.Param)
+	}
+	return fmt.Sprintf("%s: %s", s, e.Rule)
+}
+
+// ValidationError 参数绑定或校验失败的错误，响应体在状态错误的基础上返回失败的字段
+type ValidationError struct {
+	*StatusErr
+	// 校验失败的字段
+	Errors []FieldError `json:"errors"`
+}
+
+func NewValidationError(key string, code int64, errs ...FieldError) *ValidationError {
+	return &ValidationError{
+		StatusErr: NewStatusErr(key, code),
+		Errors:    errs,
+	}
+}
+
+func (v *ValidationError) Error() string {
+	list := make([]string, 0, len(v.Errors))
+	for _, e := range v.Errors {
+		list = append(list, e.String())
+	}
+	return fmt.Sprintf("%s %s", v.StatusErr.Error(), strings.Join(list, "; "))
+}
+
+func (v *ValidationError) Localize(manager *i18nx.Localize, lang string) i18nx.I18nMessage {
+	if statusErr, ok := v.StatusErr.Localize(manager, lang).(*StatusErr); ok {
+		v.StatusErr = statusErr
+	}
+	for i := range v.Errors {
+		v.Errors[i].Message = localizeFieldError(manager, lang, v.Errors[i])
+	}
+	return v
+}
+
+// localizeFieldError 优先使用 i18n 中规则对应的消息，其次使用校验器翻译的消息
+func localizeFieldError(manager *i18nx.Localize, lang string, e FieldError) string {
+	data := map[string]interface{}{
+		"Field": e.Field,
+		"Rule":  e.Rule,
+		"Param": e.Param,
+	}
+	if msg, err := manager.LocalizeData(lang, ValidationMessages+"."+e.Rule, data); err == nil {
+		return msg
+	}
+	if e.Message != "" {
+		return e.Message
+	}
+	if msg, err := manager.LocalizeData(lang, ValidationMessages+"."+RuleDefault, data); err == nil {
+		return msg
+	}
+	return e.String()
+}
+
+// GetValidationMessageMap 返回参数格式错误与未翻译规则的默认消息，内置规则由校验器翻译
+// 服务可在 i18n 文件中以 <lang>.validation.<rule> 覆盖或补充规则的消息，模板中可使用 {{.Field}}、{{.Rule}}、{{.Param}}
+func GetValidationMessageMap() map[string]map[string]string {
+	return map[string]map[string]string{
+		"en": {
+			RuleDefault: "{{.Field}} failed on the {{.Rule}} rule",
+			RuleType:    "{{.Field}} has an invalid format",
+		},
+		"zh": {
+			RuleDefault: "{{.Field}}校验失败（{{.Rule}}）",
+			RuleType:    "{{.Field}}格式错误",
+		},
+	}
+}
+
+func RegisterValidationMessages() {
+	for lang, messages := range GetValidationMessageMap() {
+		var i18nMessages []*i18n.Message
+		for rule, message := range messages {
+			i18nMessages = append(i18nMessages, &i18n.Message{ID: fmt.Sprintf("%s.%s.%s", lang, ValidationMessages, rule), Other: message})
+		}
+		i18nx.AddMessages(lang, i18nMessages)
+	}
+}
@@ -0,0 +1,58 @@
+package statuserror
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/shrewx/ginx/pkg/conf"
+	"github.com/shrewx/ginx/pkg/i18nx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidationError_Localize(t *testing.T) {
+	i18nx.Load(&conf.I18N{Langs: []string{"zh", "en"}})
+	// 服务在 i18n 文件中覆盖的规则消息
+	i18nx.AddMessages("zh", []*i18n.Message{{ID: "zh.validation.phone", Other: "{{.Field}}必须是手机号"}})
+	i18nx.AddMessages("en", []*i18n.Message{{ID: "en.validation.phone", Other: "{{.Field}} must be a phone number"}})
+
+	newErr := func() *ValidationError {
+		return NewValidationError("BadRequest", 40000000001,
+			FieldError{Field: "size", In: "query", Rule: "lte", Param: "100", Message: "translated"},
+			FieldError{Field: "mobile", In: "body", Rule: "phone", Message: "translated"},
+			FieldError{Field: "items[0].name", In: "body", Rule: "custom_rule"},
+			FieldError{Field: "page", In: "query", Rule: RuleType},
+		)
+	}
+
+	assert.Equal(t, "[BadRequest][40000000001] query.size: lte=100; body.mobile: phone; body.items[0].name: custom_rule; query.page: type", newErr().Error())
+
+	tests := []struct {
+		lang   string
+		expect []string
+	}{
+		{lang: "zh", expect: []string{"translated", "mobile必须是手机号", "items[0].name校验失败（custom_rule）", "page格式错误"}},
+		{lang: "en", expect: []string{"translated", "mobile must be a phone number", "items[0].name failed on the custom_rule rule", "page has an invalid format"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			msg := newErr().Localize(i18nx.Instance(), tt.lang)
+
+			data, err := json.Marshal(msg)
+			require.NoError(t, err)
+
+			var body struct {
+				Key    string       `json:"key"`
+				Code   int64        `json:"code"`
+				Errors []FieldError `json:"errors"`
+			}
+			require.NoError(t, json.Unmarshal(data, &body))
+			assert.Equal(t, "BadRequest", body.Key)
+			assert.Equal(t, int64(40000000001), body.Code)
+			for i, e := range body.Errors {
+				assert.Equal(t, tt.expect[i], e.Message)
+			}
+		})
+	}
+}
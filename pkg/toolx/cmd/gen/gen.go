@@ -12,6 +12,7 @@ func init() {
 	CmdGen.AddCommand(statusErrorYamlCommand())
 	CmdGen.AddCommand(openapiCommand())
 	CmdGen.AddCommand(clientCommand())
+	CmdGen.AddCommand(tsClientCommand())
 	CmdGen.AddCommand(enumCommand())
 	CmdGen.AddCommand(i18nCommand())
 	CmdGen.AddCommand(statusI18nYamlCommand())
@@ -1,15 +1,19 @@
 package gen
 
 import (
-	"github.com/shrewx/ginx/pkg/client"
-	"github.com/spf13/cobra"
+	"fmt"
 	"net/url"
 	"os"
+
+	"github.com/shrewx/ginx/pkg/client"
+	"github.com/spf13/cobra"
 )
 
 var (
 	openUrl     string
 	serviceName string
+	fromPackage string
+	check       bool
 )
 
 func clientCommand() *cobra.Command {
@@ -21,18 +25,44 @@ func clientCommand() *cobra.Command {
 				path, _ = os.Getwd()
 			}
 
-			u, err := url.Parse(openUrl)
-			if err != nil {
-				panic(err)
+			g := newClientGenerator()
+			if !check {
+				g.Output(path)
+				return
+			}
+
+			stale := g.Check(path)
+			if len(stale) == 0 {
+				return
 			}
-			g := client.NewClientGenerator(serviceName, u)
-			g.Load()
-			g.Output(path)
+			for _, file := range stale {
+				fmt.Fprintf(os.Stderr, "%s is out of date\n", file)
+			}
+			fmt.Fprintln(os.Stderr, "run `toolx gen client` to regenerate the client")
+			os.Exit(1)
 		},
 	}
 
 	client.Flags().StringVarP(&serviceName, "serviceName", "s", "", " service name")
-	client.Flags().StringVarP(&openUrl, "url", "u", "", "the url to get openapi info")
+	client.Flags().StringVarP(&openUrl, "url", "u", "", "the url or file (json or yaml) to get openapi info")
+	client.Flags().StringVarP(&fromPackage, "from-package", "", "", "scan openapi from the main package of server instead of url")
+	client.Flags().StringVarP(&tags, "tags", "t", "", "build tags used with --from-package")
+	client.Flags().StringVarP(&path, "path", "p", "", "define the output path of client")
+	client.Flags().BoolVarP(&check, "check", "", false, "exit with non-zero code when the generated client is out of date")
 
 	return client
 }
+
+func newClientGenerator() *client.ClientGenerator {
+	if fromPackage != "" {
+		return client.NewClientGeneratorWithOpenAPI(serviceName, loadOpenAPI())
+	}
+
+	u, err := url.Parse(openUrl)
+	if err != nil {
+		panic(err)
+	}
+	g := client.NewClientGenerator(serviceName, u)
+	g.Load()
+	return g
+}
@@ -24,32 +24,9 @@ func openapiCommand() *cobra.Command {
 				path, _ = os.Getwd()
 			}
 
-			var (
-				pkg *packagesx.Package
-				err error
-			)
-
-			// 如果指定了构建标签，使用 packages.Load 并设置 BuildFlags
-			if tags != "" {
-				// 支持逗号或空格分隔的构建标签
-				tagValue := strings.ReplaceAll(tags, ",", " ")
-				tagValue = strings.TrimSpace(tagValue)
-				config := &packages.Config{
-					Mode:       packages.LoadAllSyntax | packages.NeedImports,
-					BuildFlags: []string{"-tags", tagValue},
-				}
-				pkgs, err := packages.Load(config, path)
-				if err != nil {
-					panic(err)
-				}
-
-				pkg = packagesx.NewPackage(pkgs[0])
-			} else {
-				// 默认行为，使用 packagesx.Load
-				pkg, err = packagesx.Load(path)
-				if err != nil {
-					panic(err)
-				}
+			pkg, err := loadPackage(path, tags)
+			if err != nil {
+				panic(err)
 			}
 
 			g := openapi.NewOpenAPIGenerator(pkg)
@@ -63,3 +40,25 @@ func openapiCommand() *cobra.Command {
 
 	return openapi
 }
+
+// loadPackage 加载服务的 main 包，tags 不为空时使用指定的构建标签
+func loadPackage(path string, tags string) (*packagesx.Package, error) {
+	if tags == "" {
+		// 默认行为，使用 packagesx.Load
+		return packagesx.Load(path)
+	}
+
+	// 支持逗号或空格分隔的构建标签
+	tagValue := strings.ReplaceAll(tags, ",", " ")
+	tagValue = strings.TrimSpace(tagValue)
+	config := &packages.Config{
+		Mode:       packages.LoadAllSyntax | packages.NeedImports,
+		BuildFlags: []string{"-tags", tagValue},
+	}
+	pkgs, err := packages.Load(config, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return packagesx.NewPackage(pkgs[0]), nil
+}
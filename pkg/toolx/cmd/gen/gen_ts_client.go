@@ -0,0 +1,73 @@
+package gen
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/go-courier/oas"
+	"github.com/shrewx/ginx/pkg/openapi"
+	"github.com/shrewx/ginx/pkg/tsclient"
+	"github.com/spf13/cobra"
+)
+
+func tsClientCommand() *cobra.Command {
+	tsClient := &cobra.Command{
+		Use:   "ts-client",
+		Short: "generate typescript client",
+		Run: func(cmd *cobra.Command, args []string) {
+			if path == "" {
+				path, _ = os.Getwd()
+			}
+
+			g := tsclient.NewGenerator(serviceName, loadOpenAPI())
+			if !check {
+				g.Output(path)
+				return
+			}
+
+			stale := g.Check(path)
+			if len(stale) == 0 {
+				return
+			}
+			for _, file := range stale {
+				fmt.Fprintf(os.Stderr, "%s is out of date\n", file)
+			}
+			fmt.Fprintln(os.Stderr, "run `toolx gen ts-client` to regenerate the client")
+			os.Exit(1)
+		},
+	}
+
+	tsClient.Flags().StringVarP(&serviceName, "serviceName", "s", "", " service name")
+	tsClient.Flags().StringVarP(&openUrl, "url", "u", "", "the url or file (json or yaml) to get openapi info")
+	tsClient.Flags().StringVarP(&fromPackage, "from-package", "", "", "scan openapi from the main package of server instead of url")
+	tsClient.Flags().StringVarP(&tags, "tags", "t", "", "build tags used with --from-package")
+	tsClient.Flags().StringVarP(&path, "path", "p", "", "define the output path of client")
+	tsClient.Flags().BoolVarP(&check, "check", "", false, "exit with non-zero code when the generated client is out of date")
+
+	return tsClient
+}
+
+// loadOpenAPI 扫描 --from-package 指定的包或从 --url 加载文档
+func loadOpenAPI() *oas.OpenAPI {
+	if fromPackage == "" {
+		spec, err := openapi.LoadSpec(openUrl)
+		if err != nil {
+			panic(err)
+		}
+		return spec
+	}
+
+	pkg, err := loadPackage(fromPackage, tags)
+	if err != nil {
+		panic(err)
+	}
+	g := openapi.NewOpenAPIGenerator(pkg)
+	g.Scan(context.Background())
+
+	spec, err := openapi.NormalizeSpec(g.OpenAPI())
+	if err != nil {
+		panic(err)
+	}
+	return spec
+}
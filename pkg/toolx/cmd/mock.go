@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/fatih/color"
+	"github.com/shrewx/ginx/pkg/mockx/mockserver"
+	"github.com/spf13/cobra"
+)
+
+var (
+	mockSpec string
+	mockPort int32
+)
+
+func Mock() *cobra.Command {
+	mock := &cobra.Command{
+		Use:   "mock",
+		Short: "serve a mock server from openapi",
+		Run: func(cmd *cobra.Command, args []string) {
+			spec, err := mockserver.LoadOpenAPI(mockSpec)
+			if err != nil {
+				panic(err)
+			}
+
+			addr := fmt.Sprintf(":%d", mockPort)
+			log.Printf("mock server of %s listening on %s", mockSpec, color.MagentaString(addr))
+			log.Printf("set header %s or %s to return declared status errors or status codes", mockserver.HeaderMockError, mockserver.HeaderMockStatus)
+			if err := http.ListenAndServe(addr, mockserver.NewServer(spec)); err != nil {
+				panic(err)
+			}
+		},
+	}
+
+	mock.Flags().StringVarP(&mockSpec, "url", "u", "openapi.json", "the file path or url to get openapi info")
+	mock.Flags().Int32VarP(&mockPort, "port", "p", 8888, "define mock server port")
+	return mock
+}
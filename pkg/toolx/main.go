@@ -16,6 +16,7 @@ var rootCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(gen.CmdGen)
 	rootCmd.AddCommand(cmd.Swagger())
+	rootCmd.AddCommand(cmd.Mock())
 	rootCmd.AddCommand(cmd.Init())
 	rootCmd.AddCommand(cmd.InstallSkill())
 }
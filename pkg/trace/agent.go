@@ -1,6 +1,7 @@
 package trace
 
 import (
+	"context"
 	"fmt"
 	"github.com/pkg/errors"
 	"github.com/shrewx/ginx/pkg/logx"
@@ -71,3 +72,11 @@ func (a *Agent) Init() error {
 
 	return nil
 }
+
+// Shutdown flushes buffered spans and stops the tracer provider
+func (a *Agent) Shutdown(ctx context.Context) error {
+	if tp, ok := a.TracerProvider.(*sdktrace.TracerProvider); ok {
+		return tp.Shutdown(ctx)
+	}
+	return nil
+}
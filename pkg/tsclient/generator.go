@@ -0,0 +1,531 @@
+package tsclient
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/go-courier/codegen"
+	"github.com/go-courier/oas"
+	"github.com/shrewx/ginx"
+	"github.com/shrewx/ginx/pkg/openapi"
+)
+
+const header = "// Code generated by tools. DO NOT EDIT!!!!\n\n"
+
+var reStatusErrorSummary = regexp.MustCompile(`^\[([^\]]+)\]\[(\d+)\]`)
+
+// Generator 根据 OpenAPI 文档生成 TypeScript 客户端，包括请求/响应类型、枚举、状态错误与基于 fetch 的运行时
+type Generator struct {
+	ServiceName string
+	openAPI     *oas.OpenAPI
+}
+
+func NewGenerator(serviceName string, spec *oas.OpenAPI) *Generator {
+	return &Generator{
+		ServiceName: serviceName,
+		openAPI:     spec,
+	}
+}
+
+// ClientName 客户端类名，与 Go 客户端接口名一致
+func (g *Generator) ClientName() string {
+	return codegen.UpperCamelCase("Client-" + g.ServiceName)
+}
+
+func (g *Generator) rootPath(cwd string) string {
+	return filepath.Join(cwd, strings.ReplaceAll(codegen.LowerSnakeCase("Client-"+g.ServiceName), "_", "-"))
+}
+
+// Output 生成客户端到 cwd 下的 client-<service> 目录
+func (g *Generator) Output(cwd string) {
+	root := g.rootPath(cwd)
+	if err := os.MkdirAll(root, os.ModePerm); err != nil {
+		panic(err)
+	}
+	for _, file := range g.files() {
+		if err := os.WriteFile(filepath.Join(root, file.name), file.data, 0644); err != nil {
+			panic(err)
+		}
+	}
+
+	log.Printf("generated typescript client of %s into %s", g.ServiceName, color.MagentaString(root))
+}
+
+// Check 检查已生成的客户端是否与文档一致，返回缺失或过期的文件
+func (g *Generator) Check(cwd string) []string {
+	root := g.rootPath(cwd)
+	stale := make([]string, 0)
+	for _, file := range g.files() {
+		filename := filepath.Join(root, file.name)
+		data, err := os.ReadFile(filename)
+		if err != nil || !bytes.Equal(data, file.data) {
+			stale = append(stale, filename)
+		}
+	}
+	return stale
+}
+
+type tsFile struct {
+	name string
+	data []byte
+}
+
+func (g *Generator) files() []tsFile {
+	operations := g.operations()
+	return []tsFile{
+		{name: "runtime.ts", data: []byte(TplRuntime)},
+		{name: "types.ts", data: g.types(operations)},
+		{name: "errors.ts", data: g.errors(operations)},
+		{name: "client.ts", data: g.client(operations)},
+		{name: "index.ts", data: []byte(header + `export * from "./runtime";
+export * from "./types";
+export * from "./errors";
+export * from "./client";
+`)},
+	}
+}
+
+type operation struct {
+	Method string
+	Path   string
+	*oas.Operation
+}
+
+// operations 返回按 OperationId 排序的接口，与 Go 客户端一样忽略文档自身的接口
+func (g *Generator) operations() []operation {
+	operations := make([]operation, 0)
+	for p, item := range g.openAPI.Paths.Paths {
+		for method, op := range item.Operations.Operations {
+			if strings.HasPrefix(op.OperationId, "OpenAPI") || strings.HasPrefix(op.OperationId, "ER") {
+				continue
+			}
+			operations = append(operations, operation{Method: strings.ToUpper(string(method)), Path: p, Operation: op})
+		}
+	}
+	sort.Slice(operations, func(i, j int) bool {
+		return operations[i].OperationId < operations[j].OperationId
+	})
+	return operations
+}
+
+func (g *Generator) types(operations []operation) []byte {
+	b := &bytes.Buffer{}
+	b.WriteString(header)
+
+	ids := make([]string, 0, len(g.openAPI.Components.Schemas))
+	for id := range g.openAPI.Components.Schemas {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		schema := g.openAPI.Components.Schemas[id]
+		name := typeName(id)
+		b.WriteString(comment("", schema.Description))
+		switch {
+		case len(schema.Enum) > 0:
+			g.writeEnum(b, name, schema)
+		case len(schema.Properties) > 0 && len(schema.AllOf) == 0:
+			fmt.Fprintf(b, "export interface %s %s\n\n", name, objectType(schema, "  "))
+		default:
+			fmt.Fprintf(b, "export type %s = %s;\n\n", name, tsType(schema))
+		}
+	}
+
+	for _, op := range operations {
+		params := requestParams(op)
+		if len(params) == 0 {
+			continue
+		}
+		fmt.Fprintf(b, "export interface %sRequest {\n", op.OperationId)
+		for _, p := range params {
+			b.WriteString(comment("  ", p.description))
+			optional := "?"
+			if p.required {
+				optional = ""
+			}
+			fmt.Fprintf(b, "  %s%s: %s;\n", propertyKey(p.name), optional, tsType(p.schema))
+		}
+		b.WriteString("}\n\n")
+	}
+
+	return append(bytes.TrimRight(b.Bytes(), "\n"), '\n')
+}
+
+// writeEnum 枚举生成联合类型、取值列表与描述
+func (g *Generator) writeEnum(b *bytes.Buffer, name string, schema *oas.Schema) {
+	values := enumLiterals(schema)
+	fmt.Fprintf(b, "export type %s = %s;\n\n", name, strings.Join(values, " | "))
+	fmt.Fprintf(b, "export const %sValues: %s[] = [%s];\n\n", name, name, strings.Join(values, ", "))
+
+	labels := enumLabels(schema)
+	if len(labels) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "export const %sLabels: Record<%s, string> = {\n", name, name)
+	seen := make(map[string]bool, len(schema.Enum))
+	for _, v := range schema.Enum {
+		key := fmt.Sprint(v)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		k := key
+		if _, ok := v.(string); ok || strings.HasPrefix(key, "-") {
+			k = propertyKey(key)
+		}
+		fmt.Fprintf(b, "  %s: %s,\n", k, literal(labels[key]))
+	}
+	b.WriteString("};\n\n")
+}
+
+type requestParam struct {
+	name        string
+	in          string
+	schema      *oas.Schema
+	required    bool
+	description string
+	mediaType   string
+}
+
+// requestParams 返回接口的参数，请求体作为 body 参数
+// 多个来源的参数与 Go 客户端一样只保留优先级最高的来源，任一来源传入即可，因此为可选参数，路径中包含该参数时除外
+func requestParams(op operation) []requestParam {
+	params := make([]requestParam, 0, len(op.Parameters)+1)
+	for _, p := range op.Parameters {
+		if p == nil || p.In == oas.PositionCookie {
+			continue
+		}
+		sources := paramSources(p)
+		if len(sources) > 0 {
+			if in, _ := ginx.ParamSourceIn(sources[0]); in != string(p.In) {
+				continue
+			}
+		}
+		required := p.Required || p.In == oas.PositionPath
+		if len(sources) > 1 {
+			required = p.In == oas.PositionPath && strings.Contains(op.Path, "{"+p.Name+"}")
+		}
+		params = append(params, requestParam{
+			name:        p.Name,
+			in:          string(p.In),
+			schema:      p.Schema,
+			required:    required,
+			description: p.Description,
+		})
+	}
+	if op.RequestBody != nil {
+		contentTypes := make([]string, 0, len(op.RequestBody.Content))
+		for contentType := range op.RequestBody.Content {
+			contentTypes = append(contentTypes, contentType)
+		}
+		sort.Strings(contentTypes)
+		if len(contentTypes) > 0 {
+			params = append(params, requestParam{
+				name:        "body",
+				in:          "body",
+				schema:      op.RequestBody.Content[contentTypes[0]].Schema,
+				required:    true,
+				description: op.RequestBody.Description,
+				mediaType:   contentTypes[0],
+			})
+		}
+	}
+	return params
+}
+
+// paramSources 返回参数的所有来源，按优先级排列，单一来源的参数返回空
+func paramSources(p *oas.Parameter) []string {
+	switch sources := p.Extensions[openapi.XParamSources].(type) {
+	case []string:
+		return sources
+	case []interface{}:
+		list := make([]string, 0, len(sources))
+		for _, source := range sources {
+			if s, ok := source.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	}
+	return nil
+}
+
+// successContent 返回最小的 2xx 响应的内容类型与媒体类型
+func successContent(op *oas.Operation) (string, *oas.MediaType) {
+	code := 0
+	for c := range op.Responses.Responses {
+		if c >= http.StatusOK && c < http.StatusMultipleChoices && (code == 0 || c < code) {
+			code = c
+		}
+	}
+	resp := op.Responses.Responses[code]
+	if resp == nil || len(resp.Content) == 0 {
+		return "", nil
+	}
+
+	contentTypes := make([]string, 0, len(resp.Content))
+	for contentType := range resp.Content {
+		contentTypes = append(contentTypes, contentType)
+	}
+	sort.Strings(contentTypes)
+	return contentTypes[0], resp.Content[contentTypes[0]]
+}
+
+// responseSchema 返回 JSON 响应的 schema
+func responseSchema(op *oas.Operation) *oas.Schema {
+	contentType, mediaType := successContent(op)
+	if mediaType == nil || !strings.Contains(contentType, "json") {
+		return nil
+	}
+	return mediaType.Schema
+}
+
+// response 返回成功响应的类型与解析方式
+func response(op *oas.Operation) (string, string) {
+	contentType, mediaType := successContent(op)
+	switch {
+	case mediaType == nil:
+		return "void", "none"
+	case strings.Contains(contentType, "json"):
+		return tsType(mediaType.Schema), "json"
+	case strings.HasPrefix(contentType, "text/"):
+		return "string", "text"
+	}
+	return "Blob", "blob"
+}
+
+func bodyType(mediaType string) string {
+	switch {
+	case strings.HasPrefix(mediaType, "multipart/"):
+		return "multipart"
+	case mediaType == "application/x-www-form-urlencoded":
+		return "form"
+	case strings.Contains(mediaType, "json"):
+		return "json"
+	}
+	return "binary"
+}
+
+func (g *Generator) client(operations []operation) []byte {
+	b := &bytes.Buffer{}
+	b.WriteString(header)
+	b.WriteString("import { request } from \"./runtime\";\n")
+	b.WriteString("import type { ClientOptions } from \"./runtime\";\n")
+
+	imports := map[string]bool{}
+	for _, op := range operations {
+		if len(requestParams(op)) > 0 {
+			imports[op.OperationId+"Request"] = true
+		}
+		if schema := responseSchema(op.Operation); schema != nil {
+			collectRefs(schema, imports)
+		}
+	}
+	if len(imports) > 0 {
+		names := make([]string, 0, len(imports))
+		for name := range imports {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Fprintf(b, "import type { %s } from \"./types\";\n", strings.Join(names, ", "))
+	}
+
+	fmt.Fprintf(b, "\nexport class %s {\n", g.ClientName())
+	b.WriteString("  private readonly options: ClientOptions;\n\n")
+	b.WriteString("  constructor(options: ClientOptions) {\n    this.options = options;\n  }\n")
+
+	for _, op := range operations {
+		params := requestParams(op)
+		respType, responseType := response(op.Operation)
+
+		b.WriteString("\n")
+		if docs := operationDoc(op.Operation); docs != "" {
+			b.WriteString(docs)
+		}
+
+		signature := "init?: RequestInit"
+		if len(params) > 0 {
+			signature = fmt.Sprintf("req: %sRequest, init?: RequestInit", op.OperationId)
+		}
+		fmt.Fprintf(b, "  %s(%s): Promise<%s> {\n", codegen.LowerCamelCase(op.OperationId), signature, respType)
+		fmt.Fprintf(b, "    return request<%s>(\n      this.options,\n      {\n", respType)
+		fmt.Fprintf(b, "        method: %s,\n", literal(op.Method))
+		fmt.Fprintf(b, "        path: %s,\n", literal(op.Path))
+
+		for _, group := range []struct{ in, field string }{
+			{"path", "pathParams"},
+			{"query", "query"},
+			{"header", "headers"},
+		} {
+			fields := make([]string, 0)
+			for _, p := range params {
+				if p.in == group.in {
+					fields = append(fields, fmt.Sprintf("%s: %s", propertyKey(p.name), propertyAccess("req", p.name)))
+				}
+			}
+			if len(fields) > 0 {
+				fmt.Fprintf(b, "        %s: { %s },\n", group.field, strings.Join(fields, ", "))
+			}
+		}
+		for _, p := range params {
+			if p.in == "body" {
+				b.WriteString("        body: req.body,\n")
+				fmt.Fprintf(b, "        bodyType: %s,\n", literal(bodyType(p.mediaType)))
+			}
+		}
+		fmt.Fprintf(b, "        responseType: %s,\n", literal(responseType))
+		b.WriteString("      },\n      init,\n    );\n  }\n")
+	}
+	b.WriteString("}\n")
+
+	return b.Bytes()
+}
+
+func operationDoc(op *oas.Operation) string {
+	lines := make([]string, 0)
+	if op.Summary != "" {
+		lines = append(lines, op.Summary)
+	}
+	if op.Deprecated {
+		lines = append(lines, "@deprecated")
+	}
+	for _, statusErr := range statusErrors([]operation{{Operation: op}}) {
+		lines = append(lines, fmt.Sprintf("@throws {StatusError} %s %d", statusErr.key, statusErr.code))
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	b := &strings.Builder{}
+	b.WriteString("  /**\n")
+	for _, line := range lines {
+		fmt.Fprintf(b, "   * %s\n", strings.ReplaceAll(line, "*/", "*\\/"))
+	}
+	b.WriteString("   */\n")
+	return b.String()
+}
+
+type statusError struct {
+	key      string
+	code     int64
+	messages map[string]string
+}
+
+// statusErrors 收集接口声明的状态错误及其多语言消息，按 code 排序
+func statusErrors(operations []operation) []statusError {
+	byKey := make(map[string]statusError)
+	for _, op := range operations {
+		for _, resp := range op.Responses.Responses {
+			if resp == nil || resp.Extensions == nil {
+				continue
+			}
+			messages := summaryMessages(resp.Extensions[openapi.XStatusErrMessages])
+			for _, summary := range summaries(resp.Extensions[openapi.XStatusErrs]) {
+				matched := reStatusErrorSummary.FindStringSubmatch(summary)
+				if matched == nil {
+					continue
+				}
+				code, _ := strconv.ParseInt(matched[2], 10, 64)
+				if _, ok := byKey[matched[1]]; !ok {
+					byKey[matched[1]] = statusError{key: matched[1], code: code, messages: messages[summary]}
+				}
+			}
+		}
+	}
+
+	list := make([]statusError, 0, len(byKey))
+	for _, e := range byKey {
+		list = append(list, e)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].code != list[j].code {
+			return list[i].code < list[j].code
+		}
+		return list[i].key < list[j].key
+	})
+	return list
+}
+
+func summaries(v interface{}) []string {
+	switch list := v.(type) {
+	case []string:
+		return list
+	case []interface{}:
+		s := make([]string, 0, len(list))
+		for _, item := range list {
+			if summary, ok := item.(string); ok {
+				s = append(s, summary)
+			}
+		}
+		return s
+	}
+	return nil
+}
+
+func summaryMessages(v interface{}) map[string]map[string]string {
+	messages := make(map[string]map[string]string)
+	switch m := v.(type) {
+	case map[string]map[string]string:
+		return m
+	case map[string]interface{}:
+		for summary, langs := range m {
+			if langMessages, ok := langs.(map[string]interface{}); ok {
+				messages[summary] = make(map[string]string, len(langMessages))
+				for lang, message := range langMessages {
+					messages[summary][lang] = fmt.Sprint(message)
+				}
+			}
+		}
+	}
+	return messages
+}
+
+func (g *Generator) errors(operations []operation) []byte {
+	list := statusErrors(operations)
+
+	b := &bytes.Buffer{}
+	b.WriteString(header)
+	b.WriteString("// StatusErrorCodes 接口声明的状态错误码\n")
+	b.WriteString("export const StatusErrorCodes = {\n")
+	for _, e := range list {
+		fmt.Fprintf(b, "  %s: %d,\n", propertyKey(e.key), e.code)
+	}
+	b.WriteString("} as const;\n\n")
+	b.WriteString("export type StatusErrorKey = keyof typeof StatusErrorCodes;\n\n")
+
+	b.WriteString("// StatusErrorMessages 状态错误的多语言消息\n")
+	b.WriteString("export const StatusErrorMessages: Record<StatusErrorKey, Record<string, string>> = {\n")
+	for _, e := range list {
+		langs := make([]string, 0, len(e.messages))
+		for lang := range e.messages {
+			langs = append(langs, lang)
+		}
+		sort.Strings(langs)
+		fields := make([]string, 0, len(langs))
+		for _, lang := range langs {
+			fields = append(fields, fmt.Sprintf("%s: %s", propertyKey(lang), literal(e.messages[lang])))
+		}
+		if len(fields) == 0 {
+			fmt.Fprintf(b, "  %s: {},\n", propertyKey(e.key))
+			continue
+		}
+		fmt.Fprintf(b, "  %s: { %s },\n", propertyKey(e.key), strings.Join(fields, ", "))
+	}
+	b.WriteString("};\n\n")
+
+	b.WriteString(`// statusErrorMessage 返回状态错误在指定语言下的消息
+export function statusErrorMessage(key: string, lang: string): string | undefined {
+  return (StatusErrorMessages as Record<string, Record<string, string> | undefined>)[key]?.[lang];
+}
+`)
+	return b.Bytes()
+}
@@ -0,0 +1,186 @@
+package tsclient
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shrewx/ginx/pkg/openapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSpec = `{
+  "openapi": "3.0.3",
+  "info": {"title": "", "version": ""},
+  "paths": {
+    "/v1/users/{id}": {
+      "get": {
+        "operationId": "GetUser",
+        "summary": "获取用户",
+        "parameters": [
+          {"name": "id", "in": "path", "required": true, "schema": {"type": "integer", "format": "uint64"}},
+          {"name": "verbose", "in": "query", "schema": {"type": "boolean"}},
+          {"name": "X-Trace-Id", "in": "header", "schema": {"type": "string"}}
+        ],
+        "responses": {
+          "200": {"description": "", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}},
+          "404": {
+            "description": "",
+            "x-status-errors": ["[NotFound][40400000001]"],
+            "x-status-error-messages": {"[NotFound][40400000001]": {"en": "not found", "zh": "资源未找到"}}
+          }
+        }
+      }
+    },
+    "/v1/users": {
+      "post": {
+        "operationId": "CreateUser",
+        "requestBody": {"content": {"multipart/form-data": {"schema": {"type": "object", "properties": {"avatar": {"type": "string", "format": "binary"}}}}}},
+        "responses": {"204": {"description": ""}}
+      }
+    },
+    "/v1/version": {
+      "get": {
+        "operationId": "Version",
+        "responses": {"200": {"description": "", "content": {"text/plain": {"schema": {"type": "string"}}}}}
+      }
+    },
+    "/openapi": {
+      "get": {"operationId": "OpenAPI", "responses": {"200": {"description": ""}}}
+    }
+  },
+  "components": {
+    "schemas": {
+      "Role": {"type": "string", "enum": ["ADMIN", "GUEST"], "x-enum-labels": {"ADMIN": "管理员", "GUEST": "访客"}},
+      "Level": {"type": "integer", "enum": [1, 2]},
+      "Base": {"type": "object", "properties": {"id": {"type": "integer", "description": "主键"}}},
+      "User": {
+        "allOf": [
+          {"$ref": "#/components/schemas/Base"},
+          {
+            "type": "object",
+            "properties": {
+              "name": {"type": "string"},
+              "role": {"allOf": [{"$ref": "#/components/schemas/Role"}, {"x-go-field-name": "Role"}]},
+              "tags": {"type": "array", "items": {"type": "string"}, "x-tag-json": "tags,omitempty"},
+              "attrs": {"type": "object", "additionalProperties": {"type": "integer"}},
+              "deletedAt": {"type": "string", "format": "date-time", "nullable": true}
+            }
+          }
+        ]
+      }
+    }
+  }
+}`
+
+func newTestGenerator(t *testing.T) *Generator {
+	spec, err := openapi.UnmarshalSpec([]byte(testSpec))
+	require.NoError(t, err)
+	return NewGenerator("user", spec)
+}
+
+func TestGenerator_Output(t *testing.T) {
+	dir := t.TempDir()
+	newTestGenerator(t).Output(dir)
+
+	read := func(name string) string {
+		data, err := os.ReadFile(filepath.Join(dir, "client-user", name))
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	types := read("types.ts")
+	assert.Contains(t, types, "export type Role = \"ADMIN\" | \"GUEST\";")
+	assert.Contains(t, types, "export const RoleValues: Role[] = [\"ADMIN\", \"GUEST\"];")
+	assert.Contains(t, types, "export const RoleLabels: Record<Role, string> = {\n  ADMIN: \"管理员\",\n  GUEST: \"访客\",\n};")
+	assert.Contains(t, types, "export type Level = 1 | 2;")
+	assert.NotContains(t, types, "LevelLabels")
+	assert.Contains(t, types, "export interface Base {\n  /** 主键 */\n  id: number;\n}")
+	assert.Contains(t, types, "export type User = Base & { attrs: Record<string, number>; deletedAt: string | null; name: string; role: Role; tags?: string[]; };")
+	assert.Contains(t, types, "export interface GetUserRequest {\n  id: number;\n  verbose?: boolean;\n  \"X-Trace-Id\"?: string;\n}")
+	assert.Contains(t, types, "export interface CreateUserRequest {\n  body: { avatar: Blob; };\n}")
+
+	errors := read("errors.ts")
+	assert.Contains(t, errors, "  NotFound: 40400000001,\n")
+	assert.Contains(t, errors, "  NotFound: { en: \"not found\", zh: \"资源未找到\" },\n")
+
+	client := read("client.ts")
+	assert.Contains(t, client, "import type { CreateUserRequest, GetUserRequest, User } from \"./types\";")
+	assert.Contains(t, client, "export class ClientUser {")
+	assert.Contains(t, client, "   * 获取用户\n   * @throws {StatusError} NotFound 40400000001\n")
+	assert.Contains(t, client, "  getUser(req: GetUserRequest, init?: RequestInit): Promise<User> {")
+	assert.Contains(t, client, "        pathParams: { id: req.id },\n        query: { verbose: req.verbose },\n        headers: { \"X-Trace-Id\": req[\"X-Trace-Id\"] },\n")
+	assert.Contains(t, client, "  createUser(req: CreateUserRequest, init?: RequestInit): Promise<void> {")
+	assert.Contains(t, client, "        bodyType: \"multipart\",\n        responseType: \"none\",\n")
+	assert.Contains(t, client, "  version(init?: RequestInit): Promise<string> {")
+	assert.NotContains(t, client, "openAPI(")
+
+	assert.Contains(t, read("runtime.ts"), "export const LangHeader = \"GinX-Lang-Header\";")
+	assert.Contains(t, read("index.ts"), "export * from \"./client\";")
+}
+
+func TestGenerator_Check(t *testing.T) {
+	dir := t.TempDir()
+	g := newTestGenerator(t)
+	assert.Len(t, g.Check(dir), 5)
+
+	g.Output(dir)
+	assert.Empty(t, g.Check(dir))
+
+	filename := filepath.Join(dir, "client-user", "types.ts")
+	require.NoError(t, os.WriteFile(filename, []byte("// stale"), 0644))
+	assert.Equal(t, []string{filename}, g.Check(dir))
+}
+
+const multiSourceSpec = `{
+  "openapi": "3.0.3",
+  "info": {"title": "", "version": ""},
+  "paths": {
+    "/v1/files/{name}": {
+      "get": {
+        "operationId": "GetFile",
+        "parameters": [
+          {"name": "name", "in": "path", "required": true, "schema": {"type": "string"}, "x-param-sources": ["path", "query"]},
+          {"name": "name", "in": "query", "schema": {"type": "string"}, "x-param-sources": ["path", "query"]},
+          {"name": "token", "in": "header", "schema": {"type": "string"}, "x-param-sources": ["header", "query", "cookies"]},
+          {"name": "token", "in": "query", "schema": {"type": "string"}, "x-param-sources": ["header", "query", "cookies"]},
+          {"name": "token", "in": "cookie", "schema": {"type": "string"}, "x-param-sources": ["header", "query", "cookies"]}
+        ],
+        "responses": {"204": {"description": ""}}
+      }
+    },
+    "/v1/files": {
+      "delete": {
+        "operationId": "DeleteFile",
+        "parameters": [
+          {"name": "name", "in": "path", "required": true, "schema": {"type": "string"}, "x-param-sources": ["path", "query"]},
+          {"name": "name", "in": "query", "schema": {"type": "string"}, "x-param-sources": ["path", "query"]}
+        ],
+        "responses": {"204": {"description": ""}}
+      }
+    }
+  }
+}`
+
+func TestGenerator_MultiSourceParams(t *testing.T) {
+	spec, err := openapi.UnmarshalSpec([]byte(multiSourceSpec))
+	require.NoError(t, err)
+	dir := t.TempDir()
+	NewGenerator("file", spec).Output(dir)
+
+	read := func(name string) string {
+		data, err := os.ReadFile(filepath.Join(dir, "client-file", name))
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	// 多个来源的参数只生成优先级最高的来源，避免重复的属性
+	types := read("types.ts")
+	assert.Contains(t, types, "export interface GetFileRequest {\n  name: string;\n  token?: string;\n}")
+	assert.Contains(t, types, "export interface DeleteFileRequest {\n  name?: string;\n}")
+
+	client := read("client.ts")
+	assert.Contains(t, client, "        pathParams: { name: req.name },\n        headers: { token: req.token },\n")
+	assert.NotContains(t, client, "query:")
+}
@@ -0,0 +1,8 @@
+package tsclient
+
+import (
+	_ "embed"
+)
+
+//go:embed template/runtime.ts
+var TplRuntime string
@@ -0,0 +1,279 @@
+package tsclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-courier/codegen"
+	"github.com/go-courier/oas"
+	"github.com/shrewx/ginx/pkg/openapi"
+)
+
+var reIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// typeName 组件名转换为 TypeScript 类型名
+func typeName(id string) string {
+	if reIdentifier.MatchString(id) {
+		return id
+	}
+	return codegen.UpperCamelCase(id)
+}
+
+// propertyKey 属性名不是合法标识符时加引号
+func propertyKey(name string) string {
+	if reIdentifier.MatchString(name) {
+		return name
+	}
+	return literal(name)
+}
+
+// propertyAccess 访问对象的属性
+func propertyAccess(object string, name string) string {
+	if reIdentifier.MatchString(name) {
+		return object + "." + name
+	}
+	return object + "[" + literal(name) + "]"
+}
+
+func literal(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// comment 生成单行 JSDoc，枚举等生成的 markdown 描述会被忽略
+func comment(indent string, desc string) string {
+	desc = strings.TrimSpace(desc)
+	if desc == "" || strings.HasPrefix(desc, ">") {
+		return ""
+	}
+	desc = strings.Join(strings.Fields(desc), " ")
+	return fmt.Sprintf("%s/** %s */\n", indent, strings.ReplaceAll(desc, "*/", "*\\/"))
+}
+
+func refID(refer oas.Refer) string {
+	switch ref := refer.(type) {
+	case *oas.ComponentRefer:
+		return ref.ID
+	case oas.ComponentRefer:
+		return ref.ID
+	}
+	return refer.RefString()[strings.LastIndex(refer.RefString(), "/")+1:]
+}
+
+// isEmptySchema 只包含描述与扩展的 schema，如引用类型属性的 allOf 中附带的字段信息
+func isEmptySchema(schema *oas.Schema) bool {
+	return schema == nil || (schema.Refer == nil && schema.Type == "" && len(schema.Properties) == 0 &&
+		schema.AdditionalProperties == nil && schema.Items == nil && len(schema.Enum) == 0 &&
+		len(schema.AllOf) == 0 && len(schema.AnyOf) == 0 && len(schema.OneOf) == 0)
+}
+
+// enumLabels 返回枚举值对应的描述
+func enumLabels(schema *oas.Schema) map[string]string {
+	labels := make(map[string]string)
+	switch v := schema.Extensions[openapi.XEnumLabels].(type) {
+	case map[string]string:
+		labels = v
+	case map[string]interface{}:
+		for k, label := range v {
+			labels[k] = fmt.Sprint(label)
+		}
+	}
+	return labels
+}
+
+// enumLiterals 返回去重后的枚举字面量
+func enumLiterals(schema *oas.Schema) []string {
+	seen := make(map[string]bool, len(schema.Enum))
+	values := make([]string, 0, len(schema.Enum))
+	for _, v := range schema.Enum {
+		if l := literal(v); !seen[l] {
+			seen[l] = true
+			values = append(values, l)
+		}
+	}
+	return values
+}
+
+// tsType 返回 schema 对应的 TypeScript 类型
+func tsType(schema *oas.Schema) string {
+	if schema == nil {
+		return "unknown"
+	}
+
+	t := baseType(schema)
+	if schema.Nullable && t != "unknown" {
+		return t + " | null"
+	}
+	return t
+}
+
+func baseType(schema *oas.Schema) string {
+	if schema.Refer != nil {
+		return typeName(refID(schema.Refer))
+	}
+
+	if len(schema.Enum) > 0 {
+		return strings.Join(enumLiterals(schema), " | ")
+	}
+
+	if len(schema.AllOf) > 0 {
+		return compose(schema.AllOf, " & ", schema)
+	}
+	if len(schema.OneOf) > 0 {
+		return compose(schema.OneOf, " | ", nil)
+	}
+	if len(schema.AnyOf) > 0 {
+		return compose(schema.AnyOf, " | ", nil)
+	}
+
+	switch schema.Type {
+	case oas.TypeString:
+		if schema.Format == "binary" {
+			return "Blob"
+		}
+		return "string"
+	case oas.TypeInteger, oas.TypeNumber:
+		return "number"
+	case oas.TypeBoolean:
+		return "boolean"
+	case oas.TypeArray:
+		item := tsType(schema.Items)
+		if strings.ContainsAny(item, " ") {
+			return "(" + item + ")[]"
+		}
+		return item + "[]"
+	case oas.TypeObject, "":
+		if len(schema.Properties) > 0 {
+			return objectType(schema, "")
+		}
+		if schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil {
+			return "Record<string, " + tsType(schema.AdditionalProperties.Schema) + ">"
+		}
+		if schema.Type == oas.TypeObject {
+			return "Record<string, unknown>"
+		}
+	}
+	return "unknown"
+}
+
+// compose 组合多个 schema，忽略只包含描述的部分
+func compose(schemas []*oas.Schema, sep string, self *oas.Schema) string {
+	parts := make([]string, 0, len(schemas)+1)
+	for _, s := range schemas {
+		if isEmptySchema(s) {
+			continue
+		}
+		t := tsType(s)
+		if strings.Contains(t, " | ") && !strings.HasPrefix(t, "{") {
+			t = "(" + t + ")"
+		}
+		parts = append(parts, t)
+	}
+	if self != nil && len(self.Properties) > 0 {
+		parts = append(parts, objectType(self, ""))
+	}
+	if len(parts) == 0 {
+		return "unknown"
+	}
+	return strings.Join(parts, sep)
+}
+
+// objectType 生成对象类型的成员，indent 为空时生成单行类型
+func objectType(schema *oas.Schema, indent string) string {
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	b := &strings.Builder{}
+	if indent == "" {
+		b.WriteString("{ ")
+		for _, name := range names {
+			prop := schema.Properties[name]
+			fmt.Fprintf(b, "%s%s: %s; ", propertyKey(name), optionalMark(schema, name, prop), tsType(prop))
+		}
+		b.WriteString("}")
+		return b.String()
+	}
+
+	b.WriteString("{\n")
+	for _, name := range names {
+		prop := schema.Properties[name]
+		b.WriteString(comment(indent, description(prop)))
+		fmt.Fprintf(b, "%s%s%s: %s;\n", indent, propertyKey(name), optionalMark(schema, name, prop), tsType(prop))
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// optionalMark 必填或 JSON 中总会输出的字段为必选属性，omitempty 的字段为可选属性
+func optionalMark(schema *oas.Schema, name string, prop *oas.Schema) string {
+	for _, required := range schema.Required {
+		if required == name {
+			return ""
+		}
+	}
+	if jsonTag, ok := extension(prop, openapi.XTagJSON); ok && strings.Contains(jsonTag, "omitempty") {
+		return "?"
+	}
+	return ""
+}
+
+// extension 读取 schema 或 allOf 中附带的字符串扩展
+func extension(schema *oas.Schema, key string) (string, bool) {
+	if schema == nil {
+		return "", false
+	}
+	if v, ok := schema.Extensions[key].(string); ok {
+		return v, true
+	}
+	for _, s := range schema.AllOf {
+		if v, ok := extension(s, key); ok {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// description 读取 schema 或 allOf 中附带的描述
+func description(schema *oas.Schema) string {
+	if schema == nil {
+		return ""
+	}
+	if schema.Description != "" {
+		return schema.Description
+	}
+	for _, s := range schema.AllOf {
+		if isEmptySchema(s) && s != nil && s.Description != "" {
+			return s.Description
+		}
+	}
+	return ""
+}
+
+// collectRefs 收集 schema 引用的组件类型名
+func collectRefs(schema *oas.Schema, refs map[string]bool) {
+	if schema == nil {
+		return
+	}
+	if schema.Refer != nil {
+		refs[typeName(refID(schema.Refer))] = true
+		return
+	}
+	collectRefs(schema.Items, refs)
+	if schema.AdditionalProperties != nil {
+		collectRefs(schema.AdditionalProperties.Schema, refs)
+	}
+	for _, s := range schema.Properties {
+		collectRefs(s, refs)
+	}
+	for _, list := range [][]*oas.Schema{schema.AllOf, schema.AnyOf, schema.OneOf} {
+		for _, s := range list {
+			collectRefs(s, refs)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package ginx
+
+import (
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	ginbinding "github.com/gin-gonic/gin/binding"
+	"github.com/pkg/errors"
+	e2 "github.com/shrewx/ginx/internal/errors"
+	"github.com/shrewx/ginx/internal/fields"
+	"github.com/shrewx/ginx/pkg/statuserror"
+)
+
+// bodyMediaTypes body 参数支持解析的请求体类型，未设置 Content-Type 时按 JSON 解析
+var bodyMediaTypes = []string{
+	MineApplicationJson,
+	"application/*+json",
+	MineApplicationXML,
+	"text/xml",
+	"application/*+xml",
+	MineApplicationYaml,
+	"application/yaml",
+	MineApplicationToml,
+}
+
+// requestMediaType 请求的媒体类型，不包含参数，无法解析时返回原值
+func requestMediaType(req *http.Request) string {
+	contentType := req.Header.Get("Content-Type")
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mediaType
+	}
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
+
+// matchMediaType 媒体类型是否匹配，accepts 支持 */*、image/* 与 application/*+json 形式
+func matchMediaType(accepts []string, mediaType string) bool {
+	typ, subtype, ok := strings.Cut(mediaType, "/")
+	if !ok {
+		return false
+	}
+	for _, accept := range accepts {
+		accept, _, _ = strings.Cut(accept, ";")
+		acceptType, acceptSubtype, _ := strings.Cut(strings.ToLower(strings.TrimSpace(accept)), "/")
+		if acceptType != "*" && acceptType != typ {
+			continue
+		}
+		if acceptSubtype == "*" || acceptSubtype == subtype {
+			return true
+		}
+		if suffix, ok := strings.CutPrefix(acceptSubtype, "*"); ok && strings.HasSuffix(subtype, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// unsupportedMediaType 415 错误，错误信息中列出支持的类型
+func unsupportedMediaType(accepts []string) statuserror.CommonError {
+	return e2.UnsupportedMediaType.WithField(fields.AcceptMediaTypes, strings.Join(accepts, ", "))
+}
+
+// hasRequestBody 请求是否包含请求体，长度未知时视为包含
+func hasRequestBody(req *http.Request) bool {
+	return req.Body != nil && req.Body != http.NoBody && req.ContentLength != 0
+}
+
+// checkContentType 校验请求体类型是否为操作符通过 Consumes 声明的类型，不符合时返回 415
+func checkContentType(ctx *gin.Context, operator interface{}) error {
+	describer, ok := operator.(ConsumesDescriber)
+	if !ok || !hasRequestBody(ctx.Request) {
+		return nil
+	}
+
+	accepts := describer.Consumes()
+	if len(accepts) > 0 && !matchMediaType(accepts, requestMediaType(ctx.Request)) {
+		return unsupportedMediaType(accepts)
+	}
+	return nil
+}
+
+// gzipBody 解压后的请求体，关闭时同时关闭原请求体
+type gzipBody struct {
+	*gzip.Reader
+	body io.Closer
+}
+
+func (b *gzipBody) Close() error {
+	_ = b.Reader.Close()
+	return b.body.Close()
+}
+
+// decodeContentEncoding 解压 gzip 编码的请求体，解压后的大小同样受请求体大小限制，不支持的编码返回 415
+func decodeContentEncoding(ctx *gin.Context, operator interface{}) error {
+	switch strings.ToLower(strings.TrimSpace(ctx.GetHeader("Content-Encoding"))) {
+	case "", "identity":
+		return nil
+	case "gzip", "x-gzip":
+	default:
+		ctx.Header("Accept-Encoding", "gzip")
+		return e2.UnsupportedMediaType.WithField(fields.AcceptEncodings, "gzip")
+	}
+
+	req := ctx.Request
+	req.Header.Del("Content-Encoding")
+	req.Header.Del("Content-Length")
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+
+	reader, err := gzip.NewReader(req.Body)
+	if err == io.EOF {
+		req.Body, req.ContentLength = http.NoBody, 0
+		return nil
+	}
+	if err != nil {
+		// 请求体过大返回 413，其他为无效的 gzip 数据
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return e2.RequestEntityTooLarge
+		}
+		return e2.BadRequest
+	}
+
+	var body io.ReadCloser = &gzipBody{Reader: reader, body: req.Body}
+	if limit := bodyLimit(operator); limit > 0 {
+		body = http.MaxBytesReader(ctx.Writer, body, limit)
+	}
+	req.Body, req.ContentLength = body, -1
+	return nil
+}
+
+// requestBody 读取请求体，读取后缓存在 ctx 中，多个 body 参数或业务中的 ShouldBindBodyWith 不再重复读取
+func requestBody(ctx *gin.Context) ([]byte, error) {
+	if cached, ok := ctx.Get(gin.BodyBytesKey); ok {
+		if body, ok := cached.([]byte); ok {
+			return body, nil
+		}
+	}
+	if ctx.Request.Body == nil {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		return nil, err
+	}
+	ctx.Set(gin.BodyBytesKey, body)
+	return body, nil
+}
+
+// bodyBinding 根据媒体类型选择请求体的解析方式
+func bodyBinding(mediaType string) (ginbinding.BindingBody, bool) {
+	switch {
+	case mediaType == "", matchMediaType([]string{MineApplicationJson, "application/*+json"}, mediaType):
+		return ginbinding.JSON, true
+	case matchMediaType([]string{MineApplicationXML, "text/xml", "application/*+xml"}, mediaType):
+		return ginbinding.XML, true
+	case matchMediaType([]string{MineApplicationYaml, "application/yaml"}, mediaType):
+		return ginbinding.YAML, true
+	case mediaType == MineApplicationToml:
+		return ginbinding.TOML, true
+	}
+	return nil, false
+}
+
+// bindRequestBody 按 Content-Type 解析请求体，不支持的类型返回 415
+func bindRequestBody(ctx *gin.Context, obj interface{}) error {
+	b, ok := bodyBinding(requestMediaType(ctx.Request))
+	if !ok {
+		return unsupportedMediaType(bodyMediaTypes)
+	}
+
+	body, err := requestBody(ctx)
+	if err != nil {
+		return err
+	}
+	return b.BindBody(body, obj)
+}
@@ -0,0 +1,160 @@
+package ginx
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	ginbinding "github.com/gin-gonic/gin/binding"
+	"github.com/shrewx/ginx/pkg/conf"
+	"github.com/shrewx/ginx/pkg/i18nx"
+	"github.com/shrewx/ginx/pkg/statuserror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBodyOnceOperator 多个 body 参数共用同一个请求体
+type TestBodyOnceOperator struct {
+	MethodPost
+	Data TestOperatorBody       `in:"body"`
+	Raw  map[string]interface{} `in:"body"`
+}
+
+func (t *TestBodyOnceOperator) Path() string { return "/api/body" }
+
+func (t *TestBodyOnceOperator) Output(ctx *gin.Context) (interface{}, error) {
+	// 业务中再次解析请求体时使用缓存
+	var data TestOperatorBody
+	if err := ctx.ShouldBindBodyWith(&data, ginbinding.JSON); err != nil {
+		return nil, err
+	}
+	return []interface{}{t.Data.Title, t.Raw["content"], data.Title}, nil
+}
+
+// TestConsumesOperator 声明接受的请求体类型
+type TestConsumesOperator struct {
+	MethodPost
+	Data TestOperatorBody `in:"body"`
+}
+
+func (t *TestConsumesOperator) Path() string { return "/api/consumes" }
+func (t *TestConsumesOperator) Consumes() []string {
+	return []string{MineApplicationXML, "application/*+json"}
+}
+func (t *TestConsumesOperator) BodyLimit() int64 { return 64 }
+
+func (t *TestConsumesOperator) Output(ctx *gin.Context) (interface{}, error) {
+	return t.Data.Title, nil
+}
+
+func TestBindRequestBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	i18nx.Load(&conf.I18N{Langs: []string{"zh", "en"}})
+
+	engine := gin.New()
+	engine.POST("/api/body", ginHandleFuncWrapper(&TestBodyOnceOperator{}))
+	engine.POST("/api/consumes", ginHandleFuncWrapper(&TestConsumesOperator{}))
+
+	serve := func(path string, header map[string]string, body io.Reader) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, path, body)
+		for key, value := range header {
+			req.Header.Set(key, value)
+		}
+		engine.ServeHTTP(w, req)
+		return w
+	}
+	statusErr := func(w *httptest.ResponseRecorder) statuserror.StatusErr {
+		var resp statuserror.StatusErr
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp
+	}
+	gzipped := func(s string) io.Reader {
+		buf := &bytes.Buffer{}
+		writer := gzip.NewWriter(buf)
+		_, _ = writer.Write([]byte(s))
+		_ = writer.Close()
+		return buf
+	}
+
+	t.Run("read once", func(t *testing.T) {
+		for _, contentType := range []string{"", "application/json; charset=utf-8"} {
+			w := serve("/api/body", map[string]string{"Content-Type": contentType}, strings.NewReader(`{"title":"a","content":"b"}`))
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			assert.JSONEq(t, `["a","b","a"]`, w.Body.String())
+		}
+	})
+
+	t.Run("unsupported media type", func(t *testing.T) {
+		for lang, message := range map[string]string{
+			"zh": "不支持的媒体类型\n>> 支持的类型:application/json, application/*+json, application/xml, text/xml, application/*+xml, application/x-yaml, application/yaml, application/toml",
+			"en": "unsupported media type\n>> acceptable media types:application/json, application/*+json, application/xml, text/xml, application/*+xml, application/x-yaml, application/yaml, application/toml",
+		} {
+			w := serve("/api/body", map[string]string{"Content-Type": "text/plain", CurrentLangHeader(): lang}, strings.NewReader(`{}`))
+			require.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+
+			resp := statusErr(w)
+			assert.Equal(t, int64(41500000001), resp.Code())
+			assert.Equal(t, message, resp.Message)
+		}
+	})
+
+	t.Run("consumes", func(t *testing.T) {
+		w := serve("/api/consumes", map[string]string{"Content-Type": MineApplicationXML}, strings.NewReader(`<body><Title>xml</Title></body>`))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, `"xml"`, w.Body.String())
+
+		w = serve("/api/consumes", map[string]string{"Content-Type": MineApplicationMergePatch}, strings.NewReader(`{"title":"json"}`))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		// 未声明的类型与缺少 Content-Type 的请求体均返回 415
+		for _, contentType := range []string{MineApplicationJson, ""} {
+			w = serve("/api/consumes", map[string]string{"Content-Type": contentType, CurrentLangHeader(): "en"}, strings.NewReader(`{"title":"json"}`))
+			require.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+			assert.Equal(t, "unsupported media type\n>> acceptable media types:application/xml, application/*+json", statusErr(w).Message)
+		}
+	})
+
+	t.Run("gzip", func(t *testing.T) {
+		w := serve("/api/body", map[string]string{"Content-Type": MineApplicationJson, "Content-Encoding": "gzip"}, gzipped(`{"title":"a","content":"b"}`))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.JSONEq(t, `["a","b","a"]`, w.Body.String())
+
+		// 解压后超过大小限制
+		large := `{"title":"` + strings.Repeat("a", 128) + `"}`
+		w = serve("/api/consumes", map[string]string{"Content-Type": MineApplicationXML, "Content-Encoding": "gzip"}, gzipped(large))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+		w = serve("/api/body", map[string]string{"Content-Type": MineApplicationJson, "Content-Encoding": "gzip"}, strings.NewReader(`{}`))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("unsupported encoding", func(t *testing.T) {
+		w := serve("/api/body", map[string]string{"Content-Type": MineApplicationJson, "Content-Encoding": "br", CurrentLangHeader(): "en"}, strings.NewReader(`{}`))
+		require.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+		assert.Equal(t, "gzip", w.Header().Get("Accept-Encoding"))
+		assert.Equal(t, "unsupported media type\n>> acceptable encodings:gzip", statusErr(w).Message)
+	})
+}
+
+func TestMatchMediaType(t *testing.T) {
+	accepts := []string{"image/*", "application/*+json", "text/plain; charset=utf-8"}
+
+	for mediaType, expected := range map[string]bool{
+		"image/png":                    true,
+		"application/merge-patch+json": true,
+		"text/plain":                   true,
+		"application/json":             false,
+		"text/html":                    false,
+		"":                             false,
+	} {
+		assert.Equal(t, expected, matchMediaType(accepts, mediaType), mediaType)
+	}
+	assert.True(t, matchMediaType([]string{"*/*"}, "video/mp4"))
+}
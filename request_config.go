@@ -1,21 +1,22 @@
 package ginx
 
 import (
+	"io"
 	"net"
 	"net/http"
+	"sync"
 	"time"
-
-	"golang.org/x/net/http2"
 )
 
 // ClientConfig 客户端连接配置
-// 用于配置底层 HTTP 连接，长期有效
+// 用于配置底层 HTTP 连接，长期有效，通过 WithClientConfig 应用到请求配置
 type ClientConfig struct {
-	Protocol  string          // http/https
-	Host      string          // 主机地址
-	Port      uint16          // 端口号
-	Timeout   time.Duration   // 默认超时（可被请求配置覆盖）
-	Transport *http.Transport // 自定义 Transport（可被 RequestConfig.Transport 覆盖）
+	Protocol        string            // http/https
+	Host            string            // 主机地址
+	Port            uint16            // 端口号
+	Timeout         time.Duration     // 默认超时（可被请求配置覆盖）
+	Transport       http.RoundTripper // 自定义 Transport（可被 RequestConfig.Transport 覆盖）
+	MaxConnsPerHost int               // 默认 Transport 的单主机最大连接数，0 表示不限制
 }
 
 // NewClientConfig 创建新的客户端配置
@@ -32,15 +33,26 @@ func NewClientConfig(host string) ClientConfig {
 // RequestConfig 请求配置
 // 单次请求的配置，可以覆盖和添加 ClientConfig 中的配置
 type RequestConfig struct {
-	Schema     string
-	Host       string
-	Port       uint16
-	Path       string
-	Headers    map[string]string
-	Cookies    []*http.Cookie
-	Timeout    *time.Duration  // 覆盖 ClientConfig.Timeout
-	Transport  *http.Transport // 覆盖 ClientConfig.Transport
-	InvokeMode *InvokeMode
+	Schema      string
+	Host        string
+	Port        uint16
+	ServiceName string // 设置后通过 RegisterResolver 注册的负载均衡器选择实例，覆盖 Host/Port
+	Path        string
+	Headers     map[string]string
+	Cookies     []*http.Cookie
+	Timeout     *time.Duration    // 覆盖 ClientConfig.Timeout
+	Transport   http.RoundTripper // 覆盖 ClientConfig.Transport
+	InvokeMode  *InvokeMode
+
+	MaxConnsPerHost int // 默认 Transport 的单主机最大连接数，设置了 Transport 时无效
+
+	Retry          *RetryPolicy    // 重试策略，为 nil 时不重试
+	CircuitBreaker *CircuitBreaker // 熔断器，为 nil 时不熔断
+
+	Interceptors []ClientInterceptor // 拦截器，合并时默认配置的拦截器先执行
+
+	ResponseMeta   *ResponseMeta // 不为 nil 时填充响应元信息，仅对单次请求有效
+	ResponseWriter io.Writer     // 不为 nil 时成功响应体写入该 Writer，仅对单次请求有效
 }
 
 // NewRequestConfig 创建默认的请求配置
@@ -88,10 +100,27 @@ func (rc *RequestConfig) Merge(other *RequestConfig) {
 		rc.Transport = other.Transport
 	}
 
+	if rc.MaxConnsPerHost == 0 && other.MaxConnsPerHost != 0 {
+		rc.MaxConnsPerHost = other.MaxConnsPerHost
+	}
+
 	// InvokeMode: 如果请求级未设置，使用默认值
 	if rc.InvokeMode == nil && other.InvokeMode != nil {
 		rc.InvokeMode = other.InvokeMode
 	}
+	if rc.Retry == nil && other.Retry != nil {
+		rc.Retry = other.Retry
+	}
+
+	if rc.CircuitBreaker == nil && other.CircuitBreaker != nil {
+		rc.CircuitBreaker = other.CircuitBreaker
+	}
+
+	// Interceptors: 默认拦截器在请求级拦截器之前执行
+	if len(other.Interceptors) > 0 {
+		rc.Interceptors = append(append([]ClientInterceptor{}, other.Interceptors...), rc.Interceptors...)
+	}
+
 	if rc.Schema == "" && other.Schema != "" {
 		rc.Schema = other.Schema
 	}
@@ -104,6 +133,10 @@ func (rc *RequestConfig) Merge(other *RequestConfig) {
 		rc.Port = other.Port
 	}
 
+	if rc.ServiceName == "" && other.ServiceName != "" {
+		rc.ServiceName = other.ServiceName
+	}
+
 	if rc.Path == "" && other.Path != "" {
 		rc.Path = other.Path
 	}
@@ -146,6 +179,13 @@ func WithMode(mode InvokeMode) RequestOption {
 	}
 }
 
+// WithServiceName 通过服务发现调用指定服务
+func WithServiceName(name string) RequestOption {
+	return func(rc *RequestConfig) {
+		rc.ServiceName = name
+	}
+}
+
 // WithAuthorization 添加 Authorization Header
 func WithAuthorization(token string) RequestOption {
 	return WithHeader("Authorization", token)
@@ -161,13 +201,46 @@ func WithContentType(contentType string) RequestOption {
 	return WithHeader("Content-Type", contentType)
 }
 
-// WithTransport 设置 Transport
-func WithTransport(transport *http.Transport) RequestOption {
+// WithTransport 设置 Transport，可传入 otelhttp 等包装过的 http.RoundTripper
+// 自定义 Transport 会被直接复用，连接池由调用方管理
+func WithTransport(transport http.RoundTripper) RequestOption {
 	return func(rc *RequestConfig) {
 		rc.Transport = transport
 	}
 }
 
+// WithMaxConnsPerHost 设置默认 Transport 的单主机最大连接数
+func WithMaxConnsPerHost(n int) RequestOption {
+	return func(rc *RequestConfig) {
+		rc.MaxConnsPerHost = n
+	}
+}
+
+// WithClientConfig 使用 ClientConfig 设置请求的地址、超时与 Transport，ClientConfig 中未设置的字段不生效
+func WithClientConfig(config ClientConfig) RequestOption {
+	return func(rc *RequestConfig) {
+		if config.Protocol != "" {
+			rc.Schema = config.Protocol
+		}
+		if config.Host != "" {
+			rc.Host = config.Host
+		}
+		if config.Port != 0 {
+			rc.Port = config.Port
+		}
+		if config.Timeout > 0 {
+			timeout := config.Timeout
+			rc.Timeout = &timeout
+		}
+		if config.Transport != nil {
+			rc.Transport = config.Transport
+		}
+		if config.MaxConnsPerHost != 0 {
+			rc.MaxConnsPerHost = config.MaxConnsPerHost
+		}
+	}
+}
+
 // ApplyRequestConfig 将 RequestConfig 应用到 HTTP 请求
 func applyRequestConfig(req *http.Request, config RequestConfig) {
 	// 应用 Headers
@@ -181,70 +254,51 @@ func applyRequestConfig(req *http.Request, config RequestConfig) {
 	}
 }
 
-// getTimeout 获取最终的超时时间
-// 优先级：RequestConfig.Timeout > ClientConfig.Timeout > DefaultTimeout
-func getTimeout(clientConfig *ClientConfig, requestConfig *RequestConfig) *time.Duration {
-	// 1. 请求配置中的 Timeout（最高优先级）
-	if requestConfig != nil && requestConfig.Timeout != nil {
-		return requestConfig.Timeout
+// getHTTPClient 获取 HTTP Client
+// 自定义 Transport 直接使用；未设置时按单主机连接数复用进程内共享的连接池。
+// 超时通过 context 控制，不设置 http.Client.Timeout，避免截断流式响应体
+func getHTTPClient(transport http.RoundTripper, maxConnsPerHost int) *http.Client {
+	if transport != nil {
+		return &http.Client{Transport: transport}
 	}
 
-	// 2. ClientConfig 中的 Timeout
-	if clientConfig != nil && clientConfig.Timeout > 0 {
-		return &clientConfig.Timeout
+	if client, ok := httpClients.Load(maxConnsPerHost); ok {
+		return client.(*http.Client)
 	}
-
-	// 3. 默认超时
-	t := DefaultTimeout
-	return &t
+	client, _ := httpClients.LoadOrStore(maxConnsPerHost, &http.Client{
+		Transport: newDefaultTransport(maxConnsPerHost),
+	})
+	return client.(*http.Client)
 }
 
-// getTransport 获取最终的 Transport
-// 优先级：RequestConfig.Transport > ClientConfig.Transport > 默认 Transport
-func getTransport(clientConfig *ClientConfig, requestConfig *RequestConfig) *http.Transport {
-	// 1. RequestConfig 中的 Transport（最高优先级）
-	if requestConfig != nil && requestConfig.Transport != nil {
-		return requestConfig.Transport
-	}
-
-	// 2. ClientConfig 中的 Transport
-	if clientConfig != nil && clientConfig.Transport != nil {
-		return clientConfig.Transport
-	}
+// httpClients 按单主机最大连接数缓存使用默认 Transport 的 HTTP Client
+var httpClients sync.Map
 
-	// 3. 默认 Transport
-	return nil
-}
+const (
+	// DefaultMaxIdleConnsPerHost 默认 Transport 的单主机最大空闲连接数
+	DefaultMaxIdleConnsPerHost = 32
+	// DefaultIdleConnTimeout 默认 Transport 的空闲连接超时时间
+	DefaultIdleConnTimeout = 90 * time.Second
+)
 
-// GetHTTPClient 获取或创建 HTTP Client
-func getHTTPClient(timeout *time.Duration, transport *http.Transport) *http.Client {
-	if transport == nil {
-		transport = &http.Transport{
-			DialContext: (&net.Dialer{
-				Timeout:   5 * time.Second,
-				KeepAlive: 0,
-			}).DialContext,
-			DisableKeepAlives:     true,
-			TLSHandshakeTimeout:   5 * time.Second,
-			ResponseHeaderTimeout: 5 * time.Second,
-			ExpectContinueTimeout: 1 * time.Second,
-		}
-	} else {
-		transport = transport.Clone()
-	}
-	var t time.Duration
-	if timeout == nil {
-		t = DefaultTimeout
-	} else {
-		t = *timeout
+func newDefaultTransport(maxConnsPerHost int) *http.Transport {
+	maxIdleConnsPerHost := DefaultMaxIdleConnsPerHost
+	if maxConnsPerHost > 0 && maxConnsPerHost < maxIdleConnsPerHost {
+		maxIdleConnsPerHost = maxConnsPerHost
 	}
-
-	// 尝试配置 HTTP/2
-	_ = http2.ConfigureTransport(transport)
-
-	return &http.Client{
-		Timeout:   t,
-		Transport: transport,
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   5 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   maxIdleConnsPerHost,
+		MaxConnsPerHost:       maxConnsPerHost,
+		IdleConnTimeout:       DefaultIdleConnTimeout,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
 	}
 }
 
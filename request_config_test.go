@@ -0,0 +1,202 @@
+package ginx
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestGetHTTPClient_Pooling(t *testing.T) {
+	a := getHTTPClient(nil, 0)
+	b := getHTTPClient(nil, 0)
+	assert.Same(t, a, b, "clients with the same config should be reused")
+	assert.Zero(t, a.Timeout)
+
+	limited := getHTTPClient(nil, 4)
+	assert.NotSame(t, a, limited)
+	transport := limited.Transport.(*http.Transport)
+	assert.Equal(t, 4, transport.MaxConnsPerHost)
+	assert.Equal(t, 4, transport.MaxIdleConnsPerHost)
+
+	custom := roundTripperFunc(func(req *http.Request) (*http.Response, error) { return nil, nil })
+	assert.NotNil(t, getHTTPClient(custom, 0).Transport)
+}
+
+func TestInvoke_WithRoundTripper(t *testing.T) {
+	var called bool
+	fake := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		called = true
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader(`"fake"`)),
+			Request:    req,
+		}, nil
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "http://user-svc/users", nil)
+	var body string
+	err := Invoke(context.Background(), req, &body, &RequestConfig{Transport: fake}, &Client{}, nil)
+	require.NoError(t, err)
+	assert.True(t, called)
+	assert.Equal(t, "fake", body)
+}
+
+func TestInvoke_WithClientConfig(t *testing.T) {
+	var host string
+	fake := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		host = req.URL.Host
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader(`"fake"`)),
+			Request:    req,
+		}, nil
+	})
+
+	config := NewClientConfig("user-svc")
+	config.Port = 8080
+	config.Transport = fake
+	defaults := NewRequestConfig()
+	WithClientConfig(config)(defaults)
+	assert.Equal(t, "http", defaults.Schema)
+	require.NotNil(t, defaults.Timeout)
+	assert.Equal(t, DefaultTimeout, *defaults.Timeout)
+
+	var body string
+	err := Invoke(context.Background(), &struct{ MethodGet }{}, &body, defaults, &Client{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "user-svc:8080", host)
+	assert.Equal(t, "fake", body)
+
+	// 单次请求的 ClientConfig 覆盖默认配置
+	err = Invoke(context.Background(), &struct{ MethodGet }{}, &body, defaults, &Client{}, nil,
+		WithClientConfig(ClientConfig{Host: "order-svc"}))
+	require.NoError(t, err)
+	assert.Equal(t, "order-svc:8080", host)
+}
+
+func TestInvoke_TimeoutDoesNotCutStreamingBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		for i := 0; i < 3; i++ {
+			_, _ = w.Write([]byte("chunk"))
+			w.(http.Flusher).Flush()
+			time.Sleep(50 * time.Millisecond)
+		}
+	}))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	result, err := (&Client{}).Invoke(context.Background(), req, RequestConfig{Timeout: durationPtr(30 * time.Millisecond)})
+	require.NoError(t, err)
+
+	resp := result.(*Result).Response
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "chunkchunkchunk", string(data))
+}
+
+func TestInvoke_TimeoutBeforeHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	start := time.Now()
+	_, err := (&Client{}).Invoke(context.Background(), req, RequestConfig{Timeout: durationPtr(30 * time.Millisecond)})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+func TestInvoke_TimeoutTripsBreaker(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	cb := NewCircuitBreaker(WithFailureThreshold(2))
+	config := &RequestConfig{Timeout: durationPtr(20 * time.Millisecond), CircuitBreaker: cb}
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		err := Invoke(context.Background(), req, nil, config, &Client{}, nil)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	}
+	assert.Equal(t, CircuitOpen, cb.State(strings.TrimPrefix(server.URL, "http://")))
+}
+
+func TestInvoke_TimeoutBudget(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	// 剩余时间不足以等待退避时直接返回本次结果
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	result, err := (&Client{}).Invoke(context.Background(), req, RequestConfig{
+		Timeout: durationPtr(100 * time.Millisecond),
+		Retry:   &RetryPolicy{Max: 2, Backoff: ConstantBackoff(time.Second)},
+	})
+	require.NoError(t, err)
+	resp := result.(*Result).Response
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestInvoke_DiscardResponseBody(t *testing.T) {
+	body := &closeRecorder{Reader: strings.NewReader(`{"key":"NotFound","code":404000001,"msg":"not found"}`)}
+	fake := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusNotFound,
+			Header:     http.Header{"Content-Type": []string{MineApplicationJson}},
+			Body:       body,
+			Request:    req,
+		}, nil
+	})
+
+	// 不关心响应体时仍读完并关闭响应体，非 2xx 响应返回错误
+	req, _ := http.NewRequest(http.MethodDelete, "http://user-svc/users/1", nil)
+	err := Invoke(context.Background(), req, nil, &RequestConfig{Transport: fake}, &Client{}, nil)
+	assert.Error(t, err)
+	assert.True(t, body.closed)
+	assert.Zero(t, body.Len())
+}
+
+type closeRecorder struct {
+	*strings.Reader
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+func durationPtr(d time.Duration) *time.Duration {
+	return &d
+}
@@ -145,7 +145,7 @@ func executeResponseHandlers(ctx *gin.Context, result interface{}) {
 	if resp == nil {
 		return
 	}
-	ctx.Data(resp.Status(), resp.ContentType(), resp.Body())
+	writeResponse(ctx, resp.Status(), resp.ContentType(), resp.Body())
 }
 
 type CommonResponse struct {
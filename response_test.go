@@ -66,7 +66,7 @@ type testResponseHandler struct {
 	response     SuccessResponse
 }
 
-func (h *testResponseHandler) Handle(ctx *gin.Context, result interface{}) (bool, SuccessResponse) {
+func (h *testResponseHandler) Handle(ctx *gin.Context, result interface{}) (bool, Response) {
 	if h.shouldHandle {
 		return true, h.response
 	}
@@ -150,7 +150,7 @@ func TestDefaultResponseHandler_Handle_JSONResponse(t *testing.T) {
 			require.NotNil(t, resp)
 			assert.Equal(t, tt.expectedStatus, resp.Status())
 			if tt.validate != nil {
-				tt.validate(t, resp)
+				tt.validate(t, resp.(SuccessResponse))
 			}
 		})
 	}
@@ -228,7 +228,7 @@ func TestDefaultResponseHandler_Handle_MineDescriber(t *testing.T) {
 			require.NotNil(t, resp)
 			assert.Equal(t, tt.expectedStatus, resp.Status())
 			if tt.validate != nil {
-				tt.validate(t, resp)
+				tt.validate(t, resp.(SuccessResponse))
 			}
 		})
 	}
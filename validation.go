@@ -0,0 +1,41 @@
+package ginx
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/shrewx/ginx/internal/binding"
+)
+
+// RegisterValidation 注册自定义校验规则，注册后可在 validate 标签中使用
+// messages 为各语言的错误信息，key 为语言（zh、en），{0} 为字段名，{1} 为规则参数
+//
+//	ginx.RegisterValidation("even", func(fl validator.FieldLevel) bool {
+//		return fl.Field().Int()%2 == 0
+//	}, map[string]string{"zh": "{0}必须是偶数", "en": "{0} must be an even number"})
+func RegisterValidation(tag string, fn validator.Func, messages map[string]string) {
+	validate := validatorEngine()
+	if err := validate.RegisterValidation(tag, fn); err != nil {
+		panic(err)
+	}
+	for lang, text := range messages {
+		if err := binding.RegisterTranslation(validate, lang, tag, text); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// RegisterStructValidation 注册结构体级别的校验，在字段校验后执行，用于多个字段之间的校验
+// types 为需要校验的结构体，如操作符或 body 结构体；校验失败时通过 sl.ReportError 报告字段，structFieldName 需为结构体字段名
+//
+//	ginx.RegisterStructValidation(func(sl validator.StructLevel) {
+//		op := sl.Current().Interface().(ListEvents)
+//		if op.End.Before(op.Start) {
+//			sl.ReportError(op.End, "end", "End", "gtefield", "start")
+//		}
+//	}, ListEvents{})
+func RegisterStructValidation(fn validator.StructLevelFunc, types ...interface{}) {
+	validatorEngine().RegisterStructValidation(fn, types...)
+}
+
+func validatorEngine() *validator.Validate {
+	return binding.Validator.Engine().(*validator.Validate)
+}
@@ -0,0 +1,86 @@
+package ginx
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/shrewx/ginx/pkg/enum"
+	"github.com/shrewx/ginx/pkg/statuserror"
+	"github.com/stretchr/testify/require"
+)
+
+type testLevel int
+
+const (
+	TEST_LEVEL_UNKNOWN testLevel = iota
+	TEST_LEVEL__LOW
+	TEST_LEVEL__HIGH
+)
+
+func (v testLevel) Int() int { return int(v) }
+func (v testLevel) String() string {
+	switch v {
+	case TEST_LEVEL__LOW:
+		return "LOW"
+	case TEST_LEVEL__HIGH:
+		return "HIGH"
+	}
+	return "UNKNOWN"
+}
+func (v testLevel) Label() string       { return v.String() }
+func (v testLevel) Type() string        { return "TestLevel" }
+func (v testLevel) Values() []enum.Enum { return []enum.Enum{TEST_LEVEL__LOW, TEST_LEVEL__HIGH} }
+
+type testRangeRouter struct {
+	Start int       `in:"query" name:"start"`
+	End   int       `in:"query" name:"end"`
+	Step  int       `in:"query" name:"step" validate:"omitempty,test_even"`
+	Phone string    `in:"query" name:"phone" validate:"omitempty,phone"`
+	Level testLevel `in:"query" name:"level" validate:"omitempty,enum"`
+}
+
+func TestRegisterValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	RegisterValidation("test_even", func(fl validator.FieldLevel) bool {
+		return fl.Field().Int()%2 == 0
+	}, map[string]string{"zh": "{0}必须是偶数", "en": "{0} must be an even number"})
+	RegisterStructValidation(func(sl validator.StructLevel) {
+		router := sl.Current().Interface().(testRangeRouter)
+		if router.End < router.Start {
+			sl.ReportError(router.End, "end", "End", "gtefield", "start")
+		}
+	}, testRangeRouter{})
+
+	bind := func(lang, rawQuery string) []statuserror.FieldError {
+		router := &testRangeRouter{}
+		ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+		ctx.Request = httptest.NewRequest(http.MethodGet, "/range?"+rawQuery, nil)
+		ctx.Request.Header.Set(CurrentLangHeader(), lang)
+
+		err := ParameterBinding(ctx, router, parseOperatorType(reflect.TypeOf(router)))
+		if err == nil {
+			return nil
+		}
+		var validationErr *statuserror.ValidationError
+		require.True(t, errors.As(err, &validationErr))
+		return validationErr.Errors
+	}
+
+	require.Empty(t, bind("zh", "start=1&end=2&step=2&phone=13800138000&level=1"))
+
+	require.Equal(t, []statuserror.FieldError{
+		{Field: "step", In: "query", Rule: "test_even", Message: "step必须是偶数"},
+		{Field: "phone", In: "query", Rule: "phone", Message: "phone必须是有效的手机号"},
+		{Field: "level", In: "query", Rule: "enum", Message: "level必须是有效的枚举值"},
+	}, bind("zh", "step=3&phone=12345&level=3"))
+
+	require.Equal(t, []statuserror.FieldError{
+		{Field: "end", In: "query", Rule: "gtefield", Param: "start", Message: "end must be greater than or equal to start"},
+	}, bind("en", "start=5&end=1"))
+}